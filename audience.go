@@ -0,0 +1,241 @@
+package domain
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Platform represents the class of the viewer's user agent
+type Platform string
+
+const (
+	// PlatformDesktop is the platform of desktop browsers
+	PlatformDesktop Platform = "desktop"
+
+	// PlatformMobile is the platform of mobile phones
+	PlatformMobile Platform = "mobile"
+
+	// PlatformTablet is the platform of tablets
+	PlatformTablet Platform = "tablet"
+
+	// PlatformBot is the platform of crawlers and other bots
+	PlatformBot Platform = "bot"
+
+	// PlatformUnknown is the platform of the viewer without user agent
+	PlatformUnknown Platform = ""
+)
+
+// Audience represents the targeting criteria of the banner.
+// The viewer matches the audience if it matches all the criteria
+// which are set, and the empty audience matches every viewer
+type Audience struct {
+	// Locales lists the language tags, e.g. "bs" or "en-US", of which
+	// at least one must be accepted by the viewer. Base language
+	// matches all its regional variants, e.g. "en" matches "en-GB"
+	Locales []string
+
+	// Countries lists ISO 3166-1 alpha-2 country codes,
+	// of which one must be the viewer's country
+	Countries []string
+
+	// Platforms lists the platforms, of which
+	// one must be the viewer's platform
+	Platforms []Platform
+
+	// Authenticated, if set, requires the viewer to be
+	// authenticated or anonymous
+	Authenticated *bool
+
+	// Attributes lists the custom attributes the viewer must have
+	// with the same values. Attribute names are case-insensitive
+	Attributes map[string]string
+}
+
+// IsEmpty checks whether the audience has no criteria
+func (a *Audience) IsEmpty() bool {
+	return a == nil ||
+		len(a.Locales) == 0 &&
+			len(a.Countries) == 0 &&
+			len(a.Platforms) == 0 &&
+			a.Authenticated == nil &&
+			len(a.Attributes) == 0
+}
+
+// Matches checks whether the viewer is in the audience
+func (a *Audience) Matches(v Viewer) bool {
+	if a.IsEmpty() {
+		return true
+	}
+
+	if len(a.Locales) > 0 && !matchesLocale(a.Locales, v.Locales()) {
+		return false
+	}
+	if len(a.Countries) > 0 && !containsFold(a.Countries, v.Country) {
+		return false
+	}
+	if len(a.Platforms) > 0 && !containsPlatform(a.Platforms, v.Platform()) {
+		return false
+	}
+	if a.Authenticated != nil && *a.Authenticated != v.IsAuthenticated() {
+		return false
+	}
+	for k, value := range a.Attributes {
+		if actual, ok := attribute(v.Attributes, k); !ok || actual != value {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Clone returns the deep copy of the audience
+func (a *Audience) Clone() *Audience {
+	if a == nil {
+		return nil
+	}
+
+	c := &Audience{
+		Locales:   append([]string(nil), a.Locales...),
+		Countries: append([]string(nil), a.Countries...),
+		Platforms: append([]Platform(nil), a.Platforms...),
+	}
+	if a.Authenticated != nil {
+		auth := *a.Authenticated
+		c.Authenticated = &auth
+	}
+	if a.Attributes != nil {
+		c.Attributes = make(map[string]string, len(a.Attributes))
+		for k, v := range a.Attributes {
+			c.Attributes[k] = v
+		}
+	}
+
+	return c
+}
+
+func matchesLocale(targeted, accepted []string) bool {
+	for _, t := range targeted {
+		for _, a := range accepted {
+			if strings.EqualFold(t, a) {
+				return true
+			}
+
+			// base language matches its regional variants
+			if !strings.Contains(t, "-") {
+				if base, _, _ := strings.Cut(a, "-"); strings.EqualFold(t, base) {
+					return true
+				}
+			}
+		}
+	}
+
+	return false
+}
+
+// attribute returns the value of the attribute, whose name is
+// matched case-insensitively, as it is sent as the header
+func attribute(attrs map[string]string, name string) (string, bool) {
+	if value, ok := attrs[name]; ok {
+		return value, true
+	}
+	for k, value := range attrs {
+		if strings.EqualFold(k, name) {
+			return value, true
+		}
+	}
+
+	return "", false
+}
+
+func containsFold(values []string, s string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func containsPlatform(platforms []Platform, p Platform) bool {
+	for _, v := range platforms {
+		if v == p {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Locales returns the language tags accepted by the viewer,
+// ordered by preference, as listed in Accept-Language header
+func (v Viewer) Locales() []string {
+	type locale struct {
+		tag string
+		q   float64
+	}
+
+	var locales []locale
+	for _, part := range strings.Split(v.Headers["Accept-Language"], ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		tag = strings.TrimSpace(tag)
+		if tag == "" || tag == "*" {
+			continue
+		}
+
+		q := 1.0
+		if qv, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(qv, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		if q <= 0 {
+			continue
+		}
+
+		locales = append(locales, locale{tag: tag, q: q})
+	}
+
+	sort.SliceStable(locales, func(i, j int) bool {
+		return locales[i].q > locales[j].q
+	})
+
+	tags := make([]string, 0, len(locales))
+	for _, l := range locales {
+		tags = append(tags, l.tag)
+	}
+
+	return tags
+}
+
+// Platform classifies the viewer's user agent
+func (v Viewer) Platform() Platform {
+	ua := strings.ToLower(v.Headers["User-Agent"])
+
+	switch {
+	case ua == "":
+		return PlatformUnknown
+	case strings.Contains(ua, "bot") ||
+		strings.Contains(ua, "crawler") ||
+		strings.Contains(ua, "spider"):
+		return PlatformBot
+	case strings.Contains(ua, "ipad") ||
+		strings.Contains(ua, "tablet") ||
+		strings.Contains(ua, "android") && !strings.Contains(ua, "mobile"):
+		return PlatformTablet
+	case strings.Contains(ua, "mobile") ||
+		strings.Contains(ua, "iphone") ||
+		strings.Contains(ua, "android"):
+		return PlatformMobile
+	}
+
+	return PlatformDesktop
+}
+
+// IsAuthenticated checks whether the viewer is authenticated
+func (v Viewer) IsAuthenticated() bool {
+	return v.UserID != ""
+}
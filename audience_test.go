@@ -0,0 +1,148 @@
+package domain_test
+
+import (
+	"testing"
+
+	domain "github.com/DzananGanic/banner"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAudienceMatches(t *testing.T) {
+	yes := true
+	no := false
+
+	viewer := domain.Viewer{
+		UserID: "42",
+		Headers: map[string]string{
+			"Accept-Language": "bs-BA,bs;q=0.9,en;q=0.5",
+			"User-Agent":      "Mozilla/5.0 (iPhone; CPU iPhone OS 17_0 like Mac OS X) Mobile/15E148",
+		},
+		Country:    "BA",
+		Attributes: map[string]string{"plan": "premium"},
+	}
+
+	cases := []struct {
+		name     string
+		audience *domain.Audience
+		want     bool
+	}{
+		{
+			name:     "test nil audience matches everyone",
+			audience: nil,
+			want:     true,
+		},
+		{
+			name:     "test base language matches regional variant",
+			audience: &domain.Audience{Locales: []string{"BS"}},
+			want:     true,
+		},
+		{
+			name:     "test regional variant does not match other region",
+			audience: &domain.Audience{Locales: []string{"en-US"}},
+			want:     false,
+		},
+		{
+			name:     "test country",
+			audience: &domain.Audience{Countries: []string{"hr", "ba"}},
+			want:     true,
+		},
+		{
+			name:     "test other country",
+			audience: &domain.Audience{Countries: []string{"HR"}},
+			want:     false,
+		},
+		{
+			name:     "test platform",
+			audience: &domain.Audience{Platforms: []domain.Platform{domain.PlatformMobile}},
+			want:     true,
+		},
+		{
+			name:     "test other platform",
+			audience: &domain.Audience{Platforms: []domain.Platform{domain.PlatformDesktop}},
+			want:     false,
+		},
+		{
+			name:     "test authenticated",
+			audience: &domain.Audience{Authenticated: &yes},
+			want:     true,
+		},
+		{
+			name:     "test anonymous only",
+			audience: &domain.Audience{Authenticated: &no},
+			want:     false,
+		},
+		{
+			name:     "test attributes",
+			audience: &domain.Audience{Attributes: map[string]string{"plan": "premium"}},
+			want:     true,
+		},
+		{
+			name:     "test attribute names are case-insensitive",
+			audience: &domain.Audience{Attributes: map[string]string{"Plan": "premium"}},
+			want:     true,
+		},
+		{
+			name:     "test missing attribute",
+			audience: &domain.Audience{Attributes: map[string]string{"cohort": "beta"}},
+			want:     false,
+		},
+		{
+			name: "test all criteria must match",
+			audience: &domain.Audience{
+				Locales:   []string{"bs"},
+				Countries: []string{"HR"},
+			},
+			want: false,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			assert.Equal(t, c.want, c.audience.Matches(viewer))
+		})
+	}
+}
+
+func TestViewerLocales(t *testing.T) {
+	v := domain.Viewer{Headers: map[string]string{
+		"Accept-Language": "en;q=0.5, bs-BA, *;q=0.1, de;q=0, hr;q=0.8",
+	}}
+
+	assert.Equal(t, []string{"bs-BA", "hr", "en"}, v.Locales())
+	assert.Empty(t, domain.Viewer{}.Locales())
+}
+
+func TestViewerPlatform(t *testing.T) {
+	cases := []struct {
+		ua   string
+		want domain.Platform
+	}{
+		{ua: "", want: domain.PlatformUnknown},
+		{ua: "Mozilla/5.0 (Windows NT 10.0; Win64; x64) Chrome/120.0", want: domain.PlatformDesktop},
+		{ua: "Mozilla/5.0 (Linux; Android 14; Pixel 8) Mobile Safari/537.36", want: domain.PlatformMobile},
+		{ua: "Mozilla/5.0 (Linux; Android 13; SM-X700) Safari/537.36", want: domain.PlatformTablet},
+		{ua: "Mozilla/5.0 (iPad; CPU OS 17_0 like Mac OS X)", want: domain.PlatformTablet},
+		{ua: "Googlebot/2.1 (+http://www.google.com/bot.html)", want: domain.PlatformBot},
+	}
+
+	for _, c := range cases {
+		t.Run(c.ua, func(t *testing.T) {
+			v := domain.Viewer{Headers: map[string]string{"User-Agent": c.ua}}
+			assert.Equal(t, c.want, v.Platform())
+		})
+	}
+}
+
+func TestBannerClone(t *testing.T) {
+	b := domain.Banner{
+		ID:       1,
+		Audience: &domain.Audience{Locales: []string{"bs"}},
+	}
+
+	c := b.Clone()
+	c.Audience.Locales[0] = "en"
+
+	assert.Equal(t, "bs", b.Audience.Locales[0])
+	assert.True(t, b.IsTargeted())
+	assert.False(t, (&domain.Banner{Audience: &domain.Audience{}}).IsTargeted())
+}
@@ -0,0 +1,84 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// AuditAction is the kind of the change made to the banner
+type AuditAction string
+
+const (
+	// AuditCreate records the banner creation
+	AuditCreate AuditAction = "create"
+
+	// AuditUpdate records the update of the banner
+	AuditUpdate AuditAction = "update"
+
+	// AuditDelete records the banner deletion
+	AuditDelete AuditAction = "delete"
+
+	// AuditRevert records the update of the banner
+	// which restores one of its past revisions
+	AuditRevert AuditAction = "revert"
+)
+
+// AuditEntry records a single change made to the banner
+type AuditEntry struct {
+	// ID is assigned by the audit store, and orders
+	// the entries in the order they were recorded
+	ID int64
+
+	BannerID BannerID
+	Action   AuditAction
+
+	// Actor is the one who made the change, see WithActor,
+	// it is empty if the actor is not known
+	Actor string
+
+	At time.Time
+
+	// Before and After are the banner snapshots around the
+	// change, Before is nil on create and After is nil on delete
+	Before *Banner
+	After  *Banner
+}
+
+// Clone returns the copy of the entry which shares
+// no memory with the original, like Banner.Clone
+func (e AuditEntry) Clone() AuditEntry {
+	e.Before = cloneBanner(e.Before)
+	e.After = cloneBanner(e.After)
+	return e
+}
+
+func cloneBanner(b *Banner) *Banner {
+	if b == nil {
+		return nil
+	}
+	c := b.Clone()
+	return &c
+}
+
+// AuditStore is the repository of the changes made to banners.
+// Record assigns the entry ID, and ListForBanner returns the
+// entries of the banner in the order they were recorded
+type AuditStore interface {
+	Record(context.Context, AuditEntry) error
+	ListForBanner(context.Context, BannerID) ([]AuditEntry, error)
+}
+
+type actorKey struct{}
+
+// WithActor returns the context carrying the actor
+// the changes made within the context are attributed to
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFromContext returns the actor carried by
+// the context, or empty string if there is none
+func ActorFromContext(ctx context.Context) string {
+	actor, _ := ctx.Value(actorKey{}).(string)
+	return actor
+}
@@ -0,0 +1,30 @@
+package domain_test
+
+import (
+	"context"
+	"testing"
+
+	domain "github.com/DzananGanic/banner"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestActorFromContext(t *testing.T) {
+	ctx := context.Background()
+	assert.Equal(t, "", domain.ActorFromContext(ctx))
+	assert.Equal(t, "alice", domain.ActorFromContext(domain.WithActor(ctx, "alice")))
+}
+
+func TestAuditEntryClone(t *testing.T) {
+	e := domain.AuditEntry{
+		Action: domain.AuditUpdate,
+		Before: &domain.Banner{Name: "before"},
+		After:  &domain.Banner{Name: "after"},
+	}
+
+	c := e.Clone()
+	c.Before.Name = "modified"
+	c.After.Name = "modified"
+
+	assert.Equal(t, "before", e.Before.Name)
+	assert.Equal(t, "after", e.After.Name)
+}
@@ -24,21 +24,149 @@ type Banner struct {
 	CreatedAt             time.Time
 	ScheduledDisplayingAt time.Time
 	ExpiresAt             time.Time
+
+	// Weight is the share of traffic the banner gets when
+	// rotating among banners displayed at the same time,
+	// non-positive weight counts as DefaultWeight
+	Weight int
+
+	// Priority makes banners with higher priority displayed
+	// instead of the ones with lower priority
+	Priority int
+
+	// Audience restricts the viewers the banner is displayed to,
+	// the banner without one is displayed to everyone
+	Audience *Audience
+
+	// TimeZone is IANA name of the time zone the banner is
+	// scheduled in, e.g. Europe/Sarajevo. The instants are
+	// stored in UTC regardless, the time zone only resolves
+	// the wall clock times, see WallClock. Empty is UTC
+	TimeZone string
+
+	// Recurrence restricts displaying of the banner to the
+	// recurring windows within its display period, the banner
+	// without one is displayed throughout the period
+	Recurrence *Recurrence
+
+	// Content is what is rendered for the banner, in its locales
+	Content *Content
+
+	// Version is incremented by the repository on every save of
+	// the banner, and guards the update against overwriting the
+	// changes made since the banner was fetched, see BannerDB
+	Version int
+}
+
+// IsTargeted checks whether the banner is displayed
+// only to the viewers in its audience
+func (b *Banner) IsTargeted() bool {
+	return !b.Audience.IsEmpty()
+}
+
+// Clone returns the copy of the banner which shares no
+// memory with the original, unlike the plain assignment
+func (b Banner) Clone() Banner {
+	b.Audience = b.Audience.Clone()
+	b.Recurrence = b.Recurrence.Clone()
+	b.Content = b.Content.Clone()
+	return b
+}
+
+// DefaultWeight is the rotation weight of the banner without one
+const DefaultWeight = 1
+
+// DisplayWeight returns the rotation weight of the banner
+func (b *Banner) DisplayWeight() int {
+	if b.Weight <= 0 {
+		return DefaultWeight
+	}
+	return b.Weight
 }
 
 // IsInDisplayPeriod checks whether the banner is in display period
+// The instants are compared regardless of their time zones, while
+// the recurring windows are resolved in the banner time zone
 func (b *Banner) IsInDisplayPeriod(now time.Time) bool {
-	return now.In(time.Local).After(b.ScheduledDisplayingAt.In(time.Local)) &&
-		now.Before(b.ExpiresAt.In(time.Local))
+	if !now.After(b.ScheduledDisplayingAt) || !now.Before(b.ExpiresAt) {
+		return false
+	}
+	if b.Recurrence == nil {
+		return true
+	}
+
+	return b.Recurrence.IsActive(now, b.ScheduledDisplayingAt, b.location())
+}
+
+// CheckVersion checks whether the banner can be saved over the stored one,
+// which is nil if there is none, see BannerDB for the version semantics
+func (b *Banner) CheckVersion(stored *Banner) error {
+	if b.Version == 0 {
+		return nil
+	}
+
+	var actual int
+	if stored != nil {
+		actual = stored.Version
+	}
+	if b.Version != actual {
+		return &VersionConflictError{ID: b.ID, Expected: b.Version, Actual: actual}
+	}
+
+	return nil
+}
+
+// NextVersion returns the version the banner is saved with
+// over the stored one, which is nil if there is none
+func NextVersion(stored *Banner) int {
+	if stored == nil {
+		return 1
+	}
+	return stored.Version + 1
 }
 
 // IsExpired checks banner expiration date and returns
 // boolean on whether the banner is expired
 func (b *Banner) IsExpired(now time.Time) bool {
-	return now.In(time.Local).After(b.ExpiresAt.In(time.Local))
+	return now.After(b.ExpiresAt)
+}
+
+// BannerStatus represents the displaying status of the banner
+type BannerStatus string
+
+const (
+	// BannerScheduled is the status of the banner
+	// whose display period has not started yet
+	BannerScheduled BannerStatus = "scheduled"
+
+	// BannerActive is the status of the banner
+	// which is in its display period
+	BannerActive BannerStatus = "active"
+
+	// BannerExpired is the status of the banner
+	// whose display period is over
+	BannerExpired BannerStatus = "expired"
+)
+
+// Status returns the displaying status of the banner at the given time
+func (b *Banner) Status(now time.Time) BannerStatus {
+	if b.IsInDisplayPeriod(now) {
+		return BannerActive
+	}
+	if !now.Before(b.ExpiresAt) {
+		return BannerExpired
+	}
+
+	return BannerScheduled
 }
 
 // BannerDB represents Banner entity repository
+//
+// Save of the banner with non-zero version is compare-and-swap, it
+// succeeds only if the stored banner has the same version and returns
+// VersionConflictError otherwise. The banner with zero version is saved
+// unconditionally. Either way the stored version is incremented, so the
+// new banner is stored with version 1
 type BannerDB interface {
 	Save(Banner) (BannerID, error)
 	FetchForID(BannerID) (*Banner, error)
@@ -47,13 +175,50 @@ type BannerDB interface {
 }
 
 // ActiveBannerProvider is the repository which
-// sets and gets active banner.
+// sets and gets active banner. Get returns nil banner
+// if no banner has been set as active yet, or if
+// the active banner has been cleared
 type ActiveBannerProvider interface {
 	Set(Banner) error
 	Get() (*Banner, error)
+	Clear() error
+}
+
+// PreviewPolicy decides whether the given IP address is allowed
+// to see banners before their display period starts
+type PreviewPolicy interface {
+	Allows(ip string) bool
+}
+
+// Clock provides the current time, so that time
+// dependent behaviour can be controlled in tests
+type Clock interface {
+	Now() time.Time
+}
+
+// Viewer represents the one the banner is displayed to
+type Viewer struct {
+	// IP is the address of the viewer's client
+	IP string
+
+	// UserID identifies the authenticated viewer,
+	// and is empty for anonymous ones
+	UserID string
+
+	// Headers holds the request headers relevant
+	// to the display decision, e.g. Accept-Language
+	Headers map[string]string
+
+	// Country is ISO 3166-1 alpha-2 code of the
+	// viewer's country, empty if it is unknown
+	Country string
+
+	// Attributes holds the custom attributes of the
+	// viewer which banners may be targeted by
+	Attributes map[string]string
 }
 
-// BannerDisplayer returns the optimal banner to be shown
+// BannerDisplayer returns the optimal banner to be shown to the viewer
 type BannerDisplayer interface {
-	DisplayBanner() (*Banner, error)
+	DisplayBanner(Viewer) (*Banner, error)
 }
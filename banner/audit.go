@@ -0,0 +1,75 @@
+package banner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	domain "github.com/DzananGanic/banner"
+)
+
+// ErrAuditDisabled is returned by Audit when the
+// service was created without the audit store
+var ErrAuditDisabled = errors.New("audit log is disabled")
+
+// record records the change of the banner, attributing it to the actor
+// from the context. Unless the service has the transactor, the change is
+// already made when it is recorded, so the error means the change
+// is missing from the audit log
+func (s *Service) record(ctx context.Context, action domain.AuditAction, id domain.BannerID, before, after *domain.Banner) error {
+	if s.audit == nil {
+		return nil
+	}
+
+	err := s.audit.Record(ctx, domain.AuditEntry{
+		BannerID: id,
+		Action:   action,
+		Actor:    domain.ActorFromContext(ctx),
+		At:       s.clock.Now().UTC(),
+		Before:   before,
+		After:    after,
+	})
+	if err != nil {
+		return fmt.Errorf("recording %s of banner %d: %w", action, id, err)
+	}
+
+	return nil
+}
+
+// AuditReq represents the request to list the changes made to the banner
+type AuditReq struct {
+	BannerID domain.BannerID
+}
+
+// Validate validates AuditReq and returns error if the validation fails
+func (req *AuditReq) Validate() error {
+	if req.BannerID == 0 {
+		return domain.NewValidationError("banner_id", "must be set")
+	}
+	return nil
+}
+
+// AuditResp represents the banner audit log response
+type AuditResp struct {
+	// Entries are ordered from the oldest change to the newest one
+	Entries []domain.AuditEntry
+}
+
+// Audit use case lists the changes made to the banner, including
+// the ones made to the banner which has been deleted since
+func (s *Service) Audit(ctx context.Context, req *AuditReq) (*AuditResp, error) {
+	err := req.Validate()
+	if err != nil {
+		return nil, err
+	}
+	if s.audit == nil {
+		return nil, ErrAuditDisabled
+	}
+
+	entries, err := s.audit.ListForBanner(ctx, req.BannerID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &AuditResp{Entries: entries}, nil
+}
@@ -5,6 +5,11 @@ package banner
 import (
 	"context"
 	"fmt"
+	"log"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
 	"time"
 
 	domain "github.com/DzananGanic/banner"
@@ -13,18 +18,101 @@ import (
 // New creates new banner application service
 func New(
 	bdb domain.BannerDB,
+	aProvider domain.ActiveBannerProvider,
 	disp domain.BannerDisplayer,
+	clock domain.Clock,
+	opts ...Option,
 ) *Service {
-	return &Service{
-		banners: bdb,
-		disp:    disp,
+	s := &Service{
+		banners: domain.BannerDBWithContext(bdb),
+		active:  domain.ActiveBannerProviderWithContext(aProvider),
+		disp:    domain.BannerDisplayerWithContext(disp),
+		clock:   clock,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// Option configures the optional dependencies of the service
+type Option func(*Service)
+
+// WithAuditStore makes the service record every banner
+// change it makes to the given audit store
+func WithAuditStore(store domain.AuditStore) Option {
+	return func(s *Service) {
+		s.audit = store
+	}
+}
+
+// WithEventEmitter makes the service emit the event
+// for every banner change it makes to the given emitter
+func WithEventEmitter(events domain.EventEmitter) Option {
+	return func(s *Service) {
+		s.events = events
+	}
+}
+
+// WithTransactor makes the service save the banner change together
+// with its audit entry and event in the transaction, so that either
+// all of them are stored or none is. The repositories, audit store
+// and event emitter are expected to take part in its transactions
+func WithTransactor(tx domain.Transactor) Option {
+	return func(s *Service) {
+		s.tx = tx
 	}
 }
 
 // Service represents banner application service
 type Service struct {
-	banners domain.BannerDB
-	disp    domain.BannerDisplayer
+	banners domain.BannerDBContext
+	active  domain.ActiveBannerProviderContext
+	disp    domain.BannerDisplayerContext
+	clock   domain.Clock
+
+	// audit is nil if the changes are not audited
+	audit domain.AuditStore
+
+	// events is nil if no events are emitted
+	events domain.EventEmitter
+
+	// tx is nil if the changes are saved without transactions
+	tx domain.Transactor
+
+	// overlapMu serializes the writes checking for the overlaps
+	overlapMu sync.Mutex
+}
+
+// withinTx runs the function in the transaction if the service has
+// the transactor, and runs it with the given context otherwise
+func (s *Service) withinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if s.tx == nil {
+		return fn(ctx)
+	}
+	return s.tx.WithinTx(ctx, fn)
+}
+
+// emit emits the event of the banner change, attributing it to the actor
+// from the context. Like record, it is called once the change is made
+func (s *Service) emit(ctx context.Context, typ domain.EventType, id domain.BannerID, b *domain.Banner) error {
+	if s.events == nil {
+		return nil
+	}
+
+	err := s.events.Emit(ctx, domain.Event{
+		Type:     typ,
+		BannerID: id,
+		At:       s.clock.Now().UTC(),
+		Actor:    domain.ActorFromContext(ctx),
+		Banner:   b,
+	})
+	if err != nil {
+		return fmt.Errorf("emitting %s of banner %d: %w", typ, id, err)
+	}
+
+	return nil
 }
 
 // CreateReq represents create banner request
@@ -32,22 +120,64 @@ type CreateReq struct {
 	Name                  string
 	ScheduledDisplayingAt time.Time
 	ExpiresAt             time.Time
+	Weight                int
+	Priority              int
+	Audience              *domain.Audience
+
+	// TimeZone is IANA name of the banner time zone, UTC if empty
+	TimeZone string
+
+	// ScheduledDisplayingAtWallClock and ExpiresAtWallClock, when set,
+	// are resolved in the banner time zone and used instead of
+	// ScheduledDisplayingAt and ExpiresAt instants
+	ScheduledDisplayingAtWallClock *domain.WallClock
+	ExpiresAtWallClock             *domain.WallClock
+
+	// Recurrence restricts displaying to the recurring windows
+	Recurrence *domain.Recurrence
+
+	Content *domain.Content
+
+	// Overlap controls how the overlaps with other banners are treated
+	Overlap OverlapMode
 }
 
 // Validate validates CreateReq and returns error if the validation fails
 func (req *CreateReq) Validate() error {
-	if (req.Name == "") || (req.ExpiresAt == time.Time{}) || (req.ScheduledDisplayingAt == time.Time{}) {
-		return fmt.Errorf("you must set name, scheduled displaying at, and expires at")
+	var verr domain.ValidationError
+	if req.Name == "" {
+		verr.Add("name", "must not be empty")
 	}
-	return nil
+	if req.ScheduledDisplayingAt.IsZero() && req.ScheduledDisplayingAtWallClock == nil {
+		verr.Add("scheduled_displaying_at", "must be set")
+	}
+	if req.ExpiresAt.IsZero() && req.ExpiresAtWallClock == nil {
+		verr.Add("expires_at", "must be set")
+	}
+	verr.Check("expires_at", validateScheduleOrder(req.ScheduledDisplayingAt, req.ExpiresAt))
+	if req.Weight < 0 {
+		verr.Add("weight", "must not be negative")
+	}
+	verr.Check("overlap", req.Overlap.Validate())
+	verr.Check("time_zone", validateTimeZone(req.TimeZone))
+	verr.Check("recurrence", validateRecurrence(req.Recurrence))
+	verr.Check("content", validateContent(req.Content))
+	verr.Check("audience", validateAudience(req.Audience))
+
+	return verr.Err()
 }
 
 // CreateResp represents create banner response
 type CreateResp struct {
-	ID domain.BannerID
+	ID      domain.BannerID
+	Version int
+
+	// Conflicts lists the overlaps with other banners in OverlapWarn mode
+	Conflicts []Conflict
 }
 
-// Create use case creates a new banner and saves it to the repository
+// Create use case creates a new banner and saves it to the repository,
+// and clears the active banner if the new banner may pre-empt it
 func (s *Service) Create(ctx context.Context, req *CreateReq) (*CreateResp, error) {
 	err := req.Validate()
 	if err != nil {
@@ -56,17 +186,57 @@ func (s *Service) Create(ctx context.Context, req *CreateReq) (*CreateResp, erro
 
 	b := domain.Banner{
 		Name:                  req.Name,
+		CreatedAt:             s.clock.Now(),
 		ScheduledDisplayingAt: req.ScheduledDisplayingAt,
 		ExpiresAt:             req.ExpiresAt,
+		Weight:                req.Weight,
+		Priority:              req.Priority,
+		Audience:              normalizeAudience(req.Audience),
+		TimeZone:              req.TimeZone,
+		Recurrence:            normalizeRecurrence(req.Recurrence),
+		Content:               normalizeContent(req.Content),
 	}
 
-	id, err := s.banners.Save(b)
+	err = resolveWallClocks(&b, req.ScheduledDisplayingAtWallClock, req.ExpiresAtWallClock)
 	if err != nil {
 		return nil, err
 	}
+	normalizeTimes(&b)
+	if err := validateBanner(&b); err != nil {
+		return nil, err
+	}
+
+	var conflicts []Conflict
+	unlock := s.lockOverlap(req.Overlap)
+	err = s.withinTx(ctx, func(ctx context.Context) error {
+		var err error
+		conflicts, err = s.checkOverlap(ctx, &b, req.Overlap)
+		if err != nil {
+			return err
+		}
+
+		id, err := s.banners.SaveContext(ctx, b)
+		if err != nil {
+			return err
+		}
+
+		b.ID = id
+		b.Version = domain.NextVersion(nil)
+		if err := s.record(ctx, domain.AuditCreate, id, nil, &b); err != nil {
+			return err
+		}
+		return s.emit(ctx, domain.EventBannerCreated, id, &b)
+	})
+	unlock()
+	if err != nil {
+		return nil, err
+	}
+	s.invalidateActive(ctx, &b)
 
 	return &CreateResp{
-		ID: id,
+		ID:        b.ID,
+		Version:   b.Version,
+		Conflicts: conflicts,
 	}, nil
 }
 
@@ -77,28 +247,295 @@ type UpdateReq struct {
 	Name                  *string
 	ScheduledDisplayingAt *time.Time
 	ExpiresAt             *time.Time
+	Weight                *int
+	Priority              *int
+
+	// Audience replaces the audience of the banner,
+	// the empty audience makes the banner untargeted
+	Audience *domain.Audience
+
+	// TimeZone changes the banner time zone, which does
+	// not move the banner instants already scheduled
+	TimeZone *string
+
+	// ScheduledDisplayingAtWallClock and ExpiresAtWallClock, when set,
+	// are resolved in the banner time zone, after it is changed
+	ScheduledDisplayingAtWallClock *domain.WallClock
+	ExpiresAtWallClock             *domain.WallClock
+
+	// Recurrence replaces the recurrence of the banner, the
+	// recurrence without frequency makes the banner displayed
+	// throughout its display period
+	Recurrence *domain.Recurrence
+
+	// Content replaces the banner content, the
+	// content without variants removes it
+	Content *domain.Content
+
+	// Overlap controls how the overlaps with other banners are treated
+	Overlap OverlapMode
+
+	// Version is the banner version the update is based on, the
+	// update fails with domain.VersionConflictError if the banner
+	// has changed since. Zero updates whichever version is stored
+	Version int
 }
 
 // Validate validates UpdateReq and returns error if the validation fails
 func (req *UpdateReq) Validate() error {
+	var verr domain.ValidationError
 	if req.ID == 0 {
-		return fmt.Errorf("you must have banner id")
+		verr.Add("id", "must be set")
+	}
+	if req.Name != nil && *req.Name == "" {
+		verr.Add("name", "must not be empty")
+	}
+	if req.ScheduledDisplayingAt != nil && req.ExpiresAt != nil {
+		verr.Check("expires_at", validateScheduleOrder(*req.ScheduledDisplayingAt, *req.ExpiresAt))
+	}
+	if req.Weight != nil && *req.Weight < 0 {
+		verr.Add("weight", "must not be negative")
+	}
+	verr.Check("overlap", req.Overlap.Validate())
+	if req.TimeZone != nil {
+		verr.Check("time_zone", validateTimeZone(*req.TimeZone))
+	}
+	verr.Check("recurrence", validateRecurrence(req.Recurrence))
+	verr.Check("content", validateContent(req.Content))
+	verr.Check("audience", validateAudience(req.Audience))
+	if req.Version < 0 {
+		verr.Add("version", "must not be negative")
+	}
+
+	return verr.Err()
+}
+
+// validateTimeZone validates IANA time zone name
+func validateTimeZone(tz string) error {
+	_, err := (&domain.Banner{TimeZone: tz}).Location()
+	return err
+}
+
+// resolveWallClocks sets the banner instants from the
+// wall clock times which are set, in the banner time zone
+func resolveWallClocks(b *domain.Banner, scheduledDisplayingAt, expiresAt *domain.WallClock) error {
+	if scheduledDisplayingAt == nil && expiresAt == nil {
+		return nil
+	}
+
+	loc, err := b.Location()
+	if err != nil {
+		return domain.NewValidationError("time_zone", "%v", err)
+	}
+
+	if scheduledDisplayingAt != nil {
+		b.ScheduledDisplayingAt = scheduledDisplayingAt.In(loc)
+	}
+	if expiresAt != nil {
+		b.ExpiresAt = expiresAt.In(loc)
+	}
+
+	return nil
+}
+
+// validateRecurrence validates the recurring display windows,
+// the recurrence without frequency stands for no recurrence
+func validateRecurrence(r *domain.Recurrence) error {
+	if r == nil || r.Frequency == "" {
+		return nil
+	}
+
+	switch r.Frequency {
+	case domain.Daily:
+		if len(r.Weekdays) > 0 {
+			return fmt.Errorf("weekdays are only allowed in weekly recurrence")
+		}
+	case domain.Weekly:
+		if len(r.Weekdays) == 0 {
+			return fmt.Errorf("weekly recurrence must have weekdays")
+		}
+	default:
+		return fmt.Errorf("unknown frequency %q", r.Frequency)
+	}
+
+	if r.Interval < 0 {
+		return fmt.Errorf("interval must not be negative")
+	}
+	if r.StartHour < 0 || r.StartHour > 23 || r.StartMinute < 0 || r.StartMinute > 59 {
+		return fmt.Errorf("start must be valid time of day")
+	}
+	// windows of the consecutive days must not overlap
+	if r.Duration <= 0 || r.Duration > 24*time.Hour {
+		return fmt.Errorf("duration must be positive and at most 24h")
+	}
+
+	return nil
+}
+
+// normalizeRecurrence returns the copy of the recurrence,
+// or nil if the recurrence has no frequency
+func normalizeRecurrence(r *domain.Recurrence) *domain.Recurrence {
+	if r == nil || r.Frequency == "" {
+		return nil
+	}
+	return r.Clone()
+}
+
+// validateContent validates the banner content, in each of its
+// locales filled in from the fallback one, as it is displayed
+func validateContent(c *domain.Content) error {
+	if c.IsEmpty() {
+		return nil
+	}
+
+	if _, ok := c.Variants[c.FallbackLocale]; !ok {
+		return fmt.Errorf("must have the variant of fallback locale %q", c.FallbackLocale)
 	}
+
+	for locale := range c.Variants {
+		if locale == "" {
+			return fmt.Errorf("locale must not be empty")
+		}
+
+		_, v, _ := c.Localize([]string{locale})
+		if v.Title == "" && v.Body == "" {
+			return fmt.Errorf("%q must have title or body", locale)
+		}
+		if v.ImageURL != "" && v.AltText == "" {
+			return fmt.Errorf("%q image must have alt text", locale)
+		}
+		if err := validateContentURL(v.ImageURL); err != nil {
+			return fmt.Errorf("%q image %v", locale, err)
+		}
+		if err := validateContentURL(v.LinkURL); err != nil {
+			return fmt.Errorf("%q link %v", locale, err)
+		}
+	}
+
 	return nil
 }
 
-// Update use case updates the existing banner and saves it to the repository
-func (s *Service) Update(ctx context.Context, req *UpdateReq) error {
+// validateContentURL validates the URL the client loads or
+// navigates to, which must be absolute http or https one
+func validateContentURL(s string) error {
+	if s == "" {
+		return nil
+	}
+
+	u, err := url.Parse(s)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%q must be absolute http or https URL", s)
+	}
+
+	return nil
+}
+
+// normalizeContent returns the copy of the content,
+// or nil if the content has no variants
+func normalizeContent(c *domain.Content) *domain.Content {
+	if c.IsEmpty() {
+		return nil
+	}
+	return c.Clone()
+}
+
+// normalizeTimes normalizes the banner instants to UTC,
+// as repositories require, see Banner.CheckNormalized
+func normalizeTimes(b *domain.Banner) {
+	b.CreatedAt = b.CreatedAt.UTC()
+	b.ScheduledDisplayingAt = b.ScheduledDisplayingAt.UTC()
+	b.ExpiresAt = b.ExpiresAt.UTC()
+}
+
+// validateAudience validates the targeting criteria
+func validateAudience(a *domain.Audience) error {
+	if a == nil {
+		return nil
+	}
+
+	for _, c := range a.Countries {
+		if len(c) != 2 {
+			return fmt.Errorf("country %q must be ISO 3166-1 alpha-2 code", c)
+		}
+	}
+	for _, p := range a.Platforms {
+		switch p {
+		case domain.PlatformDesktop, domain.PlatformMobile, domain.PlatformTablet, domain.PlatformBot:
+		default:
+			return fmt.Errorf("unknown platform %q", p)
+		}
+	}
+	for _, l := range a.Locales {
+		if l == "" {
+			return fmt.Errorf("locale must not be empty")
+		}
+	}
+	names := make(map[string]string, len(a.Attributes))
+	for k := range a.Attributes {
+		if k == "" {
+			return fmt.Errorf("attribute name must not be empty")
+		}
+		if other, ok := names[strings.ToLower(k)]; ok {
+			return fmt.Errorf("attributes %q and %q differ only in case", other, k)
+		}
+		names[strings.ToLower(k)] = k
+	}
+
+	return nil
+}
+
+// normalizeAudience returns the copy of the audience with lowercased
+// attribute names, or nil if the audience has no criteria
+func normalizeAudience(a *domain.Audience) *domain.Audience {
+	if a.IsEmpty() {
+		return nil
+	}
+
+	c := a.Clone()
+	if c.Attributes != nil {
+		c.Attributes = make(map[string]string, len(a.Attributes))
+		for k, v := range a.Attributes {
+			c.Attributes[strings.ToLower(k)] = v
+		}
+	}
+
+	return c
+}
+
+// UpdateResp represents update banner response
+type UpdateResp struct {
+	// Version is the version of the updated banner
+	Version int
+
+	// Conflicts lists the overlaps with other banners in OverlapWarn mode
+	Conflicts []Conflict
+}
+
+// Update use case updates the existing banner and saves it to the repository,
+// and clears the active banner if the update may change which one it is
+func (s *Service) Update(ctx context.Context, req *UpdateReq) (*UpdateResp, error) {
+	return s.update(ctx, req, domain.AuditUpdate)
+}
+
+// update is Update which records the change as the given action
+func (s *Service) update(ctx context.Context, req *UpdateReq, action domain.AuditAction) (*UpdateResp, error) {
 	err := req.Validate()
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	b, err := s.banners.FetchForID(req.ID)
+	b, err := s.banners.FetchForIDContext(ctx, req.ID)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
+	// the version is checked by the repository as well, which
+	// catches the changes made while this update is being done
+	if req.Version != 0 && req.Version != b.Version {
+		return nil, &domain.VersionConflictError{ID: b.ID, Expected: req.Version, Actual: b.Version}
+	}
+	before := b.Clone()
+
 	// Note: updating fields can be done in a nicer way
 	if req.Name != nil {
 		b.Name = *req.Name
@@ -109,24 +546,373 @@ func (s *Service) Update(ctx context.Context, req *UpdateReq) error {
 	if req.ExpiresAt != nil {
 		b.ExpiresAt = *req.ExpiresAt
 	}
+	if req.Weight != nil {
+		b.Weight = *req.Weight
+	}
+	if req.Priority != nil {
+		b.Priority = *req.Priority
+	}
+	if req.Audience != nil {
+		b.Audience = normalizeAudience(req.Audience)
+	}
+	if req.TimeZone != nil {
+		b.TimeZone = *req.TimeZone
+	}
+	if req.Recurrence != nil {
+		b.Recurrence = normalizeRecurrence(req.Recurrence)
+	}
+	if req.Content != nil {
+		b.Content = normalizeContent(req.Content)
+	}
 
-	_, err = s.banners.Save(*b)
+	err = resolveWallClocks(b, req.ScheduledDisplayingAtWallClock, req.ExpiresAtWallClock)
+	if err != nil {
+		return nil, err
+	}
+	normalizeTimes(b)
+	if err := validateBanner(b); err != nil {
+		return nil, err
+	}
 
-	return err
+	var conflicts []Conflict
+	unlock := s.lockOverlap(req.Overlap)
+	err = s.withinTx(ctx, func(ctx context.Context) error {
+		var err error
+		conflicts, err = s.checkOverlap(ctx, b, req.Overlap)
+		if err != nil {
+			return err
+		}
+
+		_, err = s.banners.SaveContext(ctx, *b)
+		if err != nil {
+			return err
+		}
+
+		b.Version = domain.NextVersion(&before)
+		if err := s.record(ctx, action, b.ID, &before, b); err != nil {
+			return err
+		}
+		return s.emit(ctx, domain.EventBannerUpdated, b.ID, b)
+	})
+	unlock()
+	if err != nil {
+		return nil, err
+	}
+	s.invalidateActive(ctx, b)
+
+	return &UpdateResp{
+		Version:   b.Version,
+		Conflicts: conflicts,
+	}, nil
+}
+
+// DisplayReq represents the request to display banner to the viewer
+type DisplayReq struct {
+	Viewer domain.Viewer
 }
 
 // DisplayResp returns the display banner response
 type DisplayResp struct {
 	Banner domain.Banner
+
+	// Locale and Content are the banner content variant
+	// matching the viewer locales, Content is nil if
+	// the banner has no content
+	Locale  string
+	Content *domain.ContentVariant
 }
 
 // Display loads available domain banners and finds
-// the one that should be shown
-func (s *Service) Display(ctx context.Context) (*DisplayResp, error) {
-	banner, err := s.disp.DisplayBanner()
+// the one that should be shown to the viewer
+func (s *Service) Display(ctx context.Context, req *DisplayReq) (*DisplayResp, error) {
+	banner, err := s.disp.DisplayBannerContext(ctx, req.Viewer)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &DisplayResp{Banner: *banner}
+	if locale, v, ok := banner.Content.Localize(req.Viewer.Locales()); ok {
+		resp.Locale = locale
+		resp.Content = &v
+	}
+
+	return resp, nil
+}
+
+// GetReq represents the request to get a single banner
+type GetReq struct {
+	ID domain.BannerID
+}
+
+// Validate validates GetReq and returns error if the validation fails
+func (req *GetReq) Validate() error {
+	if req.ID == 0 {
+		return domain.NewValidationError("id", "must be set")
+	}
+	return nil
+}
+
+// GetResp represents get banner response
+type GetResp struct {
+	Banner domain.Banner
+}
+
+// Get use case returns the banner with the given ID
+func (s *Service) Get(ctx context.Context, req *GetReq) (*GetResp, error) {
+	err := req.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := s.banners.FetchForIDContext(ctx, req.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &GetResp{Banner: *b}, nil
+}
+
+// ListSort represents the banner field the list is sorted by
+type ListSort string
+
+const (
+	// SortByID sorts banners by their ID
+	SortByID ListSort = "id"
+
+	// SortByName sorts banners by their name
+	SortByName ListSort = "name"
+
+	// SortByScheduledDisplayingAt sorts banners by
+	// the start of their display period
+	SortByScheduledDisplayingAt ListSort = "scheduled_displaying_at"
+
+	// SortByExpiresAt sorts banners by their expiration time
+	SortByExpiresAt ListSort = "expires_at"
+)
+
+// MaxListLimit is the largest page size the list use case returns
+const MaxListLimit = 1000
+
+// ListReq represents the request to list banners
+// Zero values of the fields mean no filtering, sorting by ID
+// in ascending order and returning all the banners
+type ListReq struct {
+	// Status returns only the banners with the given status
+	Status domain.BannerStatus
+
+	// From and To return only the banners whose display
+	// period overlaps with the [From, To) time range
+	From time.Time
+	To   time.Time
+
+	SortBy ListSort
+	Desc   bool
+
+	Offset int
+	Limit  int
+}
+
+// Validate validates ListReq and returns error if the validation fails
+func (req *ListReq) Validate() error {
+	var verr domain.ValidationError
+	switch req.Status {
+	case "", domain.BannerScheduled, domain.BannerActive, domain.BannerExpired:
+	default:
+		verr.Add("status", "unknown banner status %q", req.Status)
+	}
+
+	switch req.SortBy {
+	case "", SortByID, SortByName, SortByScheduledDisplayingAt, SortByExpiresAt:
+	default:
+		verr.Add("sort", "unknown sort field %q", req.SortBy)
+	}
+
+	if !req.From.IsZero() && !req.To.IsZero() && !req.From.Before(req.To) {
+		verr.Add("from", "must be before to")
+	}
+
+	if req.Offset < 0 {
+		verr.Add("offset", "must not be negative")
+	}
+	if req.Limit < 0 || req.Limit > MaxListLimit {
+		verr.Add("limit", "must be between 0 and %d", MaxListLimit)
+	}
+
+	return verr.Err()
+}
+
+// ListResp represents list banners response
+type ListResp struct {
+	Banners []domain.Banner
+
+	// Total is the number of banners matching
+	// the filters, regardless of the pagination
+	Total int
+}
+
+// List use case returns filtered, sorted and paginated banners
+func (s *Service) List(ctx context.Context, req *ListReq) (*ListResp, error) {
+	err := req.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	banners, err := s.banners.ListContext(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	return &DisplayResp{Banner: *banner}, nil
+	// filtering is done in memory for now, as the
+	// repository does not support querying by fields
+	now := s.clock.Now()
+	matching := make([]domain.Banner, 0, len(banners))
+	for _, b := range banners {
+		if req.Status != "" && b.Status(now) != req.Status {
+			continue
+		}
+		if !req.From.IsZero() && !b.ExpiresAt.After(req.From) {
+			continue
+		}
+		if !req.To.IsZero() && !b.ScheduledDisplayingAt.Before(req.To) {
+			continue
+		}
+		matching = append(matching, b)
+	}
+
+	less := listLess(req.SortBy)
+	sort.SliceStable(matching, func(i, j int) bool {
+		if req.Desc {
+			return less(matching[j], matching[i])
+		}
+		return less(matching[i], matching[j])
+	})
+
+	total := len(matching)
+	offset := req.Offset
+	if offset > total {
+		offset = total
+	}
+	matching = matching[offset:]
+	if req.Limit > 0 && req.Limit < len(matching) {
+		matching = matching[:req.Limit]
+	}
+
+	return &ListResp{
+		Banners: matching,
+		Total:   total,
+	}, nil
+}
+
+// listLess returns the ordering of banners by the given field, with
+// banner ID as the tiebreaker so that pagination is stable
+func listLess(by ListSort) func(a, b domain.Banner) bool {
+	switch by {
+	case SortByName:
+		return func(a, b domain.Banner) bool {
+			if c := strings.Compare(a.Name, b.Name); c != 0 {
+				return c < 0
+			}
+			return a.ID < b.ID
+		}
+	case SortByScheduledDisplayingAt:
+		return func(a, b domain.Banner) bool {
+			if !a.ScheduledDisplayingAt.Equal(b.ScheduledDisplayingAt) {
+				return a.ScheduledDisplayingAt.Before(b.ScheduledDisplayingAt)
+			}
+			return a.ID < b.ID
+		}
+	case SortByExpiresAt:
+		return func(a, b domain.Banner) bool {
+			if !a.ExpiresAt.Equal(b.ExpiresAt) {
+				return a.ExpiresAt.Before(b.ExpiresAt)
+			}
+			return a.ID < b.ID
+		}
+	}
+
+	return func(a, b domain.Banner) bool {
+		return a.ID < b.ID
+	}
+}
+
+// DeleteReq represents the request to delete a banner
+type DeleteReq struct {
+	ID domain.BannerID
+}
+
+// Validate validates DeleteReq and returns error if the validation fails
+func (req *DeleteReq) Validate() error {
+	if req.ID == 0 {
+		return domain.NewValidationError("id", "must be set")
+	}
+	return nil
+}
+
+// Delete use case deletes the banner from the repository, and
+// clears the active banner if the deleted banner was the active one
+func (s *Service) Delete(ctx context.Context, req *DeleteReq) error {
+	err := req.Validate()
+	if err != nil {
+		return err
+	}
+
+	// the banner is fetched only for its snapshot
+	// in the audit log and in the event
+	var before *domain.Banner
+	if s.audit != nil || s.events != nil {
+		before, err = s.banners.FetchForIDContext(ctx, req.ID)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = s.withinTx(ctx, func(ctx context.Context) error {
+		err := s.banners.DeleteContext(ctx, req.ID)
+		if err != nil {
+			return err
+		}
+
+		if err := s.record(ctx, domain.AuditDelete, req.ID, before, nil); err != nil {
+			return err
+		}
+		return s.emit(ctx, domain.EventBannerDeleted, req.ID, before)
+	})
+	if err != nil {
+		return err
+	}
+
+	s.invalidateActive(ctx, &domain.Banner{ID: req.ID})
+
+	return nil
+}
+
+// invalidateActive clears the active banner after the given banner has
+// been written, so that the displayer selects the active banner anew.
+// It is needed when the written banner is the active one, or when it is
+// in its display period and therefore may pre-empt the active one.
+//
+// The write is already committed, so failing to clear the active banner
+// is only logged rather than reported as the failed write, which the
+// caller would retry. The stale active banner is then displayed until
+// it expires or the active banner is cleared by another write
+func (s *Service) invalidateActive(ctx context.Context, b *domain.Banner) {
+	if err := s.clearActive(ctx, b); err != nil {
+		log.Printf("banner service: clearing active banner after writing banner %d: %v", b.ID, err)
+	}
+}
+
+func (s *Service) clearActive(ctx context.Context, b *domain.Banner) error {
+	if b.IsInDisplayPeriod(s.clock.Now()) {
+		return s.active.ClearContext(ctx)
+	}
+
+	active, err := s.active.GetContext(ctx)
+	if err != nil {
+		return err
+	}
+	if active != nil && active.ID == b.ID {
+		return s.active.ClearContext(ctx)
+	}
+
+	return nil
 }
@@ -2,13 +2,16 @@ package banner_test
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sync"
 	"testing"
 	"time"
 
 	domain "github.com/DzananGanic/banner"
 	"github.com/DzananGanic/banner/banner"
 	"github.com/DzananGanic/banner/mock"
+	"github.com/DzananGanic/banner/platform/memory"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -23,8 +26,8 @@ func TestCreate(t *testing.T) {
 			name: "successfully create",
 			req: &banner.CreateReq{
 				Name:                  "domain Banner",
-				ScheduledDisplayingAt: time.Date(2019, 1, 1, 1, 1, 1, 1, time.Local),
-				ExpiresAt:             time.Date(2020, 1, 1, 1, 1, 1, 1, time.Local),
+				ScheduledDisplayingAt: time.Date(2019, 1, 1, 1, 1, 1, 1, time.UTC),
+				ExpiresAt:             time.Date(2020, 1, 1, 1, 1, 1, 1, time.UTC),
 			},
 			wantID:  domain.BannerID(1),
 			wantErr: false,
@@ -32,8 +35,40 @@ func TestCreate(t *testing.T) {
 		{
 			name: "failed validation no name",
 			req: &banner.CreateReq{
-				ScheduledDisplayingAt: time.Date(2019, 1, 1, 1, 1, 1, 1, time.Local),
-				ExpiresAt:             time.Date(2020, 1, 1, 1, 1, 1, 1, time.Local),
+				ScheduledDisplayingAt: time.Date(2019, 1, 1, 1, 1, 1, 1, time.UTC),
+				ExpiresAt:             time.Date(2020, 1, 1, 1, 1, 1, 1, time.UTC),
+			},
+			wantID:  0,
+			wantErr: true,
+		},
+		{
+			name: "failed validation expires before displayed",
+			req: &banner.CreateReq{
+				Name:                  "domain Banner",
+				ScheduledDisplayingAt: time.Date(2020, 1, 1, 1, 1, 1, 1, time.UTC),
+				ExpiresAt:             time.Date(2019, 1, 1, 1, 1, 1, 1, time.UTC),
+			},
+			wantID:  0,
+			wantErr: true,
+		},
+		{
+			name: "failed validation unknown platform",
+			req: &banner.CreateReq{
+				Name:                  "domain Banner",
+				ScheduledDisplayingAt: time.Date(2019, 1, 1, 1, 1, 1, 1, time.UTC),
+				ExpiresAt:             time.Date(2020, 1, 1, 1, 1, 1, 1, time.UTC),
+				Audience:              &domain.Audience{Platforms: []domain.Platform{"smartwatch"}},
+			},
+			wantID:  0,
+			wantErr: true,
+		},
+		{
+			name: "failed validation weekly recurrence without weekdays",
+			req: &banner.CreateReq{
+				Name:                  "domain Banner",
+				ScheduledDisplayingAt: time.Date(2019, 1, 1, 1, 1, 1, 1, time.UTC),
+				ExpiresAt:             time.Date(2020, 1, 1, 1, 1, 1, 1, time.UTC),
+				Recurrence:            &domain.Recurrence{Frequency: domain.Weekly, Duration: time.Hour},
 			},
 			wantID:  0,
 			wantErr: true,
@@ -42,8 +77,8 @@ func TestCreate(t *testing.T) {
 			name: "failed create database error",
 			req: &banner.CreateReq{
 				Name:                  "Fake Banner",
-				ScheduledDisplayingAt: time.Date(2019, 1, 1, 1, 1, 1, 1, time.Local),
-				ExpiresAt:             time.Date(2020, 1, 1, 1, 1, 1, 1, time.Local),
+				ScheduledDisplayingAt: time.Date(2019, 1, 1, 1, 1, 1, 1, time.UTC),
+				ExpiresAt:             time.Date(2020, 1, 1, 1, 1, 1, 1, time.UTC),
 			},
 			wantID:  0,
 			wantErr: true,
@@ -55,7 +90,9 @@ func TestCreate(t *testing.T) {
 			args := makeBannerArgs()
 			svc := banner.New(
 				args.bannerDB,
+				args.active,
 				args.disp,
+				args.clock,
 			)
 
 			resp, err := svc.Create(context.Background(), c.req)
@@ -71,6 +108,74 @@ func TestCreate(t *testing.T) {
 	}
 }
 
+func TestCreateWallClock(t *testing.T) {
+	args := makeBannerArgs()
+	var saved domain.Banner
+	args.bannerDB.SaveFn = func(b domain.Banner) (domain.BannerID, error) {
+		saved = b
+		return 1, nil
+	}
+	svc := banner.New(
+		args.bannerDB,
+		args.active,
+		args.disp,
+		args.clock,
+	)
+
+	start := domain.WallClock{Year: 2019, Month: time.June, Day: 3, Hour: 9}
+	_, err := svc.Create(context.Background(), &banner.CreateReq{
+		Name:                           "lunch",
+		TimeZone:                       "Europe/Sarajevo",
+		ScheduledDisplayingAtWallClock: &start,
+		ExpiresAt:                      time.Date(2019, 6, 10, 9, 0, 0, 0, time.FixedZone("CEST", 2*3600)),
+	})
+	assert.Nil(t, err)
+	assert.Nil(t, saved.CheckNormalized())
+	assert.Equal(t, time.Date(2019, 6, 3, 7, 0, 0, 0, time.UTC), saved.ScheduledDisplayingAt)
+	assert.Equal(t, time.Date(2019, 6, 10, 7, 0, 0, 0, time.UTC), saved.ExpiresAt)
+	assert.Equal(t, "Europe/Sarajevo", saved.TimeZone)
+
+	_, err = svc.Create(context.Background(), &banner.CreateReq{
+		Name:                           "lunch",
+		TimeZone:                       "Mars/Olympus_Mons",
+		ScheduledDisplayingAtWallClock: &start,
+		ExpiresAt:                      time.Date(2019, 6, 10, 9, 0, 0, 0, time.UTC),
+	})
+	assert.NotNil(t, err)
+}
+
+func TestCreateAudienceAttributes(t *testing.T) {
+	args := makeBannerArgs()
+	var saved domain.Banner
+	args.bannerDB.SaveFn = func(b domain.Banner) (domain.BannerID, error) {
+		saved = b
+		return 1, nil
+	}
+	svc := banner.New(
+		args.bannerDB,
+		args.active,
+		args.disp,
+		args.clock,
+	)
+
+	req := func(attrs map[string]string) *banner.CreateReq {
+		return &banner.CreateReq{
+			Name:                  "pro",
+			ScheduledDisplayingAt: time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC),
+			ExpiresAt:             time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+			Audience:              &domain.Audience{Attributes: attrs},
+		}
+	}
+
+	_, err := svc.Create(context.Background(), req(map[string]string{"Plan": "pro"}))
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{"plan": "pro"}, saved.Audience.Attributes)
+
+	_, err = svc.Create(context.Background(), req(map[string]string{"Plan": "pro", "plan": "free"}))
+	var verr *domain.ValidationError
+	assert.True(t, errors.As(err, &verr))
+}
+
 func TestUpdate(t *testing.T) {
 	cases := []struct {
 		name    string
@@ -92,8 +197,8 @@ func TestUpdate(t *testing.T) {
 			name: "successfully update all",
 			req: func() *banner.UpdateReq {
 				name := "updated name"
-				sch := time.Date(2019, 1, 1, 1, 1, 1, 1, time.Local)
-				exp := time.Date(2021, 1, 1, 1, 1, 1, 1, time.Local)
+				sch := time.Date(2019, 1, 1, 1, 1, 1, 1, time.UTC)
+				exp := time.Date(2021, 1, 1, 1, 1, 1, 1, time.UTC)
 				return &banner.UpdateReq{
 					ID:                    domain.BannerID(2),
 					ScheduledDisplayingAt: &sch,
@@ -103,6 +208,17 @@ func TestUpdate(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "failed validation resulting banner expires before displayed",
+			req: func() *banner.UpdateReq {
+				exp := time.Date(2018, 1, 1, 1, 1, 1, 1, time.UTC)
+				return &banner.UpdateReq{
+					ID:        domain.BannerID(2),
+					ExpiresAt: &exp,
+				}
+			},
+			wantErr: true,
+		},
 		{
 			name: "failed validation no id",
 			req: func() *banner.UpdateReq {
@@ -142,10 +258,12 @@ func TestUpdate(t *testing.T) {
 			args := makeBannerArgs()
 			svc := banner.New(
 				args.bannerDB,
+				args.active,
 				args.disp,
+				args.clock,
 			)
 
-			err := svc.Update(context.Background(), c.req())
+			_, err := svc.Update(context.Background(), c.req())
 			if c.wantErr {
 				assert.NotNil(t, err)
 			} else {
@@ -155,6 +273,604 @@ func TestUpdate(t *testing.T) {
 	}
 }
 
+func TestCreateOverlap(t *testing.T) {
+	daily := func(hour int, d time.Duration) *domain.Recurrence {
+		return &domain.Recurrence{Frequency: domain.Daily, StartHour: hour, Duration: d}
+	}
+	existing := []domain.Banner{
+		{
+			ID:                    5,
+			Name:                  "dinner",
+			ScheduledDisplayingAt: time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC),
+			ExpiresAt:             time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+			Recurrence:            daily(18, 2*time.Hour),
+		},
+		{
+			ID:                    6,
+			Name:                  "brunch",
+			ScheduledDisplayingAt: time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC),
+			ExpiresAt:             time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+			Recurrence:            daily(10, 2*time.Hour),
+		},
+		{
+			ID:                    7,
+			Name:                  "expired",
+			ScheduledDisplayingAt: time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC),
+			ExpiresAt:             time.Date(2018, 6, 1, 0, 0, 0, 0, time.UTC),
+		},
+	}
+	wantConflicts := []banner.Conflict{
+		{
+			BannerID: 6,
+			Name:     "brunch",
+			Interval: domain.Interval{
+				From: time.Date(2019, 1, 1, 11, 0, 0, 0, time.UTC),
+				To:   time.Date(2019, 1, 1, 12, 0, 0, 0, time.UTC),
+			},
+		},
+	}
+
+	cases := []struct {
+		name          string
+		overlap       banner.OverlapMode
+		wantConflicts []banner.Conflict
+		wantErr       error
+		wantSaved     bool
+	}{
+		{
+			name:      "successfully create without checking overlaps",
+			overlap:   banner.OverlapAllow,
+			wantSaved: true,
+		},
+		{
+			name:          "successfully create reporting overlaps",
+			overlap:       banner.OverlapWarn,
+			wantConflicts: wantConflicts,
+			wantSaved:     true,
+		},
+		{
+			name:      "failed create overlapping banner",
+			overlap:   banner.OverlapReject,
+			wantErr:   &banner.ConflictError{Conflicts: wantConflicts},
+			wantSaved: false,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			args := makeBannerArgs()
+			args.bannerDB.ListFn = func() ([]domain.Banner, error) {
+				return existing, nil
+			}
+			args.bannerDB.SaveFn = func(b domain.Banner) (domain.BannerID, error) {
+				return 8, nil
+			}
+			svc := banner.New(
+				args.bannerDB,
+				args.active,
+				args.disp,
+				args.clock,
+			)
+
+			resp, err := svc.Create(context.Background(), &banner.CreateReq{
+				Name:                  "lunch",
+				ScheduledDisplayingAt: time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC),
+				ExpiresAt:             time.Date(2019, 2, 1, 0, 0, 0, 0, time.UTC),
+				Recurrence:            daily(11, 3*time.Hour),
+				Overlap:               c.overlap,
+			})
+			assert.Equal(t, c.wantSaved, args.bannerDB.SaveInvoked)
+			if c.wantErr != nil {
+				assert.Equal(t, c.wantErr, err)
+				assert.True(t, errors.Is(err, domain.ErrConflict))
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.Equal(t, c.wantConflicts, resp.Conflicts)
+		})
+	}
+}
+
+// slowListBannerDB widens the window between the overlap
+// check listing the banners and saving the checked banner
+type slowListBannerDB struct {
+	*memory.BannerDB
+}
+
+func (db slowListBannerDB) List() ([]domain.Banner, error) {
+	banners, err := db.BannerDB.List()
+	time.Sleep(20 * time.Millisecond)
+	return banners, err
+}
+
+func TestCreateOverlapRejectConcurrent(t *testing.T) {
+	args := makeBannerArgs()
+	svc := banner.New(
+		slowListBannerDB{memory.NewBannerDB()},
+		args.active,
+		args.disp,
+		mock.NewClock(time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)),
+	)
+
+	const n = 5
+	errs := make(chan error, n)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := svc.Create(context.Background(), &banner.CreateReq{
+				Name:                  "banner",
+				ScheduledDisplayingAt: time.Date(2019, 2, 1, 0, 0, 0, 0, time.UTC),
+				ExpiresAt:             time.Date(2019, 3, 1, 0, 0, 0, 0, time.UTC),
+				Overlap:               banner.OverlapReject,
+			})
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	// only the first of the overlapping banners is created
+	created := 0
+	for err := range errs {
+		if err == nil {
+			created++
+			continue
+		}
+		assert.True(t, errors.Is(err, domain.ErrConflict))
+	}
+	assert.Equal(t, 1, created)
+}
+
+func TestUpdateVersion(t *testing.T) {
+	cases := []struct {
+		name        string
+		version     int
+		wantErr     error
+		wantSaved   bool
+		wantVersion int
+	}{
+		{
+			name:        "successfully update current version",
+			version:     4,
+			wantSaved:   true,
+			wantVersion: 5,
+		},
+		{
+			name:        "successfully update without version",
+			version:     0,
+			wantSaved:   true,
+			wantVersion: 5,
+		},
+		{
+			name:      "failed update stale version",
+			version:   3,
+			wantErr:   &domain.VersionConflictError{ID: 2, Expected: 3, Actual: 4},
+			wantSaved: false,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			args := makeBannerArgs()
+			args.bannerDB.FetchForIDFn = func(id domain.BannerID) (*domain.Banner, error) {
+				return &domain.Banner{
+					ID:                    2,
+					Name:                  "banner",
+					ScheduledDisplayingAt: time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC),
+					ExpiresAt:             time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+					Version:               4,
+				}, nil
+			}
+
+			// the fetched version is passed on for the repository to check
+			var saved domain.Banner
+			args.bannerDB.SaveFn = func(b domain.Banner) (domain.BannerID, error) {
+				saved = b
+				return b.ID, nil
+			}
+			svc := banner.New(
+				args.bannerDB,
+				args.active,
+				args.disp,
+				args.clock,
+			)
+
+			name := "updated"
+			resp, err := svc.Update(context.Background(), &banner.UpdateReq{ID: 2, Name: &name, Version: c.version})
+			assert.Equal(t, c.wantSaved, args.bannerDB.SaveInvoked)
+			if c.wantErr != nil {
+				assert.Equal(t, c.wantErr, err)
+				assert.True(t, errors.Is(err, domain.ErrConflict))
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.Equal(t, 4, saved.Version)
+			assert.Equal(t, c.wantVersion, resp.Version)
+		})
+	}
+}
+
+func TestUpdateResultingBannerValidation(t *testing.T) {
+	args := makeBannerArgs()
+	svc := banner.New(
+		args.bannerDB,
+		args.active,
+		args.disp,
+		args.clock,
+	)
+
+	exp := time.Date(2018, 1, 1, 1, 1, 1, 1, time.UTC)
+	_, err := svc.Update(context.Background(), &banner.UpdateReq{ID: 2, ExpiresAt: &exp})
+
+	var verr *domain.ValidationError
+	assert.True(t, errors.As(err, &verr))
+	assert.Equal(t, []domain.FieldError{{Field: "expires_at", Message: "must be after scheduled displaying at"}}, verr.Fields)
+	assert.False(t, args.bannerDB.SaveInvoked)
+}
+
+func TestAuditRecordsChanges(t *testing.T) {
+	cases := []struct {
+		name      string
+		write     func(context.Context, *banner.Service) error
+		recordErr error
+		wantEntry domain.AuditEntry
+		wantErr   bool
+	}{
+		{
+			name: "successfully record create",
+			write: func(ctx context.Context, svc *banner.Service) error {
+				_, err := svc.Create(ctx, &banner.CreateReq{
+					Name:                  "domain Banner",
+					ScheduledDisplayingAt: time.Date(2019, 1, 1, 1, 1, 1, 1, time.UTC),
+					ExpiresAt:             time.Date(2020, 1, 1, 1, 1, 1, 1, time.UTC),
+				})
+				return err
+			},
+			wantEntry: domain.AuditEntry{
+				BannerID: 1,
+				Action:   domain.AuditCreate,
+				Actor:    "alice",
+				At:       time.Date(2019, 1, 1, 1, 1, 1, 1, time.UTC),
+				After: &domain.Banner{
+					ID:                    1,
+					Name:                  "domain Banner",
+					CreatedAt:             time.Date(2019, 1, 1, 1, 1, 1, 1, time.UTC),
+					ScheduledDisplayingAt: time.Date(2019, 1, 1, 1, 1, 1, 1, time.UTC),
+					ExpiresAt:             time.Date(2020, 1, 1, 1, 1, 1, 1, time.UTC),
+					Version:               1,
+				},
+			},
+		},
+		{
+			name: "successfully record update",
+			write: func(ctx context.Context, svc *banner.Service) error {
+				name := "updated name"
+				_, err := svc.Update(ctx, &banner.UpdateReq{ID: 2, Name: &name})
+				return err
+			},
+			wantEntry: domain.AuditEntry{
+				BannerID: 2,
+				Action:   domain.AuditUpdate,
+				Actor:    "alice",
+				At:       time.Date(2019, 1, 1, 1, 1, 1, 1, time.UTC),
+				Before: &domain.Banner{
+					ID:                    2,
+					Name:                  "Deprecated name",
+					CreatedAt:             time.Date(2019, 1, 1, 1, 1, 1, 1, time.UTC),
+					ScheduledDisplayingAt: time.Date(2019, 1, 1, 1, 1, 1, 1, time.UTC),
+					ExpiresAt:             time.Date(2020, 1, 1, 1, 1, 1, 1, time.UTC),
+				},
+				After: &domain.Banner{
+					ID:                    2,
+					Name:                  "updated name",
+					CreatedAt:             time.Date(2019, 1, 1, 1, 1, 1, 1, time.UTC),
+					ScheduledDisplayingAt: time.Date(2019, 1, 1, 1, 1, 1, 1, time.UTC),
+					ExpiresAt:             time.Date(2020, 1, 1, 1, 1, 1, 1, time.UTC),
+					Version:               1,
+				},
+			},
+		},
+		{
+			name: "successfully record delete",
+			write: func(ctx context.Context, svc *banner.Service) error {
+				return svc.Delete(ctx, &banner.DeleteReq{ID: 2})
+			},
+			wantEntry: domain.AuditEntry{
+				BannerID: 2,
+				Action:   domain.AuditDelete,
+				Actor:    "alice",
+				At:       time.Date(2019, 1, 1, 1, 1, 1, 1, time.UTC),
+				Before: &domain.Banner{
+					ID:                    2,
+					Name:                  "Deprecated name",
+					CreatedAt:             time.Date(2019, 1, 1, 1, 1, 1, 1, time.UTC),
+					ScheduledDisplayingAt: time.Date(2019, 1, 1, 1, 1, 1, 1, time.UTC),
+					ExpiresAt:             time.Date(2020, 1, 1, 1, 1, 1, 1, time.UTC),
+				},
+			},
+		},
+		{
+			name: "failed recording delete",
+			write: func(ctx context.Context, svc *banner.Service) error {
+				return svc.Delete(ctx, &banner.DeleteReq{ID: 2})
+			},
+			recordErr: fmt.Errorf("database error"),
+			wantErr:   true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			args := makeBannerArgs()
+			var got domain.AuditEntry
+			audit := &mock.AuditStore{
+				RecordFn: func(ctx context.Context, e domain.AuditEntry) error {
+					got = e
+					return c.recordErr
+				},
+			}
+			svc := banner.New(
+				args.bannerDB,
+				args.active,
+				args.disp,
+				args.clock,
+				banner.WithAuditStore(audit),
+			)
+
+			err := c.write(domain.WithActor(context.Background(), "alice"), svc)
+			assert.True(t, audit.RecordInvoked)
+			if c.wantErr {
+				assert.NotNil(t, err)
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.Equal(t, c.wantEntry, got)
+		})
+	}
+}
+
+func TestAuditDisabled(t *testing.T) {
+	args := makeBannerArgs()
+	svc := banner.New(
+		args.bannerDB,
+		args.active,
+		args.disp,
+		args.clock,
+	)
+
+	_, err := svc.Audit(context.Background(), &banner.AuditReq{BannerID: 2})
+	assert.Equal(t, banner.ErrAuditDisabled, err)
+}
+
+func TestEmitEvents(t *testing.T) {
+	cases := []struct {
+		name         string
+		write        func(context.Context, *banner.Service) error
+		emitErr      error
+		wantType     domain.EventType
+		wantBannerID domain.BannerID
+		wantName     string
+		wantErr      bool
+	}{
+		{
+			name: "successfully emit created",
+			write: func(ctx context.Context, svc *banner.Service) error {
+				_, err := svc.Create(ctx, &banner.CreateReq{
+					Name:                  "domain Banner",
+					ScheduledDisplayingAt: time.Date(2019, 1, 1, 1, 1, 1, 1, time.UTC),
+					ExpiresAt:             time.Date(2020, 1, 1, 1, 1, 1, 1, time.UTC),
+				})
+				return err
+			},
+			wantType:     domain.EventBannerCreated,
+			wantBannerID: 1,
+			wantName:     "domain Banner",
+		},
+		{
+			name: "successfully emit updated",
+			write: func(ctx context.Context, svc *banner.Service) error {
+				name := "updated name"
+				_, err := svc.Update(ctx, &banner.UpdateReq{ID: 2, Name: &name})
+				return err
+			},
+			wantType:     domain.EventBannerUpdated,
+			wantBannerID: 2,
+			wantName:     "updated name",
+		},
+		{
+			name: "successfully emit deleted",
+			write: func(ctx context.Context, svc *banner.Service) error {
+				return svc.Delete(ctx, &banner.DeleteReq{ID: 2})
+			},
+			wantType:     domain.EventBannerDeleted,
+			wantBannerID: 2,
+			wantName:     "Deprecated name",
+		},
+		{
+			name: "failed emitting update",
+			write: func(ctx context.Context, svc *banner.Service) error {
+				name := "updated name"
+				_, err := svc.Update(ctx, &banner.UpdateReq{ID: 2, Name: &name})
+				return err
+			},
+			emitErr: fmt.Errorf("database error"),
+			wantErr: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			args := makeBannerArgs()
+			var got domain.Event
+			events := &mock.EventEmitter{
+				EmitFn: func(ctx context.Context, e domain.Event) error {
+					got = e
+					return c.emitErr
+				},
+			}
+			var txErr error
+			tx := &mock.Transactor{
+				WithinTxFn: func(ctx context.Context, fn func(context.Context) error) error {
+					txErr = fn(ctx)
+					return txErr
+				},
+			}
+			svc := banner.New(
+				args.bannerDB,
+				args.active,
+				args.disp,
+				args.clock,
+				banner.WithEventEmitter(events),
+				banner.WithTransactor(tx),
+			)
+
+			err := c.write(domain.WithActor(context.Background(), "alice"), svc)
+			assert.True(t, events.EmitInvoked)
+			assert.True(t, tx.WithinTxInvoked)
+			if c.wantErr {
+				// the failed emit rolls the change back
+				assert.NotNil(t, err)
+				assert.NotNil(t, txErr)
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.Equal(t, c.wantType, got.Type)
+			assert.Equal(t, c.wantBannerID, got.BannerID)
+			assert.Equal(t, "alice", got.Actor)
+			assert.Equal(t, time.Date(2019, 1, 1, 1, 1, 1, 1, time.UTC), got.At)
+			assert.Equal(t, c.wantName, got.Banner.Name)
+		})
+	}
+}
+
+func TestWriteInvalidatesActive(t *testing.T) {
+	now := time.Date(2019, 6, 1, 0, 0, 0, 0, time.UTC)
+	active := &domain.Banner{ID: 4, Name: "active"}
+	stored := map[domain.BannerID]domain.Banner{
+		4: {
+			ID:                    4,
+			Name:                  "active",
+			ScheduledDisplayingAt: time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC),
+			ExpiresAt:             time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+		},
+		5: {
+			ID:                    5,
+			Name:                  "upcoming",
+			ScheduledDisplayingAt: time.Date(2019, 7, 1, 0, 0, 0, 0, time.UTC),
+			ExpiresAt:             time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+		},
+	}
+	upcoming := time.Date(2019, 7, 1, 0, 0, 0, 0, time.UTC)
+	later := time.Date(2019, 8, 1, 0, 0, 0, 0, time.UTC)
+	expiry := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		name        string
+		write       func(*banner.Service) error
+		clearErr    error
+		wantCleared bool
+	}{
+		{
+			name: "test create banner in display period clears active banner",
+			write: func(svc *banner.Service) error {
+				_, err := svc.Create(context.Background(), &banner.CreateReq{
+					Name:                  "pre-empting",
+					ScheduledDisplayingAt: time.Date(2019, 5, 1, 0, 0, 0, 0, time.UTC),
+					ExpiresAt:             expiry,
+					Priority:              1,
+				})
+				return err
+			},
+			wantCleared: true,
+		},
+		{
+			name: "test create upcoming banner keeps active banner",
+			write: func(svc *banner.Service) error {
+				_, err := svc.Create(context.Background(), &banner.CreateReq{
+					Name:                  "upcoming too",
+					ScheduledDisplayingAt: upcoming,
+					ExpiresAt:             expiry,
+				})
+				return err
+			},
+			wantCleared: false,
+		},
+		{
+			name: "test update active banner out of display period clears active banner",
+			write: func(svc *banner.Service) error {
+				_, err := svc.Update(context.Background(), &banner.UpdateReq{ID: 4, ScheduledDisplayingAt: &upcoming})
+				return err
+			},
+			wantCleared: true,
+		},
+		{
+			name: "test update upcoming banner keeps active banner",
+			write: func(svc *banner.Service) error {
+				_, err := svc.Update(context.Background(), &banner.UpdateReq{ID: 5, ScheduledDisplayingAt: &later})
+				return err
+			},
+			wantCleared: false,
+		},
+		{
+			name: "test delete active banner clears active banner",
+			write: func(svc *banner.Service) error {
+				return svc.Delete(context.Background(), &banner.DeleteReq{ID: 4})
+			},
+			wantCleared: true,
+		},
+		{
+			name: "test failing to clear active banner keeps the committed write",
+			write: func(svc *banner.Service) error {
+				return svc.Delete(context.Background(), &banner.DeleteReq{ID: 4})
+			},
+			clearErr:    errors.New("active banner store unavailable"),
+			wantCleared: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			args := makeBannerArgs()
+			args.clock.Set(now)
+			args.bannerDB.SaveFn = func(b domain.Banner) (domain.BannerID, error) {
+				if b.ID == 0 {
+					return 6, nil
+				}
+				return b.ID, nil
+			}
+			args.bannerDB.FetchForIDFn = func(id domain.BannerID) (*domain.Banner, error) {
+				b := stored[id]
+				return &b, nil
+			}
+			args.bannerDB.DeleteFn = func(id domain.BannerID) error {
+				return nil
+			}
+			args.active.GetFn = func() (*domain.Banner, error) {
+				return active, nil
+			}
+			args.active.ClearFn = func() error {
+				return c.clearErr
+			}
+			svc := banner.New(
+				args.bannerDB,
+				args.active,
+				args.disp,
+				args.clock,
+			)
+
+			err := c.write(svc)
+			assert.Nil(t, err)
+			assert.Equal(t, c.wantCleared, args.active.ClearInvoked)
+		})
+	}
+}
+
 func TestDisplay(t *testing.T) {
 	cases := []struct {
 		name       string
@@ -179,10 +895,14 @@ func TestDisplay(t *testing.T) {
 			args := makeBannerArgs()
 			svc := banner.New(
 				args.bannerDB,
+				args.active,
 				args.disp,
+				args.clock,
 			)
 
-			resp, err := svc.Display(context.Background())
+			resp, err := svc.Display(context.Background(), &banner.DisplayReq{
+				Viewer: domain.Viewer{IP: "192.0.2.1"},
+			})
 			assert.Equal(t, resp.Banner, c.wantBanner)
 			if c.wantErr {
 				assert.NotNil(t, err)
@@ -193,9 +913,399 @@ func TestDisplay(t *testing.T) {
 	}
 }
 
+func TestDisplayLocalizedContent(t *testing.T) {
+	args := makeBannerArgs()
+	args.disp.DisplayBannerFn = func(v domain.Viewer) (*domain.Banner, error) {
+		return &domain.Banner{
+			ID: 1,
+			Content: &domain.Content{
+				FallbackLocale: "en",
+				Variants: map[string]domain.ContentVariant{
+					"en": {Title: "Sale", CallToAction: "Shop now"},
+					"bs": {Title: "Popust"},
+				},
+			},
+		}, nil
+	}
+	svc := banner.New(
+		args.bannerDB,
+		args.active,
+		args.disp,
+		args.clock,
+	)
+
+	resp, err := svc.Display(context.Background(), &banner.DisplayReq{
+		Viewer: domain.Viewer{Headers: map[string]string{"Accept-Language": "de;q=0.9, bs-BA"}},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, "bs", resp.Locale)
+	assert.Equal(t, &domain.ContentVariant{Title: "Popust", CallToAction: "Shop now"}, resp.Content)
+}
+
+func TestCreateContentValidation(t *testing.T) {
+	cases := []struct {
+		name    string
+		content *domain.Content
+		wantErr bool
+	}{
+		{
+			name: "successfully create with content",
+			content: &domain.Content{
+				FallbackLocale: "en",
+				Variants: map[string]domain.ContentVariant{
+					"en": {Title: "Sale", ImageURL: "https://example.com/sale.png", AltText: "Sale"},
+					"bs": {Title: "Popust"},
+				},
+			},
+		},
+		{
+			name: "failed validation missing fallback variant",
+			content: &domain.Content{
+				FallbackLocale: "de",
+				Variants:       map[string]domain.ContentVariant{"en": {Title: "Sale"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "failed validation missing title and body",
+			content: &domain.Content{
+				FallbackLocale: "en",
+				Variants:       map[string]domain.ContentVariant{"en": {Theme: "dark"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "failed validation image without alt text",
+			content: &domain.Content{
+				FallbackLocale: "en",
+				Variants:       map[string]domain.ContentVariant{"en": {Title: "Sale", ImageURL: "https://example.com/sale.png"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "failed validation relative link",
+			content: &domain.Content{
+				FallbackLocale: "en",
+				Variants:       map[string]domain.ContentVariant{"en": {Title: "Sale", LinkURL: "/sale"}},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			req := &banner.CreateReq{
+				Name:                  "banner",
+				ScheduledDisplayingAt: time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC),
+				ExpiresAt:             time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+				Content:               c.content,
+			}
+			err := req.Validate()
+			if c.wantErr {
+				assert.NotNil(t, err)
+			} else {
+				assert.Nil(t, err)
+			}
+		})
+	}
+}
+
+func TestGet(t *testing.T) {
+	cases := []struct {
+		name       string
+		req        *banner.GetReq
+		wantBanner domain.Banner
+		wantErr    bool
+	}{
+		{
+			name: "successfully get",
+			req:  &banner.GetReq{ID: 2},
+			wantBanner: domain.Banner{
+				ID:                    domain.BannerID(2),
+				Name:                  "Deprecated name",
+				CreatedAt:             time.Date(2019, 1, 1, 1, 1, 1, 1, time.UTC),
+				ScheduledDisplayingAt: time.Date(2019, 1, 1, 1, 1, 1, 1, time.UTC),
+				ExpiresAt:             time.Date(2020, 1, 1, 1, 1, 1, 1, time.UTC),
+			},
+			wantErr: false,
+		},
+		{
+			name:    "failed validation no id",
+			req:     &banner.GetReq{},
+			wantErr: true,
+		},
+		{
+			name:    "failed get non existing banner",
+			req:     &banner.GetReq{ID: -5},
+			wantErr: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			args := makeBannerArgs()
+			svc := banner.New(
+				args.bannerDB,
+				args.active,
+				args.disp,
+				args.clock,
+			)
+
+			resp, err := svc.Get(context.Background(), c.req)
+			if c.wantErr {
+				assert.NotNil(t, err)
+			} else {
+				assert.Nil(t, err)
+				assert.Equal(t, c.wantBanner, resp.Banner)
+			}
+		})
+	}
+}
+
+func TestList(t *testing.T) {
+	cases := []struct {
+		name      string
+		req       *banner.ListReq
+		listErr   error
+		wantIDs   []domain.BannerID
+		wantTotal int
+		wantErr   bool
+	}{
+		{
+			name:      "successfully list all",
+			req:       &banner.ListReq{},
+			wantIDs:   []domain.BannerID{1, 2, 3, 4},
+			wantTotal: 4,
+		},
+		{
+			name:      "successfully filter by status",
+			req:       &banner.ListReq{Status: domain.BannerActive},
+			wantIDs:   []domain.BannerID{2, 4},
+			wantTotal: 2,
+		},
+		{
+			name: "successfully filter by time range",
+			req: &banner.ListReq{
+				From: time.Date(2005, 1, 1, 0, 0, 0, 0, time.UTC),
+				To:   time.Date(2050, 1, 1, 0, 0, 0, 0, time.UTC),
+			},
+			wantIDs:   []domain.BannerID{2, 4},
+			wantTotal: 2,
+		},
+		{
+			name:      "successfully sort by expiration descending",
+			req:       &banner.ListReq{SortBy: banner.SortByExpiresAt, Desc: true},
+			wantIDs:   []domain.BannerID{3, 4, 2, 1},
+			wantTotal: 4,
+		},
+		{
+			name:      "successfully sort by name",
+			req:       &banner.ListReq{SortBy: banner.SortByName},
+			wantIDs:   []domain.BannerID{2, 4, 1, 3},
+			wantTotal: 4,
+		},
+		{
+			name:      "successfully paginate",
+			req:       &banner.ListReq{Offset: 1, Limit: 2},
+			wantIDs:   []domain.BannerID{2, 3},
+			wantTotal: 4,
+		},
+		{
+			name:      "successfully paginate past the end",
+			req:       &banner.ListReq{Offset: 10},
+			wantIDs:   []domain.BannerID{},
+			wantTotal: 4,
+		},
+		{
+			name:    "failed validation unknown status",
+			req:     &banner.ListReq{Status: "archived"},
+			wantErr: true,
+		},
+		{
+			name:    "failed validation unknown sort",
+			req:     &banner.ListReq{SortBy: "color"},
+			wantErr: true,
+		},
+		{
+			name:    "failed validation negative offset",
+			req:     &banner.ListReq{Offset: -1},
+			wantErr: true,
+		},
+		{
+			name: "failed validation from after to",
+			req: &banner.ListReq{
+				From: time.Date(2050, 1, 1, 0, 0, 0, 0, time.UTC),
+				To:   time.Date(2005, 1, 1, 0, 0, 0, 0, time.UTC),
+			},
+			wantErr: true,
+		},
+		{
+			name:    "failed list database error",
+			req:     &banner.ListReq{},
+			listErr: fmt.Errorf("database error"),
+			wantErr: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			args := makeBannerArgs()
+			args.bannerDB.ListFn = func() ([]domain.Banner, error) {
+				if c.listErr != nil {
+					return nil, c.listErr
+				}
+				return []domain.Banner{
+					{
+						ID:                    1,
+						Name:                  "expired",
+						ScheduledDisplayingAt: time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC),
+						ExpiresAt:             time.Date(2001, 1, 1, 0, 0, 0, 0, time.UTC),
+					},
+					{
+						ID:                    2,
+						Name:                  "active",
+						ScheduledDisplayingAt: time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC),
+						ExpiresAt:             time.Date(2100, 1, 1, 0, 0, 0, 0, time.UTC),
+					},
+					{
+						ID:                    3,
+						Name:                  "scheduled",
+						ScheduledDisplayingAt: time.Date(2099, 1, 1, 0, 0, 0, 0, time.UTC),
+						ExpiresAt:             time.Date(2101, 1, 1, 0, 0, 0, 0, time.UTC),
+					},
+					{
+						ID:                    4,
+						Name:                  "active too",
+						ScheduledDisplayingAt: time.Date(2001, 1, 1, 0, 0, 0, 0, time.UTC),
+						ExpiresAt:             time.Date(2100, 6, 1, 0, 0, 0, 0, time.UTC),
+					},
+				}, nil
+			}
+			svc := banner.New(
+				args.bannerDB,
+				args.active,
+				args.disp,
+				args.clock,
+			)
+
+			req := *c.req
+			resp, err := svc.List(context.Background(), c.req)
+			// the request is left as it was passed in
+			assert.Equal(t, req, *c.req)
+			if c.wantErr {
+				assert.NotNil(t, err)
+				return
+			}
+
+			assert.Nil(t, err)
+			ids := []domain.BannerID{}
+			for _, b := range resp.Banners {
+				ids = append(ids, b.ID)
+			}
+			assert.Equal(t, c.wantIDs, ids)
+			assert.Equal(t, c.wantTotal, resp.Total)
+		})
+	}
+}
+
+func TestDelete(t *testing.T) {
+	cases := []struct {
+		name        string
+		req         *banner.DeleteReq
+		active      *domain.Banner
+		wantCleared bool
+		wantErr     bool
+	}{
+		{
+			name:        "successfully delete active banner",
+			req:         &banner.DeleteReq{ID: 2},
+			active:      &domain.Banner{ID: 2},
+			wantCleared: true,
+		},
+		{
+			name:        "successfully delete inactive banner",
+			req:         &banner.DeleteReq{ID: 2},
+			active:      &domain.Banner{ID: 7},
+			wantCleared: false,
+		},
+		{
+			name:        "successfully delete without active banner",
+			req:         &banner.DeleteReq{ID: 2},
+			wantCleared: false,
+		},
+		{
+			name:    "failed validation no id",
+			req:     &banner.DeleteReq{},
+			wantErr: true,
+		},
+		{
+			name:    "failed delete database error",
+			req:     &banner.DeleteReq{ID: 3},
+			wantErr: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			args := makeBannerArgs()
+			args.active.GetFn = func() (*domain.Banner, error) {
+				return c.active, nil
+			}
+			args.active.ClearFn = func() error {
+				return nil
+			}
+			svc := banner.New(
+				args.bannerDB,
+				args.active,
+				args.disp,
+				args.clock,
+			)
+
+			err := svc.Delete(context.Background(), c.req)
+			if c.wantErr {
+				assert.NotNil(t, err)
+			} else {
+				assert.Nil(t, err)
+			}
+			assert.Equal(t, c.wantCleared, args.active.ClearInvoked)
+		})
+	}
+}
+
+func TestContextPropagation(t *testing.T) {
+	type ctxKey struct{}
+	ctx := context.WithValue(context.Background(), ctxKey{}, "request")
+
+	args := makeBannerArgs()
+	args.bannerDB.FetchForIDContextFn = func(ctx context.Context, id domain.BannerID) (*domain.Banner, error) {
+		assert.Equal(t, "request", ctx.Value(ctxKey{}))
+		return &domain.Banner{ID: id}, nil
+	}
+	args.disp.DisplayBannerContextFn = func(ctx context.Context, v domain.Viewer) (*domain.Banner, error) {
+		assert.Equal(t, "request", ctx.Value(ctxKey{}))
+		return &domain.Banner{ID: 1}, nil
+	}
+	svc := banner.New(
+		args.bannerDB,
+		args.active,
+		args.disp,
+		args.clock,
+	)
+
+	_, err := svc.Get(ctx, &banner.GetReq{ID: 2})
+	assert.Nil(t, err)
+	assert.True(t, args.bannerDB.FetchForIDInvoked)
+
+	_, err = svc.Display(ctx, &banner.DisplayReq{})
+	assert.Nil(t, err)
+	assert.True(t, args.disp.DisplayBannerInvoked)
+}
+
 type bannerArgs struct {
 	bannerDB *mock.BannerDB
+	active   *mock.ActiveBannerProvider
 	disp     *mock.BannerDisplayer
+	clock    *mock.Clock
 }
 
 func makeBannerArgs() bannerArgs {
@@ -206,38 +1316,40 @@ func makeBannerArgs() bannerArgs {
 		switch b {
 		case domain.Banner{
 			Name:                  "domain Banner",
-			ScheduledDisplayingAt: time.Date(2019, 1, 1, 1, 1, 1, 1, time.Local),
-			ExpiresAt:             time.Date(2020, 1, 1, 1, 1, 1, 1, time.Local),
+			CreatedAt:             time.Date(2019, 1, 1, 1, 1, 1, 1, time.UTC),
+			ScheduledDisplayingAt: time.Date(2019, 1, 1, 1, 1, 1, 1, time.UTC),
+			ExpiresAt:             time.Date(2020, 1, 1, 1, 1, 1, 1, time.UTC),
 		}:
 			return domain.BannerID(1), nil
 		case domain.Banner{
 			Name:                  "Fake Banner",
-			ScheduledDisplayingAt: time.Date(2019, 1, 1, 1, 1, 1, 1, time.Local),
-			ExpiresAt:             time.Date(2020, 1, 1, 1, 1, 1, 1, time.Local),
+			CreatedAt:             time.Date(2019, 1, 1, 1, 1, 1, 1, time.UTC),
+			ScheduledDisplayingAt: time.Date(2019, 1, 1, 1, 1, 1, 1, time.UTC),
+			ExpiresAt:             time.Date(2020, 1, 1, 1, 1, 1, 1, time.UTC),
 		}:
 			return domain.BannerID(0), fmt.Errorf("banner creation failed")
 		case domain.Banner{
 			ID:                    domain.BannerID(2),
 			Name:                  "updated name",
-			CreatedAt:             time.Date(2019, 1, 1, 1, 1, 1, 1, time.Local),
-			ScheduledDisplayingAt: time.Date(2019, 1, 1, 1, 1, 1, 1, time.Local),
-			ExpiresAt:             time.Date(2020, 1, 1, 1, 1, 1, 1, time.Local),
+			CreatedAt:             time.Date(2019, 1, 1, 1, 1, 1, 1, time.UTC),
+			ScheduledDisplayingAt: time.Date(2019, 1, 1, 1, 1, 1, 1, time.UTC),
+			ExpiresAt:             time.Date(2020, 1, 1, 1, 1, 1, 1, time.UTC),
 		}:
 			return domain.BannerID(2), nil
 		case domain.Banner{
 			ID:                    domain.BannerID(2),
 			Name:                  "updated name",
-			CreatedAt:             time.Date(2019, 1, 1, 1, 1, 1, 1, time.Local),
-			ScheduledDisplayingAt: time.Date(2019, 1, 1, 1, 1, 1, 1, time.Local),
-			ExpiresAt:             time.Date(2021, 1, 1, 1, 1, 1, 1, time.Local),
+			CreatedAt:             time.Date(2019, 1, 1, 1, 1, 1, 1, time.UTC),
+			ScheduledDisplayingAt: time.Date(2019, 1, 1, 1, 1, 1, 1, time.UTC),
+			ExpiresAt:             time.Date(2021, 1, 1, 1, 1, 1, 1, time.UTC),
 		}:
 			return domain.BannerID(2), nil
 		case domain.Banner{
 			ID:                    domain.BannerID(3),
 			Name:                  "updated name, about to fail",
-			CreatedAt:             time.Date(2019, 1, 1, 1, 1, 1, 1, time.Local),
-			ScheduledDisplayingAt: time.Date(2019, 1, 1, 1, 1, 1, 1, time.Local),
-			ExpiresAt:             time.Date(2020, 1, 1, 1, 1, 1, 1, time.Local),
+			CreatedAt:             time.Date(2019, 1, 1, 1, 1, 1, 1, time.UTC),
+			ScheduledDisplayingAt: time.Date(2019, 1, 1, 1, 1, 1, 1, time.UTC),
+			ExpiresAt:             time.Date(2020, 1, 1, 1, 1, 1, 1, time.UTC),
 		}:
 			return domain.BannerID(0), fmt.Errorf("database error")
 		}
@@ -251,9 +1363,9 @@ func makeBannerArgs() bannerArgs {
 			return &domain.Banner{
 				ID:                    domain.BannerID(2),
 				Name:                  "Deprecated name",
-				CreatedAt:             time.Date(2019, 1, 1, 1, 1, 1, 1, time.Local),
-				ScheduledDisplayingAt: time.Date(2019, 1, 1, 1, 1, 1, 1, time.Local),
-				ExpiresAt:             time.Date(2020, 1, 1, 1, 1, 1, 1, time.Local),
+				CreatedAt:             time.Date(2019, 1, 1, 1, 1, 1, 1, time.UTC),
+				ScheduledDisplayingAt: time.Date(2019, 1, 1, 1, 1, 1, 1, time.UTC),
+				ExpiresAt:             time.Date(2020, 1, 1, 1, 1, 1, 1, time.UTC),
 			}, nil
 		case domain.BannerID(-5):
 			return nil, fmt.Errorf("non existing banner")
@@ -261,24 +1373,43 @@ func makeBannerArgs() bannerArgs {
 			return &domain.Banner{
 				ID:                    domain.BannerID(3),
 				Name:                  "banner that is doomed to fail",
-				CreatedAt:             time.Date(2019, 1, 1, 1, 1, 1, 1, time.Local),
-				ScheduledDisplayingAt: time.Date(2019, 1, 1, 1, 1, 1, 1, time.Local),
-				ExpiresAt:             time.Date(2020, 1, 1, 1, 1, 1, 1, time.Local),
+				CreatedAt:             time.Date(2019, 1, 1, 1, 1, 1, 1, time.UTC),
+				ScheduledDisplayingAt: time.Date(2019, 1, 1, 1, 1, 1, 1, time.UTC),
+				ExpiresAt:             time.Date(2020, 1, 1, 1, 1, 1, 1, time.UTC),
 			}, nil
 		}
 
 		return nil, fmt.Errorf("no matching cases")
 	}
 
-	disp.DisplayBannerFn = func() (*domain.Banner, error) {
+	bannerDB.DeleteFn = func(id domain.BannerID) error {
+		if id == domain.BannerID(2) {
+			return nil
+		}
+
+		return fmt.Errorf("database error")
+	}
+
+	disp.DisplayBannerFn = func(v domain.Viewer) (*domain.Banner, error) {
 		return &domain.Banner{
 			ID:   1,
 			Name: "Best banner",
 		}, nil
 	}
 
+	active := &mock.ActiveBannerProvider{
+		GetFn: func() (*domain.Banner, error) {
+			return nil, nil
+		},
+		ClearFn: func() error {
+			return nil
+		},
+	}
+
 	return bannerArgs{
 		bannerDB: bannerDB,
+		active:   active,
 		disp:     disp,
+		clock:    mock.NewClock(time.Date(2019, 1, 1, 1, 1, 1, 1, time.UTC)),
 	}
 }
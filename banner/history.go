@@ -0,0 +1,218 @@
+package banner
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	domain "github.com/DzananGanic/banner"
+)
+
+// Revision is the banner as it was saved at its version. Revisions
+// are the snapshots recorded in the audit log, so the history is
+// kept only by the service with the audit store
+type Revision struct {
+	Version int
+	At      time.Time
+	Actor   string
+	Banner  domain.Banner
+}
+
+// revisions returns the revisions of the banner, oldest first
+func (s *Service) revisions(ctx context.Context, id domain.BannerID) ([]Revision, error) {
+	if s.audit == nil {
+		return nil, ErrAuditDisabled
+	}
+
+	entries, err := s.audit.ListForBanner(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	revs := []Revision{}
+	for _, e := range entries {
+		// deletion leaves no revision behind
+		if e.After == nil {
+			continue
+		}
+		revs = append(revs, Revision{
+			Version: e.After.Version,
+			At:      e.At,
+			Actor:   e.Actor,
+			Banner:  *e.After,
+		})
+	}
+
+	return revs, nil
+}
+
+// revision returns the revision of the banner at the given version
+func (s *Service) revision(ctx context.Context, id domain.BannerID, version int) (*Revision, error) {
+	revs, err := s.revisions(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range revs {
+		if revs[i].Version == version {
+			return &revs[i], nil
+		}
+	}
+
+	return nil, fmt.Errorf("revision %d of banner %d: %w", version, id, domain.ErrNotFound)
+}
+
+// HistoryReq represents the request to list the banner revisions
+type HistoryReq struct {
+	ID domain.BannerID
+}
+
+// Validate validates HistoryReq and returns error if the validation fails
+func (req *HistoryReq) Validate() error {
+	if req.ID == 0 {
+		return domain.NewValidationError("id", "must be set")
+	}
+	return nil
+}
+
+// HistoryResp represents the banner history response
+type HistoryResp struct {
+	// Revisions are ordered from the oldest one to the newest one
+	Revisions []Revision
+}
+
+// History use case lists the past revisions of the banner
+func (s *Service) History(ctx context.Context, req *HistoryReq) (*HistoryResp, error) {
+	err := req.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	revs, err := s.revisions(ctx, req.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &HistoryResp{Revisions: revs}, nil
+}
+
+// DiffReq represents the request to compare two banner revisions
+type DiffReq struct {
+	ID   domain.BannerID
+	From int
+	To   int
+}
+
+// Validate validates DiffReq and returns error if the validation fails
+func (req *DiffReq) Validate() error {
+	var verr domain.ValidationError
+	if req.ID == 0 {
+		verr.Add("id", "must be set")
+	}
+	if req.From <= 0 {
+		verr.Add("from", "must be positive")
+	}
+	if req.To <= 0 {
+		verr.Add("to", "must be positive")
+	}
+
+	return verr.Err()
+}
+
+// DiffResp represents the banner revisions comparison response
+type DiffResp struct {
+	Changes []domain.FieldChange
+}
+
+// Diff use case returns the changes made to the banner
+// from the revision From to the revision To
+func (s *Service) Diff(ctx context.Context, req *DiffReq) (*DiffResp, error) {
+	err := req.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	from, err := s.revision(ctx, req.ID, req.From)
+	if err != nil {
+		return nil, err
+	}
+	to, err := s.revision(ctx, req.ID, req.To)
+	if err != nil {
+		return nil, err
+	}
+
+	return &DiffResp{Changes: domain.Diff(from.Banner, to.Banner)}, nil
+}
+
+// RevertReq represents the request to restore the past banner revision
+type RevertReq struct {
+	ID       domain.BannerID
+	Revision int
+
+	// Overlap and Version are as in UpdateReq, Version
+	// is the current banner version, not the restored one
+	Overlap OverlapMode
+	Version int
+}
+
+// Validate validates RevertReq and returns error if the validation fails
+func (req *RevertReq) Validate() error {
+	var verr domain.ValidationError
+	if req.ID == 0 {
+		verr.Add("id", "must be set")
+	}
+	if req.Revision <= 0 {
+		verr.Add("revision", "must be positive")
+	}
+	verr.Check("overlap", req.Overlap.Validate())
+	if req.Version < 0 {
+		verr.Add("version", "must not be negative")
+	}
+
+	return verr.Err()
+}
+
+// Revert use case restores the name, schedule, weight, priority,
+// audience and content of the banner to the ones of its past
+// revision. The banner is updated as by Update, so the revert
+// makes the new revision rather than discarding the later ones
+func (s *Service) Revert(ctx context.Context, req *RevertReq) (*UpdateResp, error) {
+	err := req.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	rev, err := s.revision(ctx, req.ID, req.Revision)
+	if err != nil {
+		return nil, err
+	}
+	b := rev.Banner.Clone()
+
+	ureq := &UpdateReq{
+		ID:                    req.ID,
+		Name:                  &b.Name,
+		ScheduledDisplayingAt: &b.ScheduledDisplayingAt,
+		ExpiresAt:             &b.ExpiresAt,
+		Weight:                &b.Weight,
+		Priority:              &b.Priority,
+		Audience:              b.Audience,
+		TimeZone:              &b.TimeZone,
+		Recurrence:            b.Recurrence,
+		Content:               b.Content,
+		Overlap:               req.Overlap,
+		Version:               req.Version,
+	}
+
+	// the empty values remove what the later revisions have set
+	if ureq.Audience == nil {
+		ureq.Audience = &domain.Audience{}
+	}
+	if ureq.Recurrence == nil {
+		ureq.Recurrence = &domain.Recurrence{}
+	}
+	if ureq.Content == nil {
+		ureq.Content = &domain.Content{}
+	}
+
+	return s.update(ctx, ureq, domain.AuditRevert)
+}
@@ -0,0 +1,181 @@
+package banner_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	domain "github.com/DzananGanic/banner"
+	"github.com/DzananGanic/banner/banner"
+	"github.com/DzananGanic/banner/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+// historyArgs returns the banner 2 which was created as lunch at version
+// 1, renamed at version 2 and rescheduled with recurrence at version 3
+func historyArgs() (bannerArgs, *mock.AuditStore, *domain.Banner) {
+	args := makeBannerArgs()
+
+	v1 := domain.Banner{
+		ID:                    2,
+		Name:                  "lunch",
+		ScheduledDisplayingAt: time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC),
+		ExpiresAt:             time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+		Audience:              &domain.Audience{Countries: []string{"BA"}},
+		Version:               1,
+	}
+	v2 := v1.Clone()
+	v2.Name = "brunch"
+	v2.Version = 2
+	v3 := v2.Clone()
+	v3.ExpiresAt = time.Date(2019, 6, 1, 0, 0, 0, 0, time.UTC)
+	v3.Recurrence = &domain.Recurrence{Frequency: domain.Daily, StartHour: 10, Duration: 2 * time.Hour}
+	v3.Audience = nil
+	v3.Version = 3
+
+	audit := &mock.AuditStore{
+		RecordFn: func(ctx context.Context, e domain.AuditEntry) error {
+			return nil
+		},
+		ListForBannerFn: func(ctx context.Context, id domain.BannerID) ([]domain.AuditEntry, error) {
+			if id != 2 {
+				return []domain.AuditEntry{}, nil
+			}
+			return []domain.AuditEntry{
+				{ID: 1, BannerID: 2, Action: domain.AuditCreate, Actor: "alice", At: time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC), After: &v1},
+				{ID: 3, BannerID: 2, Action: domain.AuditUpdate, Actor: "bob", At: time.Date(2019, 1, 2, 0, 0, 0, 0, time.UTC), Before: &v1, After: &v2},
+				{ID: 4, BannerID: 2, Action: domain.AuditUpdate, Actor: "bob", At: time.Date(2019, 1, 3, 0, 0, 0, 0, time.UTC), Before: &v2, After: &v3},
+			}, nil
+		},
+	}
+	args.bannerDB.FetchForIDFn = func(id domain.BannerID) (*domain.Banner, error) {
+		b := v3.Clone()
+		return &b, nil
+	}
+
+	return args, audit, &v1
+}
+
+func TestHistory(t *testing.T) {
+	args, audit, _ := historyArgs()
+	svc := banner.New(args.bannerDB, args.active, args.disp, args.clock, banner.WithAuditStore(audit))
+
+	resp, err := svc.History(context.Background(), &banner.HistoryReq{ID: 2})
+	assert.Nil(t, err)
+	assert.Len(t, resp.Revisions, 3)
+	for i, r := range resp.Revisions {
+		assert.Equal(t, i+1, r.Version)
+		assert.Equal(t, r.Version, r.Banner.Version)
+	}
+	assert.Equal(t, "bob", resp.Revisions[1].Actor)
+	assert.Equal(t, "brunch", resp.Revisions[1].Banner.Name)
+
+	_, err = svc.History(context.Background(), &banner.HistoryReq{})
+	var verr *domain.ValidationError
+	assert.True(t, errors.As(err, &verr))
+}
+
+func TestDiff(t *testing.T) {
+	cases := []struct {
+		name        string
+		req         *banner.DiffReq
+		wantChanges []domain.FieldChange
+		wantErr     error
+	}{
+		{
+			name: "successfully diff revisions",
+			req:  &banner.DiffReq{ID: 2, From: 1, To: 2},
+			wantChanges: []domain.FieldChange{
+				{Field: "name", From: "lunch", To: "brunch"},
+			},
+		},
+		{
+			name: "successfully diff same revision",
+			req:  &banner.DiffReq{ID: 2, From: 2, To: 2},
+		},
+		{
+			name:    "failed diff unknown revision",
+			req:     &banner.DiffReq{ID: 2, From: 1, To: 4},
+			wantErr: domain.ErrNotFound,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			args, audit, _ := historyArgs()
+			svc := banner.New(args.bannerDB, args.active, args.disp, args.clock, banner.WithAuditStore(audit))
+
+			resp, err := svc.Diff(context.Background(), c.req)
+			if c.wantErr != nil {
+				assert.True(t, errors.Is(err, c.wantErr))
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.Equal(t, c.wantChanges, resp.Changes)
+		})
+	}
+}
+
+func TestRevert(t *testing.T) {
+	cases := []struct {
+		name      string
+		req       *banner.RevertReq
+		wantErr   error
+		wantSaved bool
+	}{
+		{
+			name:      "successfully revert",
+			req:       &banner.RevertReq{ID: 2, Revision: 1},
+			wantSaved: true,
+		},
+		{
+			name:      "successfully revert current version",
+			req:       &banner.RevertReq{ID: 2, Revision: 1, Version: 3},
+			wantSaved: true,
+		},
+		{
+			name:      "failed revert stale version",
+			req:       &banner.RevertReq{ID: 2, Revision: 1, Version: 2},
+			wantErr:   domain.ErrConflict,
+			wantSaved: false,
+		},
+		{
+			name:      "failed revert unknown revision",
+			req:       &banner.RevertReq{ID: 2, Revision: 7},
+			wantErr:   domain.ErrNotFound,
+			wantSaved: false,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			args, audit, v1 := historyArgs()
+			var saved domain.Banner
+			args.bannerDB.SaveFn = func(b domain.Banner) (domain.BannerID, error) {
+				saved = b
+				return b.ID, nil
+			}
+			var recorded domain.AuditEntry
+			audit.RecordFn = func(ctx context.Context, e domain.AuditEntry) error {
+				recorded = e
+				return nil
+			}
+			svc := banner.New(args.bannerDB, args.active, args.disp, args.clock, banner.WithAuditStore(audit))
+
+			resp, err := svc.Revert(context.Background(), c.req)
+			assert.Equal(t, c.wantSaved, args.bannerDB.SaveInvoked)
+			if c.wantErr != nil {
+				assert.True(t, errors.Is(err, c.wantErr))
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.Equal(t, 4, resp.Version)
+			assert.Empty(t, domain.Diff(*v1, saved))
+			assert.Equal(t, 3, saved.Version)
+			assert.Equal(t, domain.AuditRevert, recorded.Action)
+		})
+	}
+}
@@ -0,0 +1,155 @@
+package banner
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	domain "github.com/DzananGanic/banner"
+)
+
+// OverlapMode controls how the overlaps of the banner display
+// windows with the ones of other banners are treated
+type OverlapMode string
+
+const (
+	// OverlapAllow allows the overlaps without checking for them
+	OverlapAllow OverlapMode = ""
+
+	// OverlapWarn allows the overlaps, reporting them in the response
+	OverlapWarn OverlapMode = "warn"
+
+	// OverlapReject rejects the banner which overlaps with
+	// other banners, returning ConflictError. The check is made
+	// within the transaction saving the banner, and the checked
+	// writes of the service are serialized, so the concurrent ones
+	// do not miss each other. The writes of other processes sharing
+	// the SQL store, e.g. other bannerd replicas, are not serialized
+	// with them, so the check is only best-effort across processes
+	OverlapReject OverlapMode = "reject"
+)
+
+// Validate validates the overlap mode
+func (m OverlapMode) Validate() error {
+	switch m {
+	case OverlapAllow, OverlapWarn, OverlapReject:
+		return nil
+	}
+
+	return fmt.Errorf("unknown overlap mode %q", m)
+}
+
+// Conflict represents the overlap with the other banner, the
+// interval is the first one during which both are displayed
+type Conflict struct {
+	BannerID domain.BannerID
+	Name     string
+	Interval domain.Interval
+}
+
+// ConflictError is returned when the banner overlaps with other
+// banners in OverlapReject mode, it matches domain.ErrConflict
+type ConflictError struct {
+	Conflicts []Conflict
+}
+
+// Unwrap makes errors.Is(err, domain.ErrConflict) hold
+func (e *ConflictError) Unwrap() error {
+	return domain.ErrConflict
+}
+
+func (e *ConflictError) Error() string {
+	ids := make([]string, 0, len(e.Conflicts))
+	for _, c := range e.Conflicts {
+		ids = append(ids, fmt.Sprint(c.BannerID))
+	}
+
+	return fmt.Sprintf("banner overlaps with banners %s", strings.Join(ids, ", "))
+}
+
+// validateBanner validates the banner resulting from the request, once
+// its wall clock times are resolved and fields updated, e.g. so that
+// it does not expire before it is displayed
+func validateBanner(b *domain.Banner) error {
+	var verr domain.ValidationError
+	if b.Name == "" {
+		verr.Add("name", "must not be empty")
+	}
+	if b.ScheduledDisplayingAt.IsZero() {
+		verr.Add("scheduled_displaying_at", "must be set")
+	}
+	if b.ExpiresAt.IsZero() {
+		verr.Add("expires_at", "must be set")
+	}
+	verr.Check("expires_at", validateScheduleOrder(b.ScheduledDisplayingAt, b.ExpiresAt))
+	if b.Weight < 0 {
+		verr.Add("weight", "must not be negative")
+	}
+	verr.Check("time_zone", validateTimeZone(b.TimeZone))
+	verr.Check("recurrence", validateRecurrence(b.Recurrence))
+	verr.Check("content", validateContent(b.Content))
+	verr.Check("audience", validateAudience(b.Audience))
+
+	return verr.Err()
+}
+
+// checkOverlap returns the conflicts of the banner with other banners,
+// from now on, or ConflictError in OverlapReject mode if there are any
+func (s *Service) checkOverlap(ctx context.Context, b *domain.Banner, mode OverlapMode) ([]Conflict, error) {
+	if mode == OverlapAllow {
+		return nil, nil
+	}
+
+	banners, err := s.banners.ListContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.clock.Now()
+	var conflicts []Conflict
+	for i := range banners {
+		other := &banners[i]
+		if other.ID == b.ID {
+			continue
+		}
+
+		if interval, ok := b.Overlap(other, now); ok {
+			conflicts = append(conflicts, Conflict{
+				BannerID: other.ID,
+				Name:     other.Name,
+				Interval: interval,
+			})
+		}
+	}
+
+	if mode == OverlapReject && len(conflicts) > 0 {
+		return nil, &ConflictError{Conflicts: conflicts}
+	}
+
+	return conflicts, nil
+}
+
+// lockOverlap serializes the writes checking for the overlaps,
+// and returns the function releasing the lock
+func (s *Service) lockOverlap(mode OverlapMode) func() {
+	if mode == OverlapAllow {
+		return func() {}
+	}
+
+	s.overlapMu.Lock()
+	return s.overlapMu.Unlock
+}
+
+// validateScheduleOrder validates the order of the instants
+// of the request, when both of them are given
+func validateScheduleOrder(scheduledDisplayingAt, expiresAt time.Time) error {
+	if scheduledDisplayingAt.IsZero() || expiresAt.IsZero() {
+		return nil
+	}
+	if !expiresAt.After(scheduledDisplayingAt) {
+		return fmt.Errorf("must be after scheduled displaying at")
+	}
+
+	return nil
+}
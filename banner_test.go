@@ -135,3 +135,43 @@ func TestIsBannerExpired(t *testing.T) {
 		})
 	}
 }
+
+func TestBannerStatus(t *testing.T) {
+	banner := &domain.Banner{
+		ScheduledDisplayingAt: time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC),
+		ExpiresAt:             time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	cases := []struct {
+		name string
+		now  time.Time
+		want domain.BannerStatus
+	}{
+		{
+			name: "test banner is scheduled",
+			now:  time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC),
+			want: domain.BannerScheduled,
+		},
+		{
+			name: "test banner is active",
+			now:  time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC),
+			want: domain.BannerActive,
+		},
+		{
+			name: "test banner is expired at expiration time",
+			now:  time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+			want: domain.BannerExpired,
+		},
+		{
+			name: "test banner is expired",
+			now:  time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+			want: domain.BannerExpired,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			assert.Equal(t, c.want, banner.Status(c.now))
+		})
+	}
+}
@@ -0,0 +1,484 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	domain "github.com/DzananGanic/banner"
+	"github.com/DzananGanic/banner/banner"
+)
+
+// env holds dependencies shared by the commands
+type env struct {
+	// ctx is cancelled on interrupt and once the timeout passes
+	ctx    context.Context
+	svc    *banner.Service
+	out    printer
+	stderr io.Writer
+
+	// actor is who the changes are attributed to in the audit log
+	actor string
+}
+
+// context returns the context of the service calls
+func (e *env) context() context.Context {
+	return domain.WithActor(e.ctx, e.actor)
+}
+
+// command runs with the arguments following the command name
+type command func(e *env, args []string) error
+
+var commands = map[string]command{
+	"create":  create,
+	"update":  update,
+	"delete":  deleteBanner,
+	"list":    list,
+	"show":    show,
+	"display": display,
+	"audit":   audit,
+	"history": history,
+	"diff":    diff,
+	"revert":  revert,
+}
+
+func commandNames() []string {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+func create(e *env, args []string) error {
+	fs := e.flagSet("create")
+	name := fs.String("name", "", "banner name")
+	start := fs.String("start", "", "time from which the banner is displayed, RFC 3339 or wall clock time in -tz")
+	expires := fs.String("expires", "", "time at which the banner expires, RFC 3339 or wall clock time in -tz")
+	weight := fs.Int("weight", 0, "share of traffic when rotating among banners displayed at the same time")
+	priority := fs.Int("priority", 0, "banners with higher priority are displayed instead of the ones with lower")
+	audience := fs.String("audience", "", `JSON targeting criteria, e.g. {"locales":["bs"],"countries":["BA"]}`)
+	tz := fs.String("tz", "", "IANA time zone of the banner, e.g. Europe/Sarajevo")
+	recurrence := fs.String("recurrence", "", "recurring display windows, e.g. FREQ=WEEKLY;BYDAY=MO,TU,WE,TH,FR;BYHOUR=11;DURATION=PT3H")
+	content := fs.String("content", "", `JSON content by locale, e.g. {"fallback_locale":"en","variants":{"en":{"title":"Sale"}}}`)
+	overlap := fs.String("overlap", "", "warn about or reject overlaps with other banners: warn, reject")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	req := &banner.CreateReq{
+		Name:     *name,
+		Weight:   *weight,
+		Priority: *priority,
+		TimeZone: *tz,
+		Overlap:  banner.OverlapMode(*overlap),
+	}
+	var err error
+	if req.ScheduledDisplayingAt, req.ScheduledDisplayingAtWallClock, err = parseScheduleTime("start", *start); err != nil {
+		return err
+	}
+	if req.ExpiresAt, req.ExpiresAtWallClock, err = parseScheduleTime("expires", *expires); err != nil {
+		return err
+	}
+	if req.Audience, err = parseAudience(*audience); err != nil {
+		return err
+	}
+	if req.Recurrence, err = parseRecurrence(*recurrence); err != nil {
+		return err
+	}
+	if req.Content, err = parseContent(*content); err != nil {
+		return err
+	}
+
+	resp, err := e.svc.Create(e.context(), req)
+	if err != nil {
+		return err
+	}
+	e.warnConflicts(resp.Conflicts)
+
+	return e.show(resp.ID)
+}
+
+func update(e *env, args []string) error {
+	fs := e.flagSet("update")
+	id := fs.Int64("id", 0, "banner id")
+	name := fs.String("name", "", "banner name")
+	start := fs.String("start", "", "time from which the banner is displayed, RFC 3339 or wall clock time in -tz")
+	expires := fs.String("expires", "", "time at which the banner expires, RFC 3339 or wall clock time in -tz")
+	weight := fs.Int("weight", 0, "share of traffic when rotating among banners displayed at the same time")
+	priority := fs.Int("priority", 0, "banners with higher priority are displayed instead of the ones with lower")
+	audience := fs.String("audience", "", `JSON targeting criteria, e.g. {"locales":["bs"],"countries":["BA"]}`)
+	tz := fs.String("tz", "", "IANA time zone of the banner, e.g. Europe/Sarajevo")
+	recurrence := fs.String("recurrence", "", "recurring display windows, e.g. FREQ=WEEKLY;BYDAY=MO,TU,WE,TH,FR;BYHOUR=11;DURATION=PT3H")
+	content := fs.String("content", "", `JSON content by locale, e.g. {"fallback_locale":"en","variants":{"en":{"title":"Sale"}}}`)
+	overlap := fs.String("overlap", "", "warn about or reject overlaps with other banners: warn, reject")
+	version := fs.Int("version", 0, "update only if the banner is still at the version, as shown by show")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	req := &banner.UpdateReq{
+		ID:      domain.BannerID(*id),
+		Overlap: banner.OverlapMode(*overlap),
+		Version: *version,
+	}
+
+	// only the flags which were explicitly set are updated
+	var err error
+	fs.Visit(func(f *flag.Flag) {
+		// the first invalid flag is reported, the rest are skipped
+		if err != nil {
+			return
+		}
+
+		switch f.Name {
+		case "name":
+			req.Name = name
+		case "start":
+			var t time.Time
+			if t, req.ScheduledDisplayingAtWallClock, err = parseScheduleTime("start", *start); err == nil && req.ScheduledDisplayingAtWallClock == nil {
+				req.ScheduledDisplayingAt = &t
+			}
+		case "expires":
+			var t time.Time
+			if t, req.ExpiresAtWallClock, err = parseScheduleTime("expires", *expires); err == nil && req.ExpiresAtWallClock == nil {
+				req.ExpiresAt = &t
+			}
+		case "tz":
+			req.TimeZone = tz
+		case "weight":
+			req.Weight = weight
+		case "priority":
+			req.Priority = priority
+		case "audience":
+			var a *domain.Audience
+			if a, err = parseAudience(*audience); err == nil {
+				// empty value makes the banner untargeted
+				if a == nil {
+					a = &domain.Audience{}
+				}
+				req.Audience = a
+			}
+		case "recurrence":
+			var r *domain.Recurrence
+			if r, err = parseRecurrence(*recurrence); err == nil {
+				// empty value removes the recurrence
+				if r == nil {
+					r = &domain.Recurrence{}
+				}
+				req.Recurrence = r
+			}
+		case "content":
+			var c *domain.Content
+			if c, err = parseContent(*content); err == nil {
+				// empty value removes the content
+				if c == nil {
+					c = &domain.Content{}
+				}
+				req.Content = c
+			}
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	resp, err := e.svc.Update(e.context(), req)
+	if err != nil {
+		return err
+	}
+	e.warnConflicts(resp.Conflicts)
+
+	return e.show(req.ID)
+}
+
+func deleteBanner(e *env, args []string) error {
+	fs := e.flagSet("delete")
+	id := fs.Int64("id", 0, "banner id")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	return e.svc.Delete(e.context(), &banner.DeleteReq{ID: domain.BannerID(*id)})
+}
+
+func audit(e *env, args []string) error {
+	fs := e.flagSet("audit")
+	id := fs.Int64("id", 0, "banner id")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	resp, err := e.svc.Audit(e.context(), &banner.AuditReq{BannerID: domain.BannerID(*id)})
+	if err != nil {
+		return err
+	}
+
+	return e.out.audit(resp.Entries)
+}
+
+func history(e *env, args []string) error {
+	fs := e.flagSet("history")
+	id := fs.Int64("id", 0, "banner id")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	resp, err := e.svc.History(e.context(), &banner.HistoryReq{ID: domain.BannerID(*id)})
+	if err != nil {
+		return err
+	}
+
+	return e.out.revisions(resp.Revisions)
+}
+
+func diff(e *env, args []string) error {
+	fs := e.flagSet("diff")
+	id := fs.Int64("id", 0, "banner id")
+	from := fs.Int("from", 0, "revision to compare from, as shown by history")
+	to := fs.Int("to", 0, "revision to compare to, as shown by history")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	resp, err := e.svc.Diff(e.context(), &banner.DiffReq{
+		ID:   domain.BannerID(*id),
+		From: *from,
+		To:   *to,
+	})
+	if err != nil {
+		return err
+	}
+
+	return e.out.changes(resp.Changes)
+}
+
+func revert(e *env, args []string) error {
+	fs := e.flagSet("revert")
+	id := fs.Int64("id", 0, "banner id")
+	revision := fs.Int("revision", 0, "revision to restore, as shown by history")
+	overlap := fs.String("overlap", "", "warn about or reject overlaps with other banners: warn, reject")
+	version := fs.Int("version", 0, "revert only if the banner is still at the version, as shown by show")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	resp, err := e.svc.Revert(e.context(), &banner.RevertReq{
+		ID:       domain.BannerID(*id),
+		Revision: *revision,
+		Overlap:  banner.OverlapMode(*overlap),
+		Version:  *version,
+	})
+	if err != nil {
+		return err
+	}
+	e.warnConflicts(resp.Conflicts)
+
+	return e.show(domain.BannerID(*id))
+}
+
+func list(e *env, args []string) error {
+	fs := e.flagSet("list")
+	status := fs.String("status", "", "only banners with the status: scheduled, active or expired")
+	from := fs.String("from", "", "only banners displayed at or after the time")
+	to := fs.String("to", "", "only banners displayed before the time")
+	sortBy := fs.String("sort", "", "sort by: id, name, scheduled_displaying_at or expires_at")
+	desc := fs.Bool("desc", false, "sort in descending order")
+	offset := fs.Int("offset", 0, "number of banners to skip")
+	limit := fs.Int("limit", 0, "maximum number of banners to list, 0 lists all")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	req := &banner.ListReq{
+		Status: domain.BannerStatus(*status),
+		SortBy: banner.ListSort(*sortBy),
+		Desc:   *desc,
+		Offset: *offset,
+		Limit:  *limit,
+	}
+	var err error
+	if req.From, err = parseTime("from", *from); err != nil {
+		return err
+	}
+	if req.To, err = parseTime("to", *to); err != nil {
+		return err
+	}
+
+	resp, err := e.svc.List(e.context(), req)
+	if err != nil {
+		return err
+	}
+
+	return e.out.banners(resp.Banners)
+}
+
+func show(e *env, args []string) error {
+	fs := e.flagSet("show")
+	id := fs.Int64("id", 0, "banner id")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	return e.show(domain.BannerID(*id))
+}
+
+func display(e *env, args []string) error {
+	fs := e.flagSet("display")
+	ip := fs.String("ip", "", "IP address of the viewer the banner is displayed to")
+	user := fs.String("user", "", "ID of the viewer the banner is displayed to")
+	lang := fs.String("lang", "", "Accept-Language of the viewer the banner is displayed to")
+	ua := fs.String("ua", "", "User-Agent of the viewer the banner is displayed to")
+	country := fs.String("country", "", "country code of the viewer the banner is displayed to")
+	attrs := fs.String("attrs", "", "comma separated key=value attributes of the viewer the banner is displayed to")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	v := domain.Viewer{
+		IP:      *ip,
+		UserID:  *user,
+		Headers: make(map[string]string),
+		Country: *country,
+	}
+	if *lang != "" {
+		v.Headers["Accept-Language"] = *lang
+	}
+	if *ua != "" {
+		v.Headers["User-Agent"] = *ua
+	}
+	for _, attr := range strings.Split(*attrs, ",") {
+		if attr == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(attr, "=")
+		if !ok {
+			return fmt.Errorf("invalid -attrs attribute %q, expected key=value", attr)
+		}
+		if v.Attributes == nil {
+			v.Attributes = make(map[string]string)
+		}
+		v.Attributes[key] = value
+	}
+
+	resp, err := e.svc.Display(e.context(), &banner.DisplayReq{Viewer: v})
+	if err != nil {
+		return err
+	}
+
+	return e.out.displayed(resp)
+}
+
+// warnConflicts reports the overlaps with other banners
+func (e *env) warnConflicts(conflicts []banner.Conflict) {
+	for _, c := range conflicts {
+		fmt.Fprintf(
+			e.stderr, "warning: overlaps with banner %d %q from %s to %s\n",
+			c.BannerID,
+			c.Name,
+			c.Interval.From.Format(time.RFC3339),
+			c.Interval.To.Format(time.RFC3339),
+		)
+	}
+}
+
+func (e *env) show(id domain.BannerID) error {
+	resp, err := e.svc.Get(e.context(), &banner.GetReq{ID: id})
+	if err != nil {
+		return err
+	}
+
+	return e.out.banner(resp.Banner)
+}
+
+func (e *env) flagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(e.stderr)
+
+	return fs
+}
+
+func parseTime(name, value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid -%s time %q, expected RFC 3339", name, value)
+	}
+
+	return t, nil
+}
+
+// parseScheduleTime parses either RFC 3339 time or, without
+// the offset, the wall clock time in the banner time zone
+func parseScheduleTime(name, value string) (time.Time, *domain.WallClock, error) {
+	if value == "" {
+		return time.Time{}, nil, nil
+	}
+
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil, nil
+	}
+
+	w, err := domain.ParseWallClock(value)
+	if err != nil {
+		return time.Time{}, nil, fmt.Errorf("invalid -%s time %q, expected RFC 3339 or wall clock time", name, value)
+	}
+
+	return time.Time{}, &w, nil
+}
+
+// parseRecurrence parses RRULE-like recurrence, returning nil if it is empty
+func parseRecurrence(value string) (*domain.Recurrence, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	r, err := domain.ParseRecurrence(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid -recurrence %q: %v", value, err)
+	}
+
+	return r, nil
+}
+
+// parseContent parses JSON content by locale, returning nil if it is empty
+func parseContent(value string) (*domain.Content, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	var c contentJSON
+	dec := json.NewDecoder(strings.NewReader(value))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&c); err != nil {
+		return nil, fmt.Errorf("invalid -content %q: %v", value, err)
+	}
+
+	return c.toDomain(), nil
+}
+
+// parseAudience parses JSON targeting criteria, returning nil if it is empty
+func parseAudience(value string) (*domain.Audience, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	var a audienceJSON
+	dec := json.NewDecoder(strings.NewReader(value))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&a); err != nil {
+		return nil, fmt.Errorf("invalid -audience %q: %v", value, err)
+	}
+
+	return a.toDomain(), nil
+}
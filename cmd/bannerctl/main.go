@@ -0,0 +1,152 @@
+// Command bannerctl operates banners stored in the configured store
+//
+// Usage:
+//
+//	bannerctl [global flags] <command> [command flags]
+//
+// Commands:
+//
+//	create   creates new banner
+//	update   updates the fields of the existing banner which are set
+//	delete   deletes the banner
+//	list     lists all banners
+//	show     shows a single banner
+//	display  shows the banner that should be displayed to the viewer right now
+//	audit    lists the changes made to the banner, oldest first
+//	history  lists the past revisions of the banner, oldest first
+//	diff     shows the changes made to the banner between its revisions
+//	revert   restores the past revision of the banner
+//
+// Changes are attributed to the -actor in the audit log, which is
+// kept by file, sqlite and postgres stores across the invocations,
+// and so is the banner history used by history, diff and revert
+//
+// The banner events are kept in the outbox of sqlite and postgres
+// stores, and are published by bannerd sharing the same database
+//
+// Every command is cancelled on interrupt, and once
+// the time limit given with -timeout passes
+//
+// Times are given in RFC 3339 format, e.g. 2019-01-01T09:00:00+01:00
+//
+// Recurring display windows are given in RRULE-like format, e.g.
+// FREQ=WEEKLY;BYDAY=MO,TU,WE,TH,FR;BYHOUR=11;DURATION=PT3H
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/DzananGanic/banner/banner"
+	"github.com/DzananGanic/banner/platform/clock"
+	"github.com/DzananGanic/banner/platform/displayer"
+	"github.com/DzananGanic/banner/platform/preview"
+	"github.com/DzananGanic/banner/platform/store"
+)
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout, os.Stderr); err != nil {
+		fmt.Fprintf(os.Stderr, "bannerctl: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// run parses global flags, opens the store and runs the given command
+func run(args []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("bannerctl", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	storeKind := fs.String("store", "memory", "banner store: "+strings.Join(store.Kinds, ", "))
+	dsn := fs.String("dsn", "", "data source name for sqlite and postgres stores, or directory for file store")
+	redisAddr := fs.String("redis", "", "Redis address of the shared active banner")
+	previewList := fs.String("preview", "10.0.0.1,10.0.0.2", "comma separated IP addresses and CIDR blocks allowed to preview banners")
+	previewConfig := fs.String("preview-config", "", "JSON preview allowlist file, overrides -preview")
+	displayerKind := fs.String("displayer", "basic", "banner selection: "+strings.Join(displayer.Kinds, ", "))
+	orderList := fs.String("order", "priority,expiry", "comma separated banner ordering applied in turn: priority, expiry, scheduled")
+	output := fs.String("o", "table", "output format: table or json")
+	actor := fs.String("actor", os.Getenv("USER"), "who the changes are attributed to in the audit log")
+	timeout := fs.Duration("timeout", 30*time.Second, "time limit of the command, no limit if zero")
+	fs.Usage = func() {
+		fmt.Fprintf(stderr, "usage: bannerctl [global flags] <%s> [command flags]\n", strings.Join(commandNames(), "|"))
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return fmt.Errorf("missing command")
+	}
+
+	cmd, ok := commands[fs.Arg(0)]
+	if !ok {
+		return fmt.Errorf("unknown command %q", fs.Arg(0))
+	}
+
+	p, err := newPrinter(*output, stdout)
+	if err != nil {
+		return err
+	}
+
+	entries := strings.Split(*previewList, ",")
+	if *previewConfig != "" {
+		entries, err = preview.LoadConfig(*previewConfig)
+		if err != nil {
+			return err
+		}
+	}
+	policy, err := preview.NewPolicy(entries...)
+	if err != nil {
+		return err
+	}
+
+	stores, closeDB, err := store.Open(*storeKind, *dsn)
+	if err != nil {
+		return fmt.Errorf("opening %s store: %v", *storeKind, err)
+	}
+	defer closeDB()
+	bdb := stores.Banners
+
+	aProvider := store.OpenActiveBannerProvider(*redisAddr)
+	order, err := displayer.ParseOrdering(*orderList)
+	if err != nil {
+		return err
+	}
+	disp, err := displayer.New(*displayerKind, bdb, aProvider, policy, clock.New(), order, stores.Events)
+	if err != nil {
+		return err
+	}
+	svc := banner.New(
+		bdb,
+		aProvider,
+		disp,
+		clock.New(),
+		banner.WithAuditStore(stores.Audit),
+		banner.WithEventEmitter(stores.Events),
+		banner.WithTransactor(stores.Tx),
+	)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if *timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *timeout)
+		defer cancel()
+	}
+
+	env := &env{
+		ctx:    ctx,
+		svc:    svc,
+		out:    p,
+		stderr: stderr,
+		actor:  *actor,
+	}
+
+	return cmd(env, fs.Args()[1:])
+}
@@ -0,0 +1,223 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	domain "github.com/DzananGanic/banner"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	ctl := func(args ...string) (string, error) {
+		var stdout, stderr bytes.Buffer
+		err := run(append([]string{"-store", "file", "-dsn", dir}, args...), &stdout, &stderr)
+		return stdout.String(), err
+	}
+
+	out, err := ctl("create", "-name", "first", "-start", "2019-01-01T00:00:00Z", "-expires", "2020-01-01T00:00:00Z")
+	assert.Nil(t, err)
+	assert.Contains(t, out, "first")
+
+	_, err = ctl("create", "-name", "second", "-start", "2019-01-01T00:00:00Z", "-expires", "2021-01-01T00:00:00Z")
+	assert.Nil(t, err)
+
+	out, err = ctl("update", "-id", "1", "-name", "renamed")
+	assert.Nil(t, err)
+	assert.Contains(t, out, "renamed")
+	assert.Contains(t, out, "2020-01-01T00:00:00Z")
+
+	out, err = ctl("list", "-sort", "expires_at", "-desc", "-limit", "1")
+	assert.Nil(t, err)
+	assert.Contains(t, out, "second")
+	assert.NotContains(t, out, "renamed")
+
+	out, err = ctl("-o", "json", "list")
+	assert.Nil(t, err)
+	var banners []struct {
+		ID   domain.BannerID `json:"id"`
+		Name string          `json:"name"`
+	}
+	assert.Nil(t, json.Unmarshal([]byte(out), &banners))
+	assert.Len(t, banners, 2)
+	assert.Equal(t, "renamed", banners[0].Name)
+
+	_, err = ctl("create", "-name", "localized", "-start", "2019-01-01T00:00:00Z", "-expires", "2100-01-01T00:00:00Z",
+		"-content", `{"fallback_locale":"en","variants":{"en":{"title":"Sale"},"bs":{"title":"Popust"}}}`)
+	assert.Nil(t, err)
+
+	out, err = ctl("display", "-lang", "bs")
+	assert.Nil(t, err)
+	assert.Contains(t, out, "Popust")
+
+	_, err = ctl("delete", "-id", "1")
+	assert.Nil(t, err)
+
+	_, err = ctl("show", "-id", "1")
+	assert.True(t, errors.Is(err, domain.ErrNotFound))
+
+	// the history is kept across the invocations as well
+	out, err = ctl("history", "-id", "2")
+	assert.Nil(t, err)
+	assert.Contains(t, out, "second")
+}
+
+func TestRunTimeout(t *testing.T) {
+	dsn := filepath.Join(t.TempDir(), "banners.db")
+
+	// the read commands are bound by the timeout as well as the writes
+	for _, args := range [][]string{{"list"}, {"show", "-id", "1"}, {"display"}} {
+		var stdout, stderr bytes.Buffer
+		err := run(append([]string{"-store", "sqlite", "-dsn", dsn, "-timeout", "1ns"}, args...), &stdout, &stderr)
+		assert.True(t, errors.Is(err, context.DeadlineExceeded), "%s: %v", args[0], err)
+	}
+}
+
+func TestRunAudit(t *testing.T) {
+	dsn := filepath.Join(t.TempDir(), "banners.db")
+	ctl := func(args ...string) (string, error) {
+		var stdout, stderr bytes.Buffer
+		err := run(append([]string{"-store", "sqlite", "-dsn", dsn, "-actor", "alice"}, args...), &stdout, &stderr)
+		return stdout.String(), err
+	}
+
+	_, err := ctl("create", "-name", "first", "-start", "2019-01-01T00:00:00Z", "-expires", "2020-01-01T00:00:00Z")
+	assert.Nil(t, err)
+	_, err = ctl("update", "-id", "1", "-name", "renamed")
+	assert.Nil(t, err)
+	_, err = ctl("delete", "-id", "1")
+	assert.Nil(t, err)
+
+	out, err := ctl("-o", "json", "audit", "-id", "1")
+	assert.Nil(t, err)
+	var entries []struct {
+		Action domain.AuditAction `json:"action"`
+		Actor  string             `json:"actor"`
+		Before *struct {
+			Name string `json:"name"`
+		} `json:"before"`
+		After *struct {
+			Name string `json:"name"`
+		} `json:"after"`
+	}
+	assert.Nil(t, json.Unmarshal([]byte(out), &entries))
+	assert.Len(t, entries, 3)
+	for i, action := range []domain.AuditAction{domain.AuditCreate, domain.AuditUpdate, domain.AuditDelete} {
+		assert.Equal(t, action, entries[i].Action)
+		assert.Equal(t, "alice", entries[i].Actor)
+	}
+	assert.Equal(t, "first", entries[1].Before.Name)
+	assert.Equal(t, "renamed", entries[1].After.Name)
+	assert.Nil(t, entries[2].After)
+}
+
+func TestRunRevert(t *testing.T) {
+	dsn := filepath.Join(t.TempDir(), "banners.db")
+	ctl := func(args ...string) (string, error) {
+		var stdout, stderr bytes.Buffer
+		err := run(append([]string{"-store", "sqlite", "-dsn", dsn, "-o", "json"}, args...), &stdout, &stderr)
+		return stdout.String(), err
+	}
+
+	_, err := ctl("create", "-name", "first", "-start", "2019-01-01T00:00:00Z", "-expires", "2020-01-01T00:00:00Z")
+	assert.Nil(t, err)
+	_, err = ctl("update", "-id", "1", "-name", "renamed")
+	assert.Nil(t, err)
+
+	out, err := ctl("diff", "-id", "1", "-from", "1", "-to", "2")
+	assert.Nil(t, err)
+	assert.JSONEq(t, `[{"field":"name","from":"first","to":"renamed"}]`, out)
+
+	_, err = ctl("revert", "-id", "1", "-revision", "1", "-version", "1")
+	assert.True(t, errors.Is(err, domain.ErrConflict))
+
+	out, err = ctl("revert", "-id", "1", "-revision", "1")
+	assert.Nil(t, err)
+	var b struct {
+		Name    string `json:"name"`
+		Version int    `json:"version"`
+	}
+	assert.Nil(t, json.Unmarshal([]byte(out), &b))
+	assert.Equal(t, "first", b.Name)
+	assert.Equal(t, 3, b.Version)
+
+	out, err = ctl("history", "-id", "1")
+	assert.Nil(t, err)
+	var revs []struct {
+		Version int `json:"version"`
+	}
+	assert.Nil(t, json.Unmarshal([]byte(out), &revs))
+	assert.Len(t, revs, 3)
+}
+
+func TestRunUpdateInvalidFlag(t *testing.T) {
+	dsn := filepath.Join(t.TempDir(), "banners.db")
+	ctl := func(args ...string) (string, error) {
+		var stdout, stderr bytes.Buffer
+		err := run(append([]string{"-store", "sqlite", "-dsn", dsn, "-o", "json"}, args...), &stdout, &stderr)
+		return stdout.String(), err
+	}
+
+	_, err := ctl("create", "-name", "first", "-start", "2019-01-01T00:00:00Z", "-expires", "2020-01-01T00:00:00Z")
+	assert.Nil(t, err)
+
+	// the valid flag visited after the invalid one must not hide its error
+	_, err = ctl("update", "-id", "1", "-expires", "garbage", "-recurrence", "FREQ=DAILY;BYHOUR=11;DURATION=PT3H")
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "-expires")
+
+	out, err := ctl("show", "-id", "1")
+	assert.Nil(t, err)
+	var b struct {
+		Recurrence string `json:"recurrence"`
+		Version    int    `json:"version"`
+	}
+	assert.Nil(t, json.Unmarshal([]byte(out), &b))
+	assert.Empty(t, b.Recurrence)
+	assert.Equal(t, 1, b.Version)
+}
+
+func TestRunErrors(t *testing.T) {
+	cases := []struct {
+		name string
+		args []string
+	}{
+		{
+			name: "test missing command",
+			args: []string{},
+		},
+		{
+			name: "test unknown command",
+			args: []string{"explode"},
+		},
+		{
+			name: "test unknown store",
+			args: []string{"-store", "mongo", "list"},
+		},
+		{
+			name: "test unknown output",
+			args: []string{"-o", "yaml", "list"},
+		},
+		{
+			name: "test invalid time",
+			args: []string{"create", "-name", "banner", "-start", "tomorrow", "-expires", "2020-01-01T00:00:00Z"},
+		},
+		{
+			name: "test validation error",
+			args: []string{"create", "-name", "banner"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			err := run(c.args, &stdout, &stderr)
+			assert.NotNil(t, err)
+		})
+	}
+}
@@ -0,0 +1,375 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	domain "github.com/DzananGanic/banner"
+	"github.com/DzananGanic/banner/banner"
+)
+
+// printer writes banners in one of the output formats
+type printer interface {
+	banner(domain.Banner) error
+	banners([]domain.Banner) error
+	displayed(*banner.DisplayResp) error
+	audit([]domain.AuditEntry) error
+	revisions([]banner.Revision) error
+	changes([]domain.FieldChange) error
+}
+
+func newPrinter(format string, w io.Writer) (printer, error) {
+	switch format {
+	case "table":
+		return tablePrinter{w: w}, nil
+	case "json":
+		return jsonPrinter{w: w}, nil
+	}
+
+	return nil, fmt.Errorf("unknown output format %q", format)
+}
+
+type tablePrinter struct {
+	w io.Writer
+}
+
+func (p tablePrinter) banner(b domain.Banner) error {
+	return p.banners([]domain.Banner{b})
+}
+
+func (p tablePrinter) banners(banners []domain.Banner) error {
+	tw := tabwriter.NewWriter(p.w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tNAME\tSCHEDULED DISPLAYING AT\tEXPIRES AT\tWEIGHT\tPRIORITY\tVERSION")
+	for _, b := range banners {
+		fmt.Fprintf(
+			tw, "%d\t%s\t%s\t%s\t%d\t%d\t%d\n",
+			b.ID,
+			b.Name,
+			b.ScheduledDisplayingAt.Format(time.RFC3339),
+			b.ExpiresAt.Format(time.RFC3339),
+			b.DisplayWeight(),
+			b.Priority,
+			b.Version,
+		)
+	}
+
+	return tw.Flush()
+}
+
+func (p tablePrinter) displayed(resp *banner.DisplayResp) error {
+	if err := p.banner(resp.Banner); err != nil {
+		return err
+	}
+	if resp.Content == nil {
+		return nil
+	}
+
+	tw := tabwriter.NewWriter(p.w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw)
+	fmt.Fprintln(tw, "LOCALE\tTITLE\tCALL TO ACTION\tLINK")
+	fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", resp.Locale, resp.Content.Title, resp.Content.CallToAction, resp.Content.LinkURL)
+
+	return tw.Flush()
+}
+
+func (p tablePrinter) audit(entries []domain.AuditEntry) error {
+	tw := tabwriter.NewWriter(p.w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tAT\tACTION\tACTOR\tNAME\tVERSION")
+	for _, e := range entries {
+		// the snapshot after the change, or before the deletion
+		b := e.After
+		if b == nil {
+			b = e.Before
+		}
+		var (
+			name    string
+			version int
+		)
+		if b != nil {
+			name, version = b.Name, b.Version
+		}
+
+		fmt.Fprintf(
+			tw, "%d\t%s\t%s\t%s\t%s\t%d\n",
+			e.ID,
+			e.At.Format(time.RFC3339),
+			e.Action,
+			e.Actor,
+			name,
+			version,
+		)
+	}
+
+	return tw.Flush()
+}
+
+func (p tablePrinter) revisions(revs []banner.Revision) error {
+	tw := tabwriter.NewWriter(p.w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "VERSION\tAT\tACTOR\tNAME\tSCHEDULED DISPLAYING AT\tEXPIRES AT")
+	for _, r := range revs {
+		fmt.Fprintf(
+			tw, "%d\t%s\t%s\t%s\t%s\t%s\n",
+			r.Version,
+			r.At.Format(time.RFC3339),
+			r.Actor,
+			r.Banner.Name,
+			r.Banner.ScheduledDisplayingAt.Format(time.RFC3339),
+			r.Banner.ExpiresAt.Format(time.RFC3339),
+		)
+	}
+
+	return tw.Flush()
+}
+
+func (p tablePrinter) changes(changes []domain.FieldChange) error {
+	tw := tabwriter.NewWriter(p.w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "FIELD\tFROM\tTO")
+	for _, c := range changes {
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", c.Field, c.From, c.To)
+	}
+
+	return tw.Flush()
+}
+
+type jsonPrinter struct {
+	w io.Writer
+}
+
+// bannerJSON represents JSON encoding of the domain banner
+type bannerJSON struct {
+	ID                    domain.BannerID `json:"id"`
+	Name                  string          `json:"name"`
+	CreatedAt             time.Time       `json:"created_at"`
+	ScheduledDisplayingAt time.Time       `json:"scheduled_displaying_at"`
+	ExpiresAt             time.Time       `json:"expires_at"`
+	Weight                int             `json:"weight"`
+	Priority              int             `json:"priority"`
+	Audience              *audienceJSON   `json:"audience,omitempty"`
+	TimeZone              string          `json:"time_zone,omitempty"`
+	Recurrence            string          `json:"recurrence,omitempty"`
+	Content               *contentJSON    `json:"content,omitempty"`
+	Version               int             `json:"version"`
+}
+
+func newBannerJSON(b domain.Banner) bannerJSON {
+	return bannerJSON{
+		ID:                    b.ID,
+		Name:                  b.Name,
+		CreatedAt:             b.CreatedAt,
+		ScheduledDisplayingAt: b.ScheduledDisplayingAt,
+		ExpiresAt:             b.ExpiresAt,
+		Weight:                b.Weight,
+		Priority:              b.Priority,
+		Audience:              newAudienceJSON(b.Audience),
+		TimeZone:              b.TimeZone,
+		Recurrence:            formatRecurrence(b.Recurrence),
+		Content:               newContentJSON(b.Content),
+		Version:               b.Version,
+	}
+}
+
+// contentJSON represents JSON encoding of the banner content
+type contentJSON struct {
+	FallbackLocale string                        `json:"fallback_locale"`
+	Variants       map[string]contentVariantJSON `json:"variants"`
+}
+
+// contentVariantJSON represents JSON encoding of the content in a single locale
+type contentVariantJSON struct {
+	Title        string `json:"title,omitempty"`
+	Body         string `json:"body,omitempty"`
+	ImageURL     string `json:"image_url,omitempty"`
+	LinkURL      string `json:"link_url,omitempty"`
+	CallToAction string `json:"call_to_action,omitempty"`
+	AltText      string `json:"alt_text,omitempty"`
+	Theme        string `json:"theme,omitempty"`
+}
+
+func newContentJSON(c *domain.Content) *contentJSON {
+	if c.IsEmpty() {
+		return nil
+	}
+
+	variants := make(map[string]contentVariantJSON, len(c.Variants))
+	for l, v := range c.Variants {
+		variants[l] = contentVariantJSON(v)
+	}
+
+	return &contentJSON{FallbackLocale: c.FallbackLocale, Variants: variants}
+}
+
+func (c *contentJSON) toDomain() *domain.Content {
+	content := &domain.Content{FallbackLocale: c.FallbackLocale}
+	if len(c.Variants) > 0 {
+		content.Variants = make(map[string]domain.ContentVariant, len(c.Variants))
+		for l, v := range c.Variants {
+			content.Variants[l] = domain.ContentVariant(v)
+		}
+	}
+
+	return content
+}
+
+// displayJSON represents JSON encoding of the displayed banner, along
+// with its content in the locale matching the viewer's Accept-Language
+type displayJSON struct {
+	bannerJSON
+	Locale           string              `json:"locale,omitempty"`
+	LocalizedContent *contentVariantJSON `json:"localized_content,omitempty"`
+}
+
+// auditEntryJSON represents JSON encoding of the audit log entry
+type auditEntryJSON struct {
+	ID       int64              `json:"id"`
+	BannerID domain.BannerID    `json:"banner_id"`
+	Action   domain.AuditAction `json:"action"`
+	Actor    string             `json:"actor,omitempty"`
+	At       time.Time          `json:"at"`
+	Before   *bannerJSON        `json:"before,omitempty"`
+	After    *bannerJSON        `json:"after,omitempty"`
+}
+
+func newAuditEntryJSON(e domain.AuditEntry) auditEntryJSON {
+	out := auditEntryJSON{
+		ID:       e.ID,
+		BannerID: e.BannerID,
+		Action:   e.Action,
+		Actor:    e.Actor,
+		At:       e.At,
+	}
+	if e.Before != nil {
+		b := newBannerJSON(*e.Before)
+		out.Before = &b
+	}
+	if e.After != nil {
+		b := newBannerJSON(*e.After)
+		out.After = &b
+	}
+
+	return out
+}
+
+// revisionJSON represents JSON encoding of the past banner revision
+type revisionJSON struct {
+	Version int        `json:"version"`
+	At      time.Time  `json:"at"`
+	Actor   string     `json:"actor,omitempty"`
+	Banner  bannerJSON `json:"banner"`
+}
+
+// fieldChangeJSON represents JSON encoding of the change of the banner field
+type fieldChangeJSON struct {
+	Field string `json:"field"`
+	From  string `json:"from"`
+	To    string `json:"to"`
+}
+
+func formatRecurrence(r *domain.Recurrence) string {
+	if r == nil {
+		return ""
+	}
+	return r.String()
+}
+
+// audienceJSON represents JSON encoding of the banner audience
+type audienceJSON struct {
+	Locales       []string          `json:"locales,omitempty"`
+	Countries     []string          `json:"countries,omitempty"`
+	Platforms     []domain.Platform `json:"platforms,omitempty"`
+	Authenticated *bool             `json:"authenticated,omitempty"`
+	Attributes    map[string]string `json:"attributes,omitempty"`
+}
+
+func newAudienceJSON(a *domain.Audience) *audienceJSON {
+	if a.IsEmpty() {
+		return nil
+	}
+
+	return &audienceJSON{
+		Locales:       a.Locales,
+		Countries:     a.Countries,
+		Platforms:     a.Platforms,
+		Authenticated: a.Authenticated,
+		Attributes:    a.Attributes,
+	}
+}
+
+func (a *audienceJSON) toDomain() *domain.Audience {
+	if a == nil {
+		return nil
+	}
+
+	return &domain.Audience{
+		Locales:       a.Locales,
+		Countries:     a.Countries,
+		Platforms:     a.Platforms,
+		Authenticated: a.Authenticated,
+		Attributes:    a.Attributes,
+	}
+}
+
+func (p jsonPrinter) banner(b domain.Banner) error {
+	return p.encode(newBannerJSON(b))
+}
+
+func (p jsonPrinter) banners(banners []domain.Banner) error {
+	out := make([]bannerJSON, 0, len(banners))
+	for _, b := range banners {
+		out = append(out, newBannerJSON(b))
+	}
+
+	return p.encode(out)
+}
+
+func (p jsonPrinter) displayed(resp *banner.DisplayResp) error {
+	out := displayJSON{bannerJSON: newBannerJSON(resp.Banner)}
+	if resp.Content != nil {
+		out.Locale = resp.Locale
+		v := contentVariantJSON(*resp.Content)
+		out.LocalizedContent = &v
+	}
+
+	return p.encode(out)
+}
+
+func (p jsonPrinter) audit(entries []domain.AuditEntry) error {
+	out := make([]auditEntryJSON, 0, len(entries))
+	for _, e := range entries {
+		out = append(out, newAuditEntryJSON(e))
+	}
+
+	return p.encode(out)
+}
+
+func (p jsonPrinter) revisions(revs []banner.Revision) error {
+	out := make([]revisionJSON, 0, len(revs))
+	for _, r := range revs {
+		out = append(out, revisionJSON{
+			Version: r.Version,
+			At:      r.At,
+			Actor:   r.Actor,
+			Banner:  newBannerJSON(r.Banner),
+		})
+	}
+
+	return p.encode(out)
+}
+
+func (p jsonPrinter) changes(changes []domain.FieldChange) error {
+	out := make([]fieldChangeJSON, 0, len(changes))
+	for _, c := range changes {
+		out = append(out, fieldChangeJSON(c))
+	}
+
+	return p.encode(out)
+}
+
+func (p jsonPrinter) encode(v interface{}) error {
+	enc := json.NewEncoder(p.w)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(v)
+}
@@ -0,0 +1,181 @@
+// Command bannerd serves the banner API over HTTP,
+// and over gRPC as well if -grpc flag is set
+//
+// Banners are stored in the store selected with -store flag,
+// and active banner is cached in Redis if -redis flag is set,
+// or in process memory otherwise
+//
+// Every change is recorded to the audit log, attributed to the
+// X-User-ID set by the trusted proxy over HTTP, and to x-actor
+// metadata over gRPC
+//
+// The gRPC API does not authenticate its callers. The x-actor metadata,
+// and the viewer IP and user ID the banner is selected for, are taken
+// as sent, so any caller may change banners on behalf of anyone and
+// preview banners by claiming the allowlisted IP. The -grpc address
+// must therefore only be reachable by the trusted backends, e.g. bound
+// to the private network or put behind the authenticating proxy
+//
+// The banner events of sqlite and postgres stores are kept in the
+// outbox, and published to -events-stream Redis stream if -redis
+// flag is set, at least once
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/DzananGanic/banner/banner"
+	bannergrpc "github.com/DzananGanic/banner/grpc"
+	"github.com/DzananGanic/banner/grpc/bannerpb"
+	bannerhttp "github.com/DzananGanic/banner/http"
+	"github.com/DzananGanic/banner/platform/clock"
+	"github.com/DzananGanic/banner/platform/displayer"
+	"github.com/DzananGanic/banner/platform/preview"
+	bannersql "github.com/DzananGanic/banner/platform/sql"
+	"github.com/DzananGanic/banner/platform/store"
+	"google.golang.org/grpc"
+)
+
+func main() {
+	addr := flag.String("addr", ":8080", "HTTP listen address")
+	grpcAddr := flag.String("grpc", "", "gRPC listen address reachable only by trusted backends, gRPC is disabled if empty")
+	storeKind := flag.String("store", "memory", "banner store: "+strings.Join(store.Kinds, ", "))
+	dsn := flag.String("dsn", "", "data source name for sqlite and postgres stores, or directory for file store")
+	redisAddr := flag.String("redis", "", "Redis address for sharing active banner between replicas")
+	previewList := flag.String("preview", "10.0.0.1,10.0.0.2", "comma separated IP addresses and CIDR blocks allowed to preview banners")
+	previewConfig := flag.String("preview-config", "", "JSON preview allowlist file, overrides -preview and is reloaded on SIGHUP")
+	displayerKind := flag.String("displayer", "basic", "banner selection: "+strings.Join(displayer.Kinds, ", "))
+	orderList := flag.String("order", "priority,expiry", "comma separated banner ordering applied in turn: priority, expiry, scheduled")
+	eventsStream := flag.String("events-stream", "banner:events", "Redis stream the banner events are published to")
+	trustedProxies := flag.String("trusted-proxies", "", "comma separated IP addresses and CIDR blocks of proxies whose X-Forwarded-For is trusted")
+	flag.Parse()
+
+	proxies, err := bannerhttp.ParseTrustedProxies(strings.Split(*trustedProxies, ","))
+	if err != nil {
+		log.Fatalf("parsing trusted proxies: %v", err)
+	}
+
+	policy, err := preview.NewPolicy(strings.Split(*previewList, ",")...)
+	if err != nil {
+		log.Fatalf("parsing preview allowlist: %v", err)
+	}
+	if *previewConfig != "" {
+		if err := loadPreview(policy, *previewConfig); err != nil {
+			log.Fatalf("loading preview config: %v", err)
+		}
+		go reloadPreviewOnHangup(policy, *previewConfig)
+	}
+
+	stores, closeDB, err := store.Open(*storeKind, *dsn)
+	if err != nil {
+		log.Fatalf("opening %s store: %v", *storeKind, err)
+	}
+	defer closeDB()
+	bdb := stores.Banners
+
+	aProvider := store.OpenActiveBannerProvider(*redisAddr)
+	order, err := displayer.ParseOrdering(*orderList)
+	if err != nil {
+		log.Fatalf("parsing banner ordering: %v", err)
+	}
+	disp, err := displayer.New(*displayerKind, bdb, aProvider, policy, clock.New(), order, stores.Events)
+	if err != nil {
+		log.Fatalf("creating displayer: %v", err)
+	}
+	svc := banner.New(
+		bdb,
+		aProvider,
+		disp,
+		clock.New(),
+		banner.WithAuditStore(stores.Audit),
+		banner.WithEventEmitter(stores.Events),
+		banner.WithTransactor(stores.Tx),
+	)
+
+	relayCtx, stopRelay := context.WithCancel(context.Background())
+	defer stopRelay()
+	if pub := store.OpenEventPublisher(*redisAddr, *eventsStream); pub != nil && stores.Outbox != nil {
+		relay := bannersql.NewRelay(stores.Outbox, pub, bannersql.DefaultRelayInterval)
+		go relay.Run(relayCtx)
+	}
+
+	srv := &http.Server{
+		Addr:              *addr,
+		Handler:           bannerhttp.NewHandler(svc, proxies),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		log.Printf("listening on %s", *addr)
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Fatalf("serving http: %v", err)
+		}
+	}()
+
+	var gsrv *grpc.Server
+	if *grpcAddr != "" {
+		lis, err := net.Listen("tcp", *grpcAddr)
+		if err != nil {
+			log.Fatalf("listening for grpc: %v", err)
+		}
+
+		gsrv = grpc.NewServer()
+		bannerpb.RegisterBannerServiceServer(gsrv, bannergrpc.NewServer(svc))
+
+		go func() {
+			log.Printf("serving grpc on %s", *grpcAddr)
+			if err := gsrv.Serve(lis); err != nil {
+				log.Fatalf("serving grpc: %v", err)
+			}
+		}()
+	}
+
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
+	<-stop
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if gsrv != nil {
+		gsrv.GracefulStop()
+	}
+	if err := srv.Shutdown(ctx); err != nil {
+		log.Printf("shutting down: %v", err)
+	}
+}
+
+// loadPreview replaces preview allowlist with the one from the config file
+func loadPreview(policy *preview.Policy, path string) error {
+	entries, err := preview.LoadConfig(path)
+	if err != nil {
+		return err
+	}
+
+	return policy.Set(entries)
+}
+
+// reloadPreviewOnHangup reloads preview config file every time
+// the process receives SIGHUP, keeping the current allowlist
+// if the file is not valid
+func reloadPreviewOnHangup(policy *preview.Policy, path string) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+
+	for range hup {
+		if err := loadPreview(policy, path); err != nil {
+			log.Printf("reloading preview config: %v", err)
+			continue
+		}
+		log.Printf("reloaded preview config %s", path)
+	}
+}
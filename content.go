@@ -0,0 +1,131 @@
+package domain
+
+import (
+	"sort"
+	"strings"
+)
+
+// ContentVariant represents what is rendered for
+// the banner in a single locale
+type ContentVariant struct {
+	Title        string
+	Body         string
+	ImageURL     string
+	LinkURL      string
+	CallToAction string
+	AltText      string
+
+	// Theme names the visual style the client renders
+	// the banner with, e.g. light or dark
+	Theme string
+}
+
+// Content represents the banner content in all of its locales
+type Content struct {
+	// FallbackLocale is the locale of the variant displayed when none
+	// of the viewer locales has one, its fields also fill in the
+	// ones left empty in other variants
+	FallbackLocale string
+
+	// Variants maps the language tags, e.g. "bs" or "en-US",
+	// to the content in that locale
+	Variants map[string]ContentVariant
+}
+
+// IsEmpty checks whether the content has no variants
+func (c *Content) IsEmpty() bool {
+	return c == nil || len(c.Variants) == 0
+}
+
+// Clone returns the deep copy of the content
+func (c *Content) Clone() *Content {
+	if c == nil {
+		return nil
+	}
+
+	clone := &Content{FallbackLocale: c.FallbackLocale}
+	if c.Variants != nil {
+		clone.Variants = make(map[string]ContentVariant, len(c.Variants))
+		for l, v := range c.Variants {
+			clone.Variants[l] = v
+		}
+	}
+
+	return clone
+}
+
+// Localize returns the locale and the variant which best match the
+// language tags accepted by the viewer, ordered by preference. The
+// accepted tag matches the variant of the same tag, then the one of
+// its base language, then any regional variant of its base language.
+// The fallback variant is returned if no tag matches, and false only
+// if the content has no variants
+func (c *Content) Localize(accepted []string) (string, ContentVariant, bool) {
+	if c.IsEmpty() {
+		return "", ContentVariant{}, false
+	}
+
+	locale := c.match(accepted)
+	if locale == "" {
+		locale = c.FallbackLocale
+	}
+
+	v, ok := c.Variants[locale]
+	if !ok {
+		return "", ContentVariant{}, false
+	}
+
+	return locale, v.withDefaults(c.Variants[c.FallbackLocale]), true
+}
+
+// match returns the locale of the variant matching
+// the accepted tags, or empty string if none does
+func (c *Content) match(accepted []string) string {
+	locales := make([]string, 0, len(c.Variants))
+	for l := range c.Variants {
+		locales = append(locales, l)
+	}
+	// regional variants are matched in deterministic order
+	sort.Strings(locales)
+
+	for _, a := range accepted {
+		base, _, _ := strings.Cut(a, "-")
+
+		for _, l := range locales {
+			if strings.EqualFold(l, a) {
+				return l
+			}
+		}
+		for _, l := range locales {
+			if strings.EqualFold(l, base) {
+				return l
+			}
+		}
+		for _, l := range locales {
+			if lbase, _, _ := strings.Cut(l, "-"); strings.EqualFold(lbase, base) {
+				return l
+			}
+		}
+	}
+
+	return ""
+}
+
+// withDefaults fills in the empty fields of the variant from def
+func (v ContentVariant) withDefaults(def ContentVariant) ContentVariant {
+	fill := func(s *string, d string) {
+		if *s == "" {
+			*s = d
+		}
+	}
+
+	fill(&v.Title, def.Title)
+	fill(&v.Body, def.Body)
+	fill(&v.ImageURL, def.ImageURL)
+	fill(&v.LinkURL, def.LinkURL)
+	fill(&v.CallToAction, def.CallToAction)
+	fill(&v.AltText, def.AltText)
+	fill(&v.Theme, def.Theme)
+
+	return v
+}
@@ -0,0 +1,92 @@
+package domain_test
+
+import (
+	"testing"
+
+	domain "github.com/DzananGanic/banner"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContentLocalize(t *testing.T) {
+	content := &domain.Content{
+		FallbackLocale: "en",
+		Variants: map[string]domain.ContentVariant{
+			"en":    {Title: "Sale", LinkURL: "https://example.com/sale", Theme: "dark"},
+			"en-GB": {Title: "Sale!"},
+			"bs":    {Title: "Popust"},
+			"pt-BR": {Title: "Promoção"},
+		},
+	}
+
+	cases := []struct {
+		name        string
+		content     *domain.Content
+		accepted    []string
+		wantLocale  string
+		wantVariant domain.ContentVariant
+		wantOK      bool
+	}{
+		{
+			name:        "test exact locale filled in from fallback",
+			content:     content,
+			accepted:    []string{"bs"},
+			wantLocale:  "bs",
+			wantVariant: domain.ContentVariant{Title: "Popust", LinkURL: "https://example.com/sale", Theme: "dark"},
+			wantOK:      true,
+		},
+		{
+			name:        "test regional locale is case insensitive",
+			content:     content,
+			accepted:    []string{"EN-gb"},
+			wantLocale:  "en-GB",
+			wantVariant: domain.ContentVariant{Title: "Sale!", LinkURL: "https://example.com/sale", Theme: "dark"},
+			wantOK:      true,
+		},
+		{
+			name:        "test base language of regional locale",
+			content:     content,
+			accepted:    []string{"bs-BA"},
+			wantLocale:  "bs",
+			wantVariant: domain.ContentVariant{Title: "Popust", LinkURL: "https://example.com/sale", Theme: "dark"},
+			wantOK:      true,
+		},
+		{
+			name:        "test regional variant of base language",
+			content:     content,
+			accepted:    []string{"pt"},
+			wantLocale:  "pt-BR",
+			wantVariant: domain.ContentVariant{Title: "Promoção", LinkURL: "https://example.com/sale", Theme: "dark"},
+			wantOK:      true,
+		},
+		{
+			name:        "test preferred locale wins",
+			content:     content,
+			accepted:    []string{"de", "bs", "en"},
+			wantLocale:  "bs",
+			wantVariant: domain.ContentVariant{Title: "Popust", LinkURL: "https://example.com/sale", Theme: "dark"},
+			wantOK:      true,
+		},
+		{
+			name:        "test fallback locale",
+			content:     content,
+			accepted:    []string{"de"},
+			wantLocale:  "en",
+			wantVariant: domain.ContentVariant{Title: "Sale", LinkURL: "https://example.com/sale", Theme: "dark"},
+			wantOK:      true,
+		},
+		{
+			name:    "test no content",
+			content: nil,
+			wantOK:  false,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			locale, variant, ok := c.content.Localize(c.accepted)
+			assert.Equal(t, c.wantOK, ok)
+			assert.Equal(t, c.wantLocale, locale)
+			assert.Equal(t, c.wantVariant, variant)
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package domain
+
+import "context"
+
+// BannerDBContext is the variant of BannerDB whose methods accept
+// context, so that deadlines, cancellation and request scoped
+// values reach the storage
+type BannerDBContext interface {
+	SaveContext(context.Context, Banner) (BannerID, error)
+	FetchForIDContext(context.Context, BannerID) (*Banner, error)
+	ListContext(context.Context) ([]Banner, error)
+	DeleteContext(context.Context, BannerID) error
+}
+
+// ActiveBannerProviderContext is the variant of
+// ActiveBannerProvider whose methods accept context
+type ActiveBannerProviderContext interface {
+	SetContext(context.Context, Banner) error
+	GetContext(context.Context) (*Banner, error)
+	ClearContext(context.Context) error
+}
+
+// BannerDisplayerContext is the variant of
+// BannerDisplayer whose methods accept context
+type BannerDisplayerContext interface {
+	DisplayBannerContext(context.Context, Viewer) (*Banner, error)
+}
+
+// BannerDBWithContext returns the context-aware variant of the banner
+// repository. Repositories which do not support context get wrapped,
+// and the wrapper only checks whether context is done before the call
+func BannerDBWithContext(db BannerDB) BannerDBContext {
+	if dbc, ok := db.(BannerDBContext); ok {
+		return dbc
+	}
+
+	return bannerDBAdapter{db}
+}
+
+type bannerDBAdapter struct {
+	db BannerDB
+}
+
+func (a bannerDBAdapter) SaveContext(ctx context.Context, b Banner) (BannerID, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	return a.db.Save(b)
+}
+
+func (a bannerDBAdapter) FetchForIDContext(ctx context.Context, id BannerID) (*Banner, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return a.db.FetchForID(id)
+}
+
+func (a bannerDBAdapter) ListContext(ctx context.Context) ([]Banner, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return a.db.List()
+}
+
+func (a bannerDBAdapter) DeleteContext(ctx context.Context, id BannerID) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return a.db.Delete(id)
+}
+
+// ActiveBannerProviderWithContext returns the context-aware
+// variant of the active banner provider, wrapping the
+// providers which do not support context
+func ActiveBannerProviderWithContext(p ActiveBannerProvider) ActiveBannerProviderContext {
+	if pc, ok := p.(ActiveBannerProviderContext); ok {
+		return pc
+	}
+
+	return activeBannerProviderAdapter{p}
+}
+
+type activeBannerProviderAdapter struct {
+	p ActiveBannerProvider
+}
+
+func (a activeBannerProviderAdapter) SetContext(ctx context.Context, b Banner) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return a.p.Set(b)
+}
+
+func (a activeBannerProviderAdapter) GetContext(ctx context.Context) (*Banner, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return a.p.Get()
+}
+
+func (a activeBannerProviderAdapter) ClearContext(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return a.p.Clear()
+}
+
+// BannerDisplayerWithContext returns the context-aware variant of
+// the banner displayer, wrapping the displayers which do not support context
+func BannerDisplayerWithContext(d BannerDisplayer) BannerDisplayerContext {
+	if dc, ok := d.(BannerDisplayerContext); ok {
+		return dc
+	}
+
+	return bannerDisplayerAdapter{d}
+}
+
+type bannerDisplayerAdapter struct {
+	d BannerDisplayer
+}
+
+func (a bannerDisplayerAdapter) DisplayBannerContext(ctx context.Context, v Viewer) (*Banner, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return a.d.DisplayBanner(v)
+}
@@ -0,0 +1,100 @@
+package domain_test
+
+import (
+	"context"
+	"testing"
+
+	domain "github.com/DzananGanic/banner"
+	"github.com/DzananGanic/banner/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+// plainBannerDB implements only the BannerDB without context
+type plainBannerDB struct {
+	domain.BannerDB
+	listInvoked bool
+}
+
+func (db *plainBannerDB) List() ([]domain.Banner, error) {
+	db.listInvoked = true
+	return []domain.Banner{{ID: 1}}, nil
+}
+
+func TestBannerDBWithContext(t *testing.T) {
+	db := &plainBannerDB{}
+	dbc := domain.BannerDBWithContext(db)
+
+	banners, err := dbc.ListContext(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, []domain.Banner{{ID: 1}}, banners)
+	assert.True(t, db.listInvoked)
+
+	db.listInvoked = false
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err = dbc.ListContext(ctx)
+	assert.Equal(t, context.Canceled, err)
+	assert.False(t, db.listInvoked)
+}
+
+func TestBannerDBWithContextNative(t *testing.T) {
+	type ctxKey struct{}
+
+	db := &mock.BannerDB{}
+	db.ListContextFn = func(ctx context.Context) ([]domain.Banner, error) {
+		assert.Equal(t, "value", ctx.Value(ctxKey{}))
+		return nil, nil
+	}
+
+	dbc := domain.BannerDBWithContext(db)
+	assert.Equal(t, db, dbc)
+
+	_, err := dbc.ListContext(context.WithValue(context.Background(), ctxKey{}, "value"))
+	assert.Nil(t, err)
+	assert.True(t, db.ListInvoked)
+}
+
+// plainActiveBannerProvider implements only the ActiveBannerProvider without context
+type plainActiveBannerProvider struct {
+	domain.ActiveBannerProvider
+}
+
+func (p plainActiveBannerProvider) Get() (*domain.Banner, error) {
+	return &domain.Banner{ID: 1}, nil
+}
+
+func TestActiveBannerProviderWithContext(t *testing.T) {
+	pc := domain.ActiveBannerProviderWithContext(plainActiveBannerProvider{})
+
+	b, err := pc.GetContext(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, domain.BannerID(1), b.ID)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err = pc.GetContext(ctx)
+	assert.Equal(t, context.Canceled, err)
+}
+
+// plainBannerDisplayer implements only the BannerDisplayer without context
+type plainBannerDisplayer struct{}
+
+func (plainBannerDisplayer) DisplayBanner(v domain.Viewer) (*domain.Banner, error) {
+	return &domain.Banner{Name: v.IP}, nil
+}
+
+func TestBannerDisplayerWithContext(t *testing.T) {
+	dc := domain.BannerDisplayerWithContext(plainBannerDisplayer{})
+
+	b, err := dc.DisplayBannerContext(context.Background(), domain.Viewer{IP: "192.0.2.1"})
+	assert.Nil(t, err)
+	assert.Equal(t, "192.0.2.1", b.Name)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err = dc.DisplayBannerContext(ctx, domain.Viewer{})
+	assert.Equal(t, context.Canceled, err)
+}
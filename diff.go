@@ -0,0 +1,80 @@
+package domain
+
+import (
+	"encoding/json"
+	"strconv"
+	"time"
+)
+
+// FieldChange describes the change of the banner field, with the
+// values formatted as they are shown to the users. The empty value
+// means the field is not set
+type FieldChange struct {
+	Field string
+	From  string
+	To    string
+}
+
+// Diff returns the changes of the editable banner fields made from
+// the banner a to the banner b, in the order of the Banner fields
+func Diff(a, b Banner) []FieldChange {
+	fa, fb := a.fields(), b.fields()
+
+	var changes []FieldChange
+	for i := range fa {
+		if fa[i].value != fb[i].value {
+			changes = append(changes, FieldChange{Field: fa[i].name, From: fa[i].value, To: fb[i].value})
+		}
+	}
+
+	return changes
+}
+
+type field struct {
+	name  string
+	value string
+}
+
+// fields returns the formatted editable fields of the banner,
+// named as in the validation errors
+func (b *Banner) fields() []field {
+	return []field{
+		{"name", b.Name},
+		{"scheduled_displaying_at", formatInstant(b.ScheduledDisplayingAt)},
+		{"expires_at", formatInstant(b.ExpiresAt)},
+		{"weight", strconv.Itoa(b.Weight)},
+		{"priority", strconv.Itoa(b.Priority)},
+		{"audience", formatJSON(!b.Audience.IsEmpty(), b.Audience)},
+		{"time_zone", b.TimeZone},
+		{"recurrence", formatRecurrence(b.Recurrence)},
+		{"content", formatJSON(!b.Content.IsEmpty(), b.Content)},
+	}
+}
+
+func formatInstant(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339Nano)
+}
+
+func formatRecurrence(r *Recurrence) string {
+	if r == nil {
+		return ""
+	}
+	return r.String()
+}
+
+// formatJSON encodes the value if it is set, the maps
+// are encoded with sorted keys so the result is stable
+func formatJSON(set bool, v interface{}) string {
+	if !set {
+		return ""
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err.Error()
+	}
+	return string(data)
+}
@@ -0,0 +1,68 @@
+package domain_test
+
+import (
+	"testing"
+	"time"
+
+	domain "github.com/DzananGanic/banner"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiff(t *testing.T) {
+	a := domain.Banner{
+		ID:                    1,
+		Name:                  "lunch",
+		ScheduledDisplayingAt: time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC),
+		ExpiresAt:             time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+		Audience:              &domain.Audience{Countries: []string{"BA"}},
+		Version:               1,
+	}
+
+	cases := []struct {
+		name        string
+		change      func(*domain.Banner)
+		wantChanges []domain.FieldChange
+	}{
+		{
+			name:   "test no changes",
+			change: func(b *domain.Banner) {},
+		},
+		{
+			name: "test untracked fields are ignored",
+			change: func(b *domain.Banner) {
+				b.Version = 2
+				b.CreatedAt = time.Date(2019, 6, 1, 0, 0, 0, 0, time.UTC)
+			},
+		},
+		{
+			name: "test changed schedule and name",
+			change: func(b *domain.Banner) {
+				b.Name = "dinner"
+				b.ExpiresAt = time.Date(2019, 6, 1, 0, 0, 0, 0, time.UTC)
+				b.Recurrence = &domain.Recurrence{Frequency: domain.Daily, StartHour: 18, Duration: 2 * time.Hour}
+			},
+			wantChanges: []domain.FieldChange{
+				{Field: "name", From: "lunch", To: "dinner"},
+				{Field: "expires_at", From: "2020-01-01T00:00:00Z", To: "2019-06-01T00:00:00Z"},
+				{Field: "recurrence", From: "", To: "FREQ=DAILY;BYHOUR=18;BYMINUTE=0;DURATION=PT2H"},
+			},
+		},
+		{
+			name: "test removed audience",
+			change: func(b *domain.Banner) {
+				b.Audience = &domain.Audience{}
+			},
+			wantChanges: []domain.FieldChange{
+				{Field: "audience", From: `{"Locales":null,"Countries":["BA"],"Platforms":null,"Authenticated":null,"Attributes":null}`, To: ""},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			b := a.Clone()
+			c.change(&b)
+			assert.Equal(t, c.wantChanges, domain.Diff(a, b))
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrNotFound is returned by repositories when
+// the requested entity does not exist
+var ErrNotFound = errors.New("not found")
+
+// ErrNoActiveBanner is returned by banner displayers
+// when there is no banner which could be shown
+var ErrNoActiveBanner = errors.New("no active banners found")
+
+// ErrConflict is returned when the change conflicts with the
+// current state, e.g. when the banner overlaps with other banners
+var ErrConflict = errors.New("conflict")
+
+// VersionConflictError is returned when the banner has been changed
+// since the version the change is based on. Actual is zero if the
+// banner does not exist anymore
+type VersionConflictError struct {
+	ID       BannerID
+	Expected int
+	Actual   int
+}
+
+func (e *VersionConflictError) Error() string {
+	if e.Actual == 0 {
+		return fmt.Sprintf("banner %d: expected version %d, but the banner does not exist", e.ID, e.Expected)
+	}
+	return fmt.Sprintf("banner %d: expected version %d, got %d", e.ID, e.Expected, e.Actual)
+}
+
+// Unwrap makes the version conflict match ErrConflict
+func (e *VersionConflictError) Unwrap() error {
+	return ErrConflict
+}
+
+// FieldError describes why the value of the field is invalid
+type FieldError struct {
+	Field   string
+	Message string
+}
+
+// ValidationError is returned when the request, or the banner
+// resulting from it, is invalid, listing every invalid field
+type ValidationError struct {
+	Fields []FieldError
+}
+
+// NewValidationError returns the validation error of a single field
+func NewValidationError(field, format string, args ...interface{}) *ValidationError {
+	e := &ValidationError{}
+	e.Add(field, format, args...)
+	return e
+}
+
+// Add adds the invalid field
+func (e *ValidationError) Add(field, format string, args ...interface{}) {
+	e.Fields = append(e.Fields, FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+// Check adds the field as invalid if err is not nil
+func (e *ValidationError) Check(field string, err error) {
+	if err != nil {
+		e.Add(field, "%s", err.Error())
+	}
+}
+
+// Err returns the validation error, or nil if no field is invalid
+func (e *ValidationError) Err() error {
+	if len(e.Fields) == 0 {
+		return nil
+	}
+	return e
+}
+
+func (e *ValidationError) Error() string {
+	fields := make([]string, 0, len(e.Fields))
+	for _, f := range e.Fields {
+		fields = append(fields, f.Field+": "+f.Message)
+	}
+
+	return "invalid " + strings.Join(fields, "; ")
+}
@@ -0,0 +1,29 @@
+package domain_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	domain "github.com/DzananGanic/banner"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidationError(t *testing.T) {
+	var verr domain.ValidationError
+	assert.Nil(t, verr.Err())
+
+	verr.Add("name", "must not be empty")
+	verr.Check("weight", nil)
+	verr.Check("limit", fmt.Errorf("must be between 0 and %d", 100))
+
+	err := fmt.Errorf("creating banner: %w", verr.Err())
+	assert.Equal(t, "creating banner: invalid name: must not be empty; limit: must be between 0 and 100", err.Error())
+
+	var target *domain.ValidationError
+	assert.True(t, errors.As(err, &target))
+	assert.Equal(t, []domain.FieldError{
+		{Field: "name", Message: "must not be empty"},
+		{Field: "limit", Message: "must be between 0 and 100"},
+	}, target.Fields)
+}
@@ -0,0 +1,72 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// EventType is the kind of the banner lifecycle event
+type EventType string
+
+const (
+	// EventBannerCreated is emitted when the banner is created
+	EventBannerCreated EventType = "banner.created"
+
+	// EventBannerUpdated is emitted when the banner is updated,
+	// including when it is reverted to its past revision
+	EventBannerUpdated EventType = "banner.updated"
+
+	// EventBannerDeleted is emitted when the banner is deleted
+	EventBannerDeleted EventType = "banner.deleted"
+
+	// EventBannerActivated is emitted when the banner replaces the
+	// previous one as the active banner shared with every viewer.
+	// It is not emitted again when the same banner is selected
+	// after the active banner has been cleared
+	EventBannerActivated EventType = "banner.activated"
+
+	// EventBannerExpired is emitted when the expired active
+	// banner is replaced by the next one
+	EventBannerExpired EventType = "banner.expired"
+)
+
+// Event describes the change in the banner lifecycle
+type Event struct {
+	// ID is assigned by the outbox the event is emitted to, and
+	// orders the events in the order they were emitted. Events
+	// are delivered at least once, so the consumers use it to
+	// recognise the events they have already handled
+	ID int64
+
+	Type     EventType
+	BannerID BannerID
+	At       time.Time
+
+	// Actor is the one who made the change, see WithActor,
+	// it is empty if the actor is not known
+	Actor string
+
+	// Banner is the banner snapshot after the change, or
+	// the one before the change for the deleted banner
+	Banner *Banner
+}
+
+// EventEmitter stores the emitted events for publishing
+type EventEmitter interface {
+	Emit(context.Context, Event) error
+}
+
+// EventPublisher delivers the events to the other systems. An event
+// is published again if publishing it returns the error, and may be
+// published again even if it does not
+type EventPublisher interface {
+	Publish(context.Context, Event) error
+}
+
+// Transactor runs the function in the transaction spanning the
+// repositories it belongs to, which are passed the context given
+// to the function. The changes are committed if the function
+// returns no error, and rolled back otherwise
+type Transactor interface {
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
@@ -1,7 +1,7 @@
 package domain
 
 // An example to show how banner API interface is used:
-// In this example we are using postgres repository implementation
+// In this example we are using postgres repository implementation from platform/sql
 // and basic banner displaying algorithm from platform/displayer/basic.go
 // For caching active banner, we use redis implementation as an example
 
@@ -9,32 +9,60 @@ package domain
 
 /*
 
-db := postgres.NewBannerDB()
-aProvider := redis.NewBannerActiveProvider()
+conn, err := sql.Open("postgres", dsn)
+err = bannersql.Migrate(conn, bannersql.Postgres)
+
+db := bannersql.NewBannerDB(conn)
+aProvider := redis.NewActiveBannerProvider(redisClient, redis.DefaultKey)
+outbox := bannersql.NewOutbox(conn)
+
+// addresses which may see banners before their display period
+previewPolicy, err := preview.NewPolicy("10.0.0.1", "10.0.0.2", "10.10.0.0/16")
 
 // creation of banner API
 b := banner.New(
 	db,
+	aProvider,
 	displayer.NewBasic(
 		db,
 		aProvider,
-		ip.Internal,
+		previewPolicy,
+		clock.New(),
+		displayer.DefaultOrdering,
+		// emitting the activated and expired events to the outbox
+		outbox,
 	),
+	clock.New(),
+	// recording every change to the audit log
+	banner.WithAuditStore(bannersql.NewAuditStore(conn)),
+	// emitting the created, updated and deleted events to the outbox,
+	// in the same transaction as the change and its audit entry
+	banner.WithEventEmitter(outbox),
+	banner.WithTransactor(bannersql.NewTransactor(conn)),
 )
 
-// creating a new banner
+// publishing the outbox events to Redis stream, at least once
+relay := bannersql.NewRelay(outbox, redis.NewEventPublisher(redisClient, redis.DefaultStream), bannersql.DefaultRelayInterval)
+go relay.Run(context.Background())
+
+// the changes are attributed to the actor carried by the context
+ctx := domain.WithActor(context.Background(), "alice")
+
+// creating a new banner, warning about the other banners displayed at the same time
 resp, err := b.Create(
 	context.Background(),
 	banner.CreateReq{
 		Name: "sample banner",
 		ScheduledDisplayingAt: time.Now(),
 		ExpiresAt: time.Now().AddDate(time.Hour*24),
+		Overlap: banner.OverlapWarn,
 	},
 )
 resp.ID
+resp.Conflicts
 
 // updating existing banner
-err := b.Update(
+_, err := b.Update(
 	context.Background(),
 	banner.UpdateReq{
 		ID: 1,
@@ -42,7 +70,34 @@ err := b.Update(
 	},
 )
 
-// calling .Display returns available and active domain banner
-activeBanner, err := b.Display(context.Background())
+// listing active banners, the ones expiring first on top
+list, err := b.List(
+	context.Background(),
+	&banner.ListReq{
+		Status: domain.BannerActive,
+		SortBy: banner.SortByExpiresAt,
+		Limit:  10,
+	},
+)
+
+// deleting the banner, which also clears it if it is the active one
+err := b.Delete(ctx, &banner.DeleteReq{ID: 1})
+
+// listing the changes made to the banner, who made them and when
+audit, err := b.Audit(context.Background(), &banner.AuditReq{BannerID: 1})
+audit.Entries
+
+// comparing the first two revisions of the banner, and restoring the first one
+diff, err := b.Diff(ctx, &banner.DiffReq{ID: 1, From: 1, To: 2})
+diff.Changes
+reverted, err := b.Revert(ctx, &banner.RevertReq{ID: 1, Revision: 1})
+
+// calling .Display returns available and active domain banner for the viewer
+activeBanner, err := b.Display(
+	context.Background(),
+	&banner.DisplayReq{
+		Viewer: domain.Viewer{IP: "203.0.113.7"},
+	},
+)
 
 */
@@ -0,0 +1,469 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v5.27.1
+// source: banner.proto
+
+package bannerpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	BannerService_CreateBanner_FullMethodName        = "/banner.v1.BannerService/CreateBanner"
+	BannerService_UpdateBanner_FullMethodName        = "/banner.v1.BannerService/UpdateBanner"
+	BannerService_DisplayBanner_FullMethodName       = "/banner.v1.BannerService/DisplayBanner"
+	BannerService_GetBanner_FullMethodName           = "/banner.v1.BannerService/GetBanner"
+	BannerService_ListBanners_FullMethodName         = "/banner.v1.BannerService/ListBanners"
+	BannerService_DeleteBanner_FullMethodName        = "/banner.v1.BannerService/DeleteBanner"
+	BannerService_ListBannerAudit_FullMethodName     = "/banner.v1.BannerService/ListBannerAudit"
+	BannerService_ListBannerRevisions_FullMethodName = "/banner.v1.BannerService/ListBannerRevisions"
+	BannerService_DiffBannerRevisions_FullMethodName = "/banner.v1.BannerService/DiffBannerRevisions"
+	BannerService_RevertBanner_FullMethodName        = "/banner.v1.BannerService/RevertBanner"
+)
+
+// BannerServiceClient is the client API for BannerService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// BannerService manages banners and returns the one
+// that should be displayed
+type BannerServiceClient interface {
+	CreateBanner(ctx context.Context, in *CreateBannerRequest, opts ...grpc.CallOption) (*CreateBannerResponse, error)
+	UpdateBanner(ctx context.Context, in *UpdateBannerRequest, opts ...grpc.CallOption) (*UpdateBannerResponse, error)
+	DisplayBanner(ctx context.Context, in *DisplayBannerRequest, opts ...grpc.CallOption) (*DisplayBannerResponse, error)
+	GetBanner(ctx context.Context, in *GetBannerRequest, opts ...grpc.CallOption) (*GetBannerResponse, error)
+	ListBanners(ctx context.Context, in *ListBannersRequest, opts ...grpc.CallOption) (*ListBannersResponse, error)
+	DeleteBanner(ctx context.Context, in *DeleteBannerRequest, opts ...grpc.CallOption) (*DeleteBannerResponse, error)
+	ListBannerAudit(ctx context.Context, in *ListBannerAuditRequest, opts ...grpc.CallOption) (*ListBannerAuditResponse, error)
+	ListBannerRevisions(ctx context.Context, in *ListBannerRevisionsRequest, opts ...grpc.CallOption) (*ListBannerRevisionsResponse, error)
+	DiffBannerRevisions(ctx context.Context, in *DiffBannerRevisionsRequest, opts ...grpc.CallOption) (*DiffBannerRevisionsResponse, error)
+	RevertBanner(ctx context.Context, in *RevertBannerRequest, opts ...grpc.CallOption) (*RevertBannerResponse, error)
+}
+
+type bannerServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewBannerServiceClient(cc grpc.ClientConnInterface) BannerServiceClient {
+	return &bannerServiceClient{cc}
+}
+
+func (c *bannerServiceClient) CreateBanner(ctx context.Context, in *CreateBannerRequest, opts ...grpc.CallOption) (*CreateBannerResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateBannerResponse)
+	err := c.cc.Invoke(ctx, BannerService_CreateBanner_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bannerServiceClient) UpdateBanner(ctx context.Context, in *UpdateBannerRequest, opts ...grpc.CallOption) (*UpdateBannerResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UpdateBannerResponse)
+	err := c.cc.Invoke(ctx, BannerService_UpdateBanner_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bannerServiceClient) DisplayBanner(ctx context.Context, in *DisplayBannerRequest, opts ...grpc.CallOption) (*DisplayBannerResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DisplayBannerResponse)
+	err := c.cc.Invoke(ctx, BannerService_DisplayBanner_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bannerServiceClient) GetBanner(ctx context.Context, in *GetBannerRequest, opts ...grpc.CallOption) (*GetBannerResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetBannerResponse)
+	err := c.cc.Invoke(ctx, BannerService_GetBanner_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bannerServiceClient) ListBanners(ctx context.Context, in *ListBannersRequest, opts ...grpc.CallOption) (*ListBannersResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListBannersResponse)
+	err := c.cc.Invoke(ctx, BannerService_ListBanners_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bannerServiceClient) DeleteBanner(ctx context.Context, in *DeleteBannerRequest, opts ...grpc.CallOption) (*DeleteBannerResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteBannerResponse)
+	err := c.cc.Invoke(ctx, BannerService_DeleteBanner_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bannerServiceClient) ListBannerAudit(ctx context.Context, in *ListBannerAuditRequest, opts ...grpc.CallOption) (*ListBannerAuditResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListBannerAuditResponse)
+	err := c.cc.Invoke(ctx, BannerService_ListBannerAudit_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bannerServiceClient) ListBannerRevisions(ctx context.Context, in *ListBannerRevisionsRequest, opts ...grpc.CallOption) (*ListBannerRevisionsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListBannerRevisionsResponse)
+	err := c.cc.Invoke(ctx, BannerService_ListBannerRevisions_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bannerServiceClient) DiffBannerRevisions(ctx context.Context, in *DiffBannerRevisionsRequest, opts ...grpc.CallOption) (*DiffBannerRevisionsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DiffBannerRevisionsResponse)
+	err := c.cc.Invoke(ctx, BannerService_DiffBannerRevisions_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bannerServiceClient) RevertBanner(ctx context.Context, in *RevertBannerRequest, opts ...grpc.CallOption) (*RevertBannerResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RevertBannerResponse)
+	err := c.cc.Invoke(ctx, BannerService_RevertBanner_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// BannerServiceServer is the server API for BannerService service.
+// All implementations must embed UnimplementedBannerServiceServer
+// for forward compatibility.
+//
+// BannerService manages banners and returns the one
+// that should be displayed
+type BannerServiceServer interface {
+	CreateBanner(context.Context, *CreateBannerRequest) (*CreateBannerResponse, error)
+	UpdateBanner(context.Context, *UpdateBannerRequest) (*UpdateBannerResponse, error)
+	DisplayBanner(context.Context, *DisplayBannerRequest) (*DisplayBannerResponse, error)
+	GetBanner(context.Context, *GetBannerRequest) (*GetBannerResponse, error)
+	ListBanners(context.Context, *ListBannersRequest) (*ListBannersResponse, error)
+	DeleteBanner(context.Context, *DeleteBannerRequest) (*DeleteBannerResponse, error)
+	ListBannerAudit(context.Context, *ListBannerAuditRequest) (*ListBannerAuditResponse, error)
+	ListBannerRevisions(context.Context, *ListBannerRevisionsRequest) (*ListBannerRevisionsResponse, error)
+	DiffBannerRevisions(context.Context, *DiffBannerRevisionsRequest) (*DiffBannerRevisionsResponse, error)
+	RevertBanner(context.Context, *RevertBannerRequest) (*RevertBannerResponse, error)
+	mustEmbedUnimplementedBannerServiceServer()
+}
+
+// UnimplementedBannerServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedBannerServiceServer struct{}
+
+func (UnimplementedBannerServiceServer) CreateBanner(context.Context, *CreateBannerRequest) (*CreateBannerResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateBanner not implemented")
+}
+func (UnimplementedBannerServiceServer) UpdateBanner(context.Context, *UpdateBannerRequest) (*UpdateBannerResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateBanner not implemented")
+}
+func (UnimplementedBannerServiceServer) DisplayBanner(context.Context, *DisplayBannerRequest) (*DisplayBannerResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DisplayBanner not implemented")
+}
+func (UnimplementedBannerServiceServer) GetBanner(context.Context, *GetBannerRequest) (*GetBannerResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBanner not implemented")
+}
+func (UnimplementedBannerServiceServer) ListBanners(context.Context, *ListBannersRequest) (*ListBannersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListBanners not implemented")
+}
+func (UnimplementedBannerServiceServer) DeleteBanner(context.Context, *DeleteBannerRequest) (*DeleteBannerResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteBanner not implemented")
+}
+func (UnimplementedBannerServiceServer) ListBannerAudit(context.Context, *ListBannerAuditRequest) (*ListBannerAuditResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListBannerAudit not implemented")
+}
+func (UnimplementedBannerServiceServer) ListBannerRevisions(context.Context, *ListBannerRevisionsRequest) (*ListBannerRevisionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListBannerRevisions not implemented")
+}
+func (UnimplementedBannerServiceServer) DiffBannerRevisions(context.Context, *DiffBannerRevisionsRequest) (*DiffBannerRevisionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DiffBannerRevisions not implemented")
+}
+func (UnimplementedBannerServiceServer) RevertBanner(context.Context, *RevertBannerRequest) (*RevertBannerResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RevertBanner not implemented")
+}
+func (UnimplementedBannerServiceServer) mustEmbedUnimplementedBannerServiceServer() {}
+func (UnimplementedBannerServiceServer) testEmbeddedByValue()                       {}
+
+// UnsafeBannerServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to BannerServiceServer will
+// result in compilation errors.
+type UnsafeBannerServiceServer interface {
+	mustEmbedUnimplementedBannerServiceServer()
+}
+
+func RegisterBannerServiceServer(s grpc.ServiceRegistrar, srv BannerServiceServer) {
+	// If the following call pancis, it indicates UnimplementedBannerServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&BannerService_ServiceDesc, srv)
+}
+
+func _BannerService_CreateBanner_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateBannerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BannerServiceServer).CreateBanner(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BannerService_CreateBanner_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BannerServiceServer).CreateBanner(ctx, req.(*CreateBannerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BannerService_UpdateBanner_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateBannerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BannerServiceServer).UpdateBanner(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BannerService_UpdateBanner_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BannerServiceServer).UpdateBanner(ctx, req.(*UpdateBannerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BannerService_DisplayBanner_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DisplayBannerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BannerServiceServer).DisplayBanner(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BannerService_DisplayBanner_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BannerServiceServer).DisplayBanner(ctx, req.(*DisplayBannerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BannerService_GetBanner_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetBannerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BannerServiceServer).GetBanner(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BannerService_GetBanner_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BannerServiceServer).GetBanner(ctx, req.(*GetBannerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BannerService_ListBanners_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListBannersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BannerServiceServer).ListBanners(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BannerService_ListBanners_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BannerServiceServer).ListBanners(ctx, req.(*ListBannersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BannerService_DeleteBanner_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteBannerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BannerServiceServer).DeleteBanner(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BannerService_DeleteBanner_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BannerServiceServer).DeleteBanner(ctx, req.(*DeleteBannerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BannerService_ListBannerAudit_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListBannerAuditRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BannerServiceServer).ListBannerAudit(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BannerService_ListBannerAudit_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BannerServiceServer).ListBannerAudit(ctx, req.(*ListBannerAuditRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BannerService_ListBannerRevisions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListBannerRevisionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BannerServiceServer).ListBannerRevisions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BannerService_ListBannerRevisions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BannerServiceServer).ListBannerRevisions(ctx, req.(*ListBannerRevisionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BannerService_DiffBannerRevisions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DiffBannerRevisionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BannerServiceServer).DiffBannerRevisions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BannerService_DiffBannerRevisions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BannerServiceServer).DiffBannerRevisions(ctx, req.(*DiffBannerRevisionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BannerService_RevertBanner_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevertBannerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BannerServiceServer).RevertBanner(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BannerService_RevertBanner_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BannerServiceServer).RevertBanner(ctx, req.(*RevertBannerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// BannerService_ServiceDesc is the grpc.ServiceDesc for BannerService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var BannerService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "banner.v1.BannerService",
+	HandlerType: (*BannerServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateBanner",
+			Handler:    _BannerService_CreateBanner_Handler,
+		},
+		{
+			MethodName: "UpdateBanner",
+			Handler:    _BannerService_UpdateBanner_Handler,
+		},
+		{
+			MethodName: "DisplayBanner",
+			Handler:    _BannerService_DisplayBanner_Handler,
+		},
+		{
+			MethodName: "GetBanner",
+			Handler:    _BannerService_GetBanner_Handler,
+		},
+		{
+			MethodName: "ListBanners",
+			Handler:    _BannerService_ListBanners_Handler,
+		},
+		{
+			MethodName: "DeleteBanner",
+			Handler:    _BannerService_DeleteBanner_Handler,
+		},
+		{
+			MethodName: "ListBannerAudit",
+			Handler:    _BannerService_ListBannerAudit_Handler,
+		},
+		{
+			MethodName: "ListBannerRevisions",
+			Handler:    _BannerService_ListBannerRevisions_Handler,
+		},
+		{
+			MethodName: "DiffBannerRevisions",
+			Handler:    _BannerService_DiffBannerRevisions_Handler,
+		},
+		{
+			MethodName: "RevertBanner",
+			Handler:    _BannerService_RevertBanner_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "banner.proto",
+}
@@ -0,0 +1,5 @@
+// Package bannerpb contains protobuf messages and gRPC
+// stubs generated from banner.proto
+package bannerpb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative banner.proto
@@ -0,0 +1,578 @@
+// Package grpc exposes banner application service over gRPC
+//
+// The server does not authenticate its callers, it trusts the actor
+// metadata and the viewer sent with the request, so it must only be
+// reachable by the trusted backends
+package grpc
+
+import (
+	"context"
+	"errors"
+	"net/textproto"
+	"time"
+
+	domain "github.com/DzananGanic/banner"
+	"github.com/DzananGanic/banner/banner"
+	"github.com/DzananGanic/banner/grpc/bannerpb"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// NewServer creates new gRPC banner service server
+// on top of the given banner application service
+func NewServer(svc *banner.Service) *Server {
+	return &Server{svc: svc}
+}
+
+// Server represents gRPC banner service implementation
+type Server struct {
+	bannerpb.UnimplementedBannerServiceServer
+
+	svc *banner.Service
+}
+
+// CreateBanner creates new banner
+func (s *Server) CreateBanner(ctx context.Context, req *bannerpb.CreateBannerRequest) (*bannerpb.CreateBannerResponse, error) {
+	creq := &banner.CreateReq{
+		Name:                  req.GetName(),
+		ScheduledDisplayingAt: fromTimestamp(req.GetScheduledDisplayingAt()),
+		ExpiresAt:             fromTimestamp(req.GetExpiresAt()),
+		Weight:                int(req.GetWeight()),
+		Priority:              int(req.GetPriority()),
+		Audience:              fromProtoAudience(req.GetAudience()),
+		TimeZone:              req.GetTimeZone(),
+		Content:               fromProtoContent(req.GetContent()),
+		Overlap:               banner.OverlapMode(req.GetOverlap()),
+	}
+	var err error
+	if creq.ScheduledDisplayingAtWallClock, err = parseWallClock(req.GetScheduledDisplayingAtWallClock()); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if creq.ExpiresAtWallClock, err = parseWallClock(req.GetExpiresAtWallClock()); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if req.GetRecurrence() != "" {
+		if creq.Recurrence, err = domain.ParseRecurrence(req.GetRecurrence()); err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+	}
+	if err := creq.Validate(); err != nil {
+		return nil, toStatus(err)
+	}
+
+	resp, err := s.svc.Create(withActor(ctx), creq)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &bannerpb.CreateBannerResponse{
+		Id:        int64(resp.ID),
+		Conflicts: toProtoConflicts(resp.Conflicts),
+		Version:   int64(resp.Version),
+	}, nil
+}
+
+// UpdateBanner updates the fields of the existing banner which are set
+func (s *Server) UpdateBanner(ctx context.Context, req *bannerpb.UpdateBannerRequest) (*bannerpb.UpdateBannerResponse, error) {
+	ureq := &banner.UpdateReq{
+		ID:      domain.BannerID(req.GetId()),
+		Name:    req.Name,
+		Overlap: banner.OverlapMode(req.GetOverlap()),
+		Version: int(req.GetVersion()),
+	}
+	if req.ScheduledDisplayingAt != nil {
+		t := fromTimestamp(req.ScheduledDisplayingAt)
+		ureq.ScheduledDisplayingAt = &t
+	}
+	if req.ExpiresAt != nil {
+		t := fromTimestamp(req.ExpiresAt)
+		ureq.ExpiresAt = &t
+	}
+	if req.Weight != nil {
+		w := int(*req.Weight)
+		ureq.Weight = &w
+	}
+	if req.Priority != nil {
+		p := int(*req.Priority)
+		ureq.Priority = &p
+	}
+	if req.Audience != nil {
+		ureq.Audience = fromProtoAudience(req.Audience)
+		if ureq.Audience == nil {
+			ureq.Audience = &domain.Audience{}
+		}
+	}
+	ureq.TimeZone = req.TimeZone
+	var err error
+	if ureq.ScheduledDisplayingAtWallClock, err = parseWallClock(req.GetScheduledDisplayingAtWallClock()); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if ureq.ExpiresAtWallClock, err = parseWallClock(req.GetExpiresAtWallClock()); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if req.Content != nil {
+		ureq.Content = fromProtoContent(req.Content)
+	}
+	if req.Recurrence != nil {
+		ureq.Recurrence = &domain.Recurrence{}
+		if *req.Recurrence != "" {
+			if ureq.Recurrence, err = domain.ParseRecurrence(*req.Recurrence); err != nil {
+				return nil, status.Error(codes.InvalidArgument, err.Error())
+			}
+		}
+	}
+	if err := ureq.Validate(); err != nil {
+		return nil, toStatus(err)
+	}
+
+	resp, err := s.svc.Update(withActor(ctx), ureq)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &bannerpb.UpdateBannerResponse{
+		Conflicts: toProtoConflicts(resp.Conflicts),
+		Version:   int64(resp.Version),
+	}, nil
+}
+
+// DisplayBanner returns the banner that should be displayed to the viewer.
+// The viewer IP and user ID are taken as sent by the caller, so the caller
+// claiming the allowlisted IP is allowed to preview banners
+func (s *Server) DisplayBanner(ctx context.Context, req *bannerpb.DisplayBannerRequest) (*bannerpb.DisplayBannerResponse, error) {
+	v := req.GetViewer()
+	resp, err := s.svc.Display(ctx, &banner.DisplayReq{
+		Viewer: domain.Viewer{
+			IP:         v.GetIp(),
+			UserID:     v.GetUserId(),
+			Headers:    fromProtoHeaders(v.GetHeaders()),
+			Country:    v.GetCountry(),
+			Attributes: v.GetAttributes(),
+		},
+	})
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	dresp := &bannerpb.DisplayBannerResponse{Banner: toProto(resp.Banner)}
+	if resp.Content != nil {
+		dresp.Locale = resp.Locale
+		dresp.Content = toProtoContentVariant(*resp.Content)
+	}
+
+	return dresp, nil
+}
+
+// GetBanner returns the banner with the given ID
+func (s *Server) GetBanner(ctx context.Context, req *bannerpb.GetBannerRequest) (*bannerpb.GetBannerResponse, error) {
+	greq := &banner.GetReq{ID: domain.BannerID(req.GetId())}
+	if err := greq.Validate(); err != nil {
+		return nil, toStatus(err)
+	}
+
+	resp, err := s.svc.Get(ctx, greq)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &bannerpb.GetBannerResponse{Banner: toProto(resp.Banner)}, nil
+}
+
+// ListBanners returns filtered, sorted and paginated banners
+func (s *Server) ListBanners(ctx context.Context, req *bannerpb.ListBannersRequest) (*bannerpb.ListBannersResponse, error) {
+	lreq := &banner.ListReq{
+		Status: domain.BannerStatus(req.GetStatus()),
+		From:   fromTimestamp(req.GetFrom()),
+		To:     fromTimestamp(req.GetTo()),
+		SortBy: banner.ListSort(req.GetSortBy()),
+		Desc:   req.GetDesc(),
+		Offset: int(req.GetOffset()),
+		Limit:  int(req.GetLimit()),
+	}
+	if err := lreq.Validate(); err != nil {
+		return nil, toStatus(err)
+	}
+
+	resp, err := s.svc.List(ctx, lreq)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	banners := make([]*bannerpb.Banner, 0, len(resp.Banners))
+	for _, b := range resp.Banners {
+		banners = append(banners, toProto(b))
+	}
+
+	return &bannerpb.ListBannersResponse{
+		Banners: banners,
+		Total:   int32(resp.Total),
+	}, nil
+}
+
+// DeleteBanner deletes the banner with the given ID
+func (s *Server) DeleteBanner(ctx context.Context, req *bannerpb.DeleteBannerRequest) (*bannerpb.DeleteBannerResponse, error) {
+	dreq := &banner.DeleteReq{ID: domain.BannerID(req.GetId())}
+	if err := dreq.Validate(); err != nil {
+		return nil, toStatus(err)
+	}
+
+	err := s.svc.Delete(withActor(ctx), dreq)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &bannerpb.DeleteBannerResponse{}, nil
+}
+
+// ListBannerAudit lists the changes made to the banner, oldest first
+func (s *Server) ListBannerAudit(ctx context.Context, req *bannerpb.ListBannerAuditRequest) (*bannerpb.ListBannerAuditResponse, error) {
+	areq := &banner.AuditReq{BannerID: domain.BannerID(req.GetBannerId())}
+	if err := areq.Validate(); err != nil {
+		return nil, toStatus(err)
+	}
+
+	resp, err := s.svc.Audit(ctx, areq)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	entries := make([]*bannerpb.AuditEntry, 0, len(resp.Entries))
+	for _, e := range resp.Entries {
+		entries = append(entries, toProtoAuditEntry(e))
+	}
+
+	return &bannerpb.ListBannerAuditResponse{Entries: entries}, nil
+}
+
+// ListBannerRevisions lists the past revisions of the banner, oldest first
+func (s *Server) ListBannerRevisions(ctx context.Context, req *bannerpb.ListBannerRevisionsRequest) (*bannerpb.ListBannerRevisionsResponse, error) {
+	hreq := &banner.HistoryReq{ID: domain.BannerID(req.GetId())}
+	if err := hreq.Validate(); err != nil {
+		return nil, toStatus(err)
+	}
+
+	resp, err := s.svc.History(ctx, hreq)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	revs := make([]*bannerpb.Revision, 0, len(resp.Revisions))
+	for _, r := range resp.Revisions {
+		revs = append(revs, &bannerpb.Revision{
+			Version: int64(r.Version),
+			At:      toTimestamp(r.At),
+			Actor:   r.Actor,
+			Banner:  toProto(r.Banner),
+		})
+	}
+
+	return &bannerpb.ListBannerRevisionsResponse{Revisions: revs}, nil
+}
+
+// DiffBannerRevisions returns the changes made to the banner between its revisions
+func (s *Server) DiffBannerRevisions(ctx context.Context, req *bannerpb.DiffBannerRevisionsRequest) (*bannerpb.DiffBannerRevisionsResponse, error) {
+	dreq := &banner.DiffReq{
+		ID:   domain.BannerID(req.GetId()),
+		From: int(req.GetFrom()),
+		To:   int(req.GetTo()),
+	}
+	if err := dreq.Validate(); err != nil {
+		return nil, toStatus(err)
+	}
+
+	resp, err := s.svc.Diff(ctx, dreq)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	changes := make([]*bannerpb.FieldChange, 0, len(resp.Changes))
+	for _, c := range resp.Changes {
+		changes = append(changes, &bannerpb.FieldChange{Field: c.Field, From: c.From, To: c.To})
+	}
+
+	return &bannerpb.DiffBannerRevisionsResponse{Changes: changes}, nil
+}
+
+// RevertBanner restores the past revision of the banner
+func (s *Server) RevertBanner(ctx context.Context, req *bannerpb.RevertBannerRequest) (*bannerpb.RevertBannerResponse, error) {
+	rreq := &banner.RevertReq{
+		ID:       domain.BannerID(req.GetId()),
+		Revision: int(req.GetRevision()),
+		Overlap:  banner.OverlapMode(req.GetOverlap()),
+		Version:  int(req.GetVersion()),
+	}
+	if err := rreq.Validate(); err != nil {
+		return nil, toStatus(err)
+	}
+
+	resp, err := s.svc.Revert(withActor(ctx), rreq)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &bannerpb.RevertBannerResponse{
+		Conflicts: toProtoConflicts(resp.Conflicts),
+		Version:   int64(resp.Version),
+	}, nil
+}
+
+// actorMetadataKey is the request metadata key holding who makes the
+// change, as set by the authenticating client or proxy in front of the
+// service. It is not verified, any caller may set it to any actor
+const actorMetadataKey = "x-actor"
+
+// withActor returns the context carrying the actor from the request metadata
+func withActor(ctx context.Context) context.Context {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if actors := md.Get(actorMetadataKey); len(actors) > 0 && actors[0] != "" {
+		return domain.WithActor(ctx, actors[0])
+	}
+
+	return ctx
+}
+
+// toStatus maps the service error to the gRPC status
+func toStatus(err error) error {
+	var (
+		verr     *domain.ValidationError
+		conflict *banner.ConflictError
+		version  *domain.VersionConflictError
+	)
+	switch {
+	case errors.As(err, &verr):
+		br := &errdetails.BadRequest{}
+		for _, f := range verr.Fields {
+			br.FieldViolations = append(br.FieldViolations, &errdetails.BadRequest_FieldViolation{
+				Field:       f.Field,
+				Description: f.Message,
+			})
+		}
+		st := status.New(codes.InvalidArgument, err.Error())
+		if detailed, derr := st.WithDetails(br); derr == nil {
+			st = detailed
+		}
+		return st.Err()
+	case errors.As(err, &conflict):
+		st := status.New(codes.FailedPrecondition, err.Error())
+		if detailed, derr := st.WithDetails(&bannerpb.ConflictReport{Conflicts: toProtoConflicts(conflict.Conflicts)}); derr == nil {
+			st = detailed
+		}
+		return st.Err()
+	case errors.As(err, &version):
+		// the client is expected to retry on the current version
+		return status.Error(codes.Aborted, err.Error())
+	case errors.Is(err, domain.ErrConflict):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, domain.ErrNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, domain.ErrNoActiveBanner):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, banner.ErrAuditDisabled):
+		return status.Error(codes.Unimplemented, err.Error())
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, err.Error())
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	}
+
+	return status.Error(codes.Internal, "internal error")
+}
+
+func toProto(b domain.Banner) *bannerpb.Banner {
+	return &bannerpb.Banner{
+		Id:                    int64(b.ID),
+		Name:                  b.Name,
+		CreatedAt:             toTimestamp(b.CreatedAt),
+		ScheduledDisplayingAt: toTimestamp(b.ScheduledDisplayingAt),
+		ExpiresAt:             toTimestamp(b.ExpiresAt),
+		Weight:                int32(b.Weight),
+		Priority:              int32(b.Priority),
+		Audience:              toProtoAudience(b.Audience),
+		TimeZone:              b.TimeZone,
+		Recurrence:            formatRecurrence(b.Recurrence),
+		Content:               toProtoContent(b.Content),
+		Version:               int64(b.Version),
+	}
+}
+
+func toProtoAuditEntry(e domain.AuditEntry) *bannerpb.AuditEntry {
+	pe := &bannerpb.AuditEntry{
+		Id:       e.ID,
+		BannerId: int64(e.BannerID),
+		Action:   string(e.Action),
+		Actor:    e.Actor,
+		At:       toTimestamp(e.At),
+	}
+	if e.Before != nil {
+		pe.Before = toProto(*e.Before)
+	}
+	if e.After != nil {
+		pe.After = toProto(*e.After)
+	}
+
+	return pe
+}
+
+func toProtoConflicts(conflicts []banner.Conflict) []*bannerpb.Conflict {
+	out := make([]*bannerpb.Conflict, 0, len(conflicts))
+	for _, c := range conflicts {
+		out = append(out, &bannerpb.Conflict{
+			BannerId: int64(c.BannerID),
+			Name:     c.Name,
+			From:     toTimestamp(c.Interval.From),
+			To:       toTimestamp(c.Interval.To),
+		})
+	}
+
+	return out
+}
+
+func toProtoContent(c *domain.Content) *bannerpb.Content {
+	if c.IsEmpty() {
+		return nil
+	}
+
+	variants := make(map[string]*bannerpb.ContentVariant, len(c.Variants))
+	for l, v := range c.Variants {
+		variants[l] = toProtoContentVariant(v)
+	}
+
+	return &bannerpb.Content{FallbackLocale: c.FallbackLocale, Variants: variants}
+}
+
+func toProtoContentVariant(v domain.ContentVariant) *bannerpb.ContentVariant {
+	return &bannerpb.ContentVariant{
+		Title:        v.Title,
+		Body:         v.Body,
+		ImageUrl:     v.ImageURL,
+		LinkUrl:      v.LinkURL,
+		CallToAction: v.CallToAction,
+		AltText:      v.AltText,
+		Theme:        v.Theme,
+	}
+}
+
+func fromProtoContent(c *bannerpb.Content) *domain.Content {
+	if c == nil {
+		return nil
+	}
+
+	content := &domain.Content{FallbackLocale: c.GetFallbackLocale()}
+	if len(c.GetVariants()) > 0 {
+		content.Variants = make(map[string]domain.ContentVariant, len(c.GetVariants()))
+		for l, v := range c.GetVariants() {
+			content.Variants[l] = domain.ContentVariant{
+				Title:        v.GetTitle(),
+				Body:         v.GetBody(),
+				ImageURL:     v.GetImageUrl(),
+				LinkURL:      v.GetLinkUrl(),
+				CallToAction: v.GetCallToAction(),
+				AltText:      v.GetAltText(),
+				Theme:        v.GetTheme(),
+			}
+		}
+	}
+
+	return content
+}
+
+func formatRecurrence(r *domain.Recurrence) string {
+	if r == nil {
+		return ""
+	}
+	return r.String()
+}
+
+// parseWallClock parses the wall clock time, leaving the empty one unset
+func parseWallClock(s string) (*domain.WallClock, error) {
+	if s == "" {
+		return nil, nil
+	}
+
+	w, err := domain.ParseWallClock(s)
+	if err != nil {
+		return nil, err
+	}
+
+	return &w, nil
+}
+
+func toProtoAudience(a *domain.Audience) *bannerpb.Audience {
+	if a.IsEmpty() {
+		return nil
+	}
+
+	pa := &bannerpb.Audience{
+		Locales:       a.Locales,
+		Countries:     a.Countries,
+		Authenticated: a.Authenticated,
+		Attributes:    a.Attributes,
+	}
+	for _, p := range a.Platforms {
+		pa.Platforms = append(pa.Platforms, string(p))
+	}
+
+	return pa
+}
+
+// fromProtoAudience converts the protobuf audience,
+// returning nil for the one without criteria
+func fromProtoAudience(pa *bannerpb.Audience) *domain.Audience {
+	if pa == nil {
+		return nil
+	}
+
+	a := &domain.Audience{
+		Locales:       pa.GetLocales(),
+		Countries:     pa.GetCountries(),
+		Authenticated: pa.Authenticated,
+		Attributes:    pa.GetAttributes(),
+	}
+	for _, p := range pa.GetPlatforms() {
+		a.Platforms = append(a.Platforms, domain.Platform(p))
+	}
+	if a.IsEmpty() {
+		return nil
+	}
+
+	return a
+}
+
+// toTimestamp converts the time to protobuf timestamp,
+// leaving zero time unset
+// fromProtoHeaders canonicalizes the header names, as gRPC clients
+// usually send them lowercased like the metadata keys, while the
+// viewer looks them up in the canonical form, e.g. Accept-Language
+func fromProtoHeaders(h map[string]string) map[string]string {
+	if len(h) == 0 {
+		return nil
+	}
+
+	headers := make(map[string]string, len(h))
+	for k, v := range h {
+		headers[textproto.CanonicalMIMEHeaderKey(k)] = v
+	}
+
+	return headers
+}
+
+func toTimestamp(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+
+	return timestamppb.New(t)
+}
+
+// fromTimestamp converts protobuf timestamp to time,
+// returning zero time for unset timestamp
+func fromTimestamp(ts *timestamppb.Timestamp) time.Time {
+	if ts == nil {
+		return time.Time{}
+	}
+
+	return ts.AsTime()
+}
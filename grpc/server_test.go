@@ -0,0 +1,481 @@
+package grpc_test
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	domain "github.com/DzananGanic/banner"
+	"github.com/DzananGanic/banner/banner"
+	bannergrpc "github.com/DzananGanic/banner/grpc"
+	"github.com/DzananGanic/banner/grpc/bannerpb"
+	"github.com/DzananGanic/banner/mock"
+	"github.com/DzananGanic/banner/platform/memory"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestCreateBanner(t *testing.T) {
+	cases := []struct {
+		name     string
+		req      *bannerpb.CreateBannerRequest
+		wantID   int64
+		wantCode codes.Code
+	}{
+		{
+			name: "test successfully create",
+			req: &bannerpb.CreateBannerRequest{
+				Name:                  "banner",
+				ScheduledDisplayingAt: timestamppb.New(time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)),
+				ExpiresAt:             timestamppb.New(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)),
+			},
+			wantID:   1,
+			wantCode: codes.OK,
+		},
+		{
+			name: "test validation error",
+			req: &bannerpb.CreateBannerRequest{
+				Name: "banner",
+			},
+			wantCode: codes.InvalidArgument,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			client, _ := newClient(t, nil)
+
+			resp, err := client.CreateBanner(context.Background(), c.req)
+			assert.Equal(t, c.wantCode, status.Code(err))
+			assert.Equal(t, c.wantID, resp.GetId())
+		})
+	}
+}
+
+func TestCreateBannerValidationDetails(t *testing.T) {
+	client, _ := newClient(t, nil)
+
+	_, err := client.CreateBanner(context.Background(), &bannerpb.CreateBannerRequest{Weight: -1})
+	st := status.Convert(err)
+	assert.Equal(t, codes.InvalidArgument, st.Code())
+
+	var fields []string
+	for _, d := range st.Details() {
+		if br, ok := d.(*errdetails.BadRequest); ok {
+			for _, v := range br.GetFieldViolations() {
+				fields = append(fields, v.GetField())
+			}
+		}
+	}
+	assert.Equal(t, []string{"name", "scheduled_displaying_at", "expires_at", "weight"}, fields)
+}
+
+func TestUpdateBanner(t *testing.T) {
+	cases := []struct {
+		name     string
+		req      *bannerpb.UpdateBannerRequest
+		wantCode codes.Code
+		wantName string
+	}{
+		{
+			name:     "test successfully update",
+			req:      &bannerpb.UpdateBannerRequest{Id: 1, Name: proto.String("updated")},
+			wantCode: codes.OK,
+			wantName: "updated",
+		},
+		{
+			name:     "test missing id",
+			req:      &bannerpb.UpdateBannerRequest{Name: proto.String("updated")},
+			wantCode: codes.InvalidArgument,
+			wantName: "banner",
+		},
+		{
+			name:     "test not found",
+			req:      &bannerpb.UpdateBannerRequest{Id: 5, Name: proto.String("updated")},
+			wantCode: codes.NotFound,
+			wantName: "banner",
+		},
+		{
+			name:     "test successfully update current version",
+			req:      &bannerpb.UpdateBannerRequest{Id: 1, Name: proto.String("updated"), Version: 1},
+			wantCode: codes.OK,
+			wantName: "updated",
+		},
+		{
+			name:     "test stale version",
+			req:      &bannerpb.UpdateBannerRequest{Id: 1, Name: proto.String("updated"), Version: 3},
+			wantCode: codes.Aborted,
+			wantName: "banner",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			client, db := newClient(t, nil)
+			_, err := db.Save(domain.Banner{
+				Name:                  "banner",
+				ScheduledDisplayingAt: time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC),
+				ExpiresAt:             time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+			})
+			assert.Nil(t, err)
+
+			_, err = client.UpdateBanner(context.Background(), c.req)
+			assert.Equal(t, c.wantCode, status.Code(err))
+
+			b, err := db.FetchForID(1)
+			assert.Nil(t, err)
+			assert.Equal(t, c.wantName, b.Name)
+		})
+	}
+}
+
+func TestDisplayBanner(t *testing.T) {
+	cases := []struct {
+		name       string
+		display    func(domain.Viewer) (*domain.Banner, error)
+		wantCode   codes.Code
+		wantBanner *bannerpb.Banner
+	}{
+		{
+			name: "test display banner",
+			display: func(v domain.Viewer) (*domain.Banner, error) {
+				return &domain.Banner{
+					ID:        1,
+					Name:      "banner",
+					ExpiresAt: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+				}, nil
+			},
+			wantCode: codes.OK,
+			wantBanner: &bannerpb.Banner{
+				Id:        1,
+				Name:      "banner",
+				ExpiresAt: timestamppb.New(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)),
+			},
+		},
+		{
+			name: "test no active banner",
+			display: func(v domain.Viewer) (*domain.Banner, error) {
+				return nil, domain.ErrNoActiveBanner
+			},
+			wantCode: codes.NotFound,
+		},
+		{
+			name: "test internal error",
+			display: func(v domain.Viewer) (*domain.Banner, error) {
+				return nil, fmt.Errorf("database error")
+			},
+			wantCode: codes.Internal,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			client, _ := newClient(t, c.display)
+
+			resp, err := client.DisplayBanner(context.Background(), &bannerpb.DisplayBannerRequest{})
+			assert.Equal(t, c.wantCode, status.Code(err))
+			if c.wantBanner != nil {
+				assert.True(t, proto.Equal(c.wantBanner, resp.GetBanner()))
+			}
+		})
+	}
+}
+
+func TestGetBanner(t *testing.T) {
+	cases := []struct {
+		name     string
+		id       int64
+		wantCode codes.Code
+	}{
+		{
+			name:     "test get banner",
+			id:       1,
+			wantCode: codes.OK,
+		},
+		{
+			name:     "test missing id",
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "test not found",
+			id:       5,
+			wantCode: codes.NotFound,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			client, db := newClient(t, nil)
+			_, err := db.Save(domain.Banner{Name: "banner"})
+			assert.Nil(t, err)
+
+			resp, err := client.GetBanner(context.Background(), &bannerpb.GetBannerRequest{Id: c.id})
+			assert.Equal(t, c.wantCode, status.Code(err))
+			if c.wantCode == codes.OK {
+				assert.Equal(t, "banner", resp.GetBanner().GetName())
+			}
+		})
+	}
+}
+
+func TestListBanners(t *testing.T) {
+	cases := []struct {
+		name      string
+		req       *bannerpb.ListBannersRequest
+		wantCode  codes.Code
+		wantNames []string
+		wantTotal int32
+	}{
+		{
+			name:      "test list all",
+			req:       &bannerpb.ListBannersRequest{},
+			wantCode:  codes.OK,
+			wantNames: []string{"expired", "active"},
+			wantTotal: 2,
+		},
+		{
+			name:      "test filter by status",
+			req:       &bannerpb.ListBannersRequest{Status: "expired"},
+			wantCode:  codes.OK,
+			wantNames: []string{"expired"},
+			wantTotal: 1,
+		},
+		{
+			name:      "test sort and paginate",
+			req:       &bannerpb.ListBannersRequest{SortBy: "name", Limit: 1},
+			wantCode:  codes.OK,
+			wantNames: []string{"active"},
+			wantTotal: 2,
+		},
+		{
+			name:     "test invalid sort",
+			req:      &bannerpb.ListBannersRequest{SortBy: "color"},
+			wantCode: codes.InvalidArgument,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			client, db := newClient(t, nil)
+			for _, b := range []domain.Banner{
+				{
+					Name:                  "expired",
+					ScheduledDisplayingAt: time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC),
+					ExpiresAt:             time.Date(2001, 1, 1, 0, 0, 0, 0, time.UTC),
+				},
+				{
+					Name:                  "active",
+					ScheduledDisplayingAt: time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC),
+					ExpiresAt:             time.Date(2100, 1, 1, 0, 0, 0, 0, time.UTC),
+				},
+			} {
+				_, err := db.Save(b)
+				assert.Nil(t, err)
+			}
+
+			resp, err := client.ListBanners(context.Background(), c.req)
+			assert.Equal(t, c.wantCode, status.Code(err))
+			if c.wantCode != codes.OK {
+				return
+			}
+
+			names := []string{}
+			for _, b := range resp.GetBanners() {
+				names = append(names, b.GetName())
+			}
+			assert.Equal(t, c.wantNames, names)
+			assert.Equal(t, c.wantTotal, resp.GetTotal())
+		})
+	}
+}
+
+func TestDeleteBanner(t *testing.T) {
+	cases := []struct {
+		name     string
+		id       int64
+		wantCode codes.Code
+		wantLeft int
+	}{
+		{
+			name:     "test delete banner",
+			id:       1,
+			wantCode: codes.OK,
+			wantLeft: 0,
+		},
+		{
+			name:     "test not found",
+			id:       5,
+			wantCode: codes.NotFound,
+			wantLeft: 1,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			client, db := newClient(t, nil)
+			_, err := db.Save(domain.Banner{Name: "banner"})
+			assert.Nil(t, err)
+
+			_, err = client.DeleteBanner(context.Background(), &bannerpb.DeleteBannerRequest{Id: c.id})
+			assert.Equal(t, c.wantCode, status.Code(err))
+
+			banners, err := db.List()
+			assert.Nil(t, err)
+			assert.Len(t, banners, c.wantLeft)
+		})
+	}
+}
+
+func TestDisplayBannerViewer(t *testing.T) {
+	var got domain.Viewer
+	client, _ := newClient(t, func(v domain.Viewer) (*domain.Banner, error) {
+		got = v
+		return &domain.Banner{ID: 1}, nil
+	})
+
+	_, err := client.DisplayBanner(context.Background(), &bannerpb.DisplayBannerRequest{
+		Viewer: &bannerpb.Viewer{
+			Ip:      "198.51.100.2",
+			UserId:  "42",
+			Headers: map[string]string{"Accept-Language": "bs"},
+		},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, domain.Viewer{
+		IP:      "198.51.100.2",
+		UserID:  "42",
+		Headers: map[string]string{"Accept-Language": "bs"},
+	}, got)
+}
+
+func TestDisplayBannerViewerLowercaseHeaders(t *testing.T) {
+	var got domain.Viewer
+	client, _ := newClient(t, func(v domain.Viewer) (*domain.Banner, error) {
+		got = v
+		return &domain.Banner{ID: 1}, nil
+	})
+
+	_, err := client.DisplayBanner(context.Background(), &bannerpb.DisplayBannerRequest{
+		Viewer: &bannerpb.Viewer{
+			Headers: map[string]string{
+				"accept-language": "bs",
+				"user-agent":      "Mozilla/5.0 (iPhone; CPU iPhone OS 17_0 like Mac OS X) Mobile/15E148",
+			},
+		},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"bs"}, got.Locales())
+	assert.Equal(t, domain.PlatformMobile, got.Platform())
+}
+
+func TestListBannerAudit(t *testing.T) {
+	client, _ := newClient(t, nil)
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "x-actor", "alice")
+
+	created, err := client.CreateBanner(ctx, &bannerpb.CreateBannerRequest{
+		Name:                  "banner",
+		ScheduledDisplayingAt: timestamppb.New(time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)),
+		ExpiresAt:             timestamppb.New(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)),
+	})
+	assert.Nil(t, err)
+	_, err = client.DeleteBanner(ctx, &bannerpb.DeleteBannerRequest{Id: created.GetId()})
+	assert.Nil(t, err)
+
+	resp, err := client.ListBannerAudit(context.Background(), &bannerpb.ListBannerAuditRequest{BannerId: created.GetId()})
+	assert.Nil(t, err)
+	assert.Len(t, resp.GetEntries(), 2)
+	for i, action := range []string{"create", "delete"} {
+		e := resp.GetEntries()[i]
+		assert.Equal(t, action, e.GetAction())
+		assert.Equal(t, "alice", e.GetActor())
+	}
+	assert.Equal(t, "banner", resp.GetEntries()[1].GetBefore().GetName())
+	assert.Nil(t, resp.GetEntries()[1].GetAfter())
+
+	_, err = client.ListBannerAudit(context.Background(), &bannerpb.ListBannerAuditRequest{})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestBannerRevisions(t *testing.T) {
+	client, db := newClient(t, nil)
+	ctx := context.Background()
+
+	created, err := client.CreateBanner(ctx, &bannerpb.CreateBannerRequest{
+		Name:                  "banner",
+		ScheduledDisplayingAt: timestamppb.New(time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)),
+		ExpiresAt:             timestamppb.New(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)),
+	})
+	assert.Nil(t, err)
+	name := "renamed"
+	_, err = client.UpdateBanner(ctx, &bannerpb.UpdateBannerRequest{Id: created.GetId(), Name: &name})
+	assert.Nil(t, err)
+
+	revs, err := client.ListBannerRevisions(ctx, &bannerpb.ListBannerRevisionsRequest{Id: created.GetId()})
+	assert.Nil(t, err)
+	assert.Len(t, revs.GetRevisions(), 2)
+	assert.Equal(t, int64(2), revs.GetRevisions()[1].GetVersion())
+	assert.Equal(t, "renamed", revs.GetRevisions()[1].GetBanner().GetName())
+
+	diff, err := client.DiffBannerRevisions(ctx, &bannerpb.DiffBannerRevisionsRequest{Id: created.GetId(), From: 1, To: 2})
+	assert.Nil(t, err)
+	assert.Len(t, diff.GetChanges(), 1)
+	assert.Equal(t, "name", diff.GetChanges()[0].GetField())
+	assert.Equal(t, "banner", diff.GetChanges()[0].GetFrom())
+	assert.Equal(t, "renamed", diff.GetChanges()[0].GetTo())
+
+	_, err = client.RevertBanner(ctx, &bannerpb.RevertBannerRequest{Id: created.GetId(), Revision: 1, Version: 1})
+	assert.Equal(t, codes.Aborted, status.Code(err))
+
+	reverted, err := client.RevertBanner(ctx, &bannerpb.RevertBannerRequest{Id: created.GetId(), Revision: 1, Version: 2})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(3), reverted.GetVersion())
+	b, err := db.FetchForID(domain.BannerID(created.GetId()))
+	assert.Nil(t, err)
+	assert.Equal(t, "banner", b.Name)
+
+	_, err = client.RevertBanner(ctx, &bannerpb.RevertBannerRequest{Id: created.GetId(), Revision: 9})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func newClient(t *testing.T, display func(domain.Viewer) (*domain.Banner, error)) (bannerpb.BannerServiceClient, *memory.BannerDB) {
+	t.Helper()
+
+	db := memory.NewBannerDB()
+	svc := banner.New(
+		db,
+		memory.NewActiveBannerProvider(),
+		&mock.BannerDisplayer{DisplayBannerFn: display},
+		mock.NewClock(time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)),
+		banner.WithAuditStore(memory.NewAuditStore()),
+	)
+
+	lis := bufconn.Listen(1024 * 1024)
+	srv := grpc.NewServer()
+	bannerpb.RegisterBannerServiceServer(srv, bannergrpc.NewServer(svc))
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient(
+		"passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("failed to dial bufnet: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return bannerpb.NewBannerServiceClient(conn), db
+}
@@ -0,0 +1,871 @@
+// Package http exposes banner application service
+// over HTTP with JSON encoded requests and responses
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"time"
+
+	domain "github.com/DzananGanic/banner"
+	"github.com/DzananGanic/banner/banner"
+)
+
+// NewHandler creates new HTTP handler which serves the banner
+// API on top of the given service. Trusted proxies are the ones
+// whose X-Forwarded-For header is relied upon, see ParseTrustedProxies
+func NewHandler(svc *banner.Service, trustedProxies []netip.Prefix) *Handler {
+	h := &Handler{
+		svc:            svc,
+		trustedProxies: trustedProxies,
+		mux:            http.NewServeMux(),
+	}
+
+	h.mux.HandleFunc("POST /banners", h.create)
+	h.mux.HandleFunc("PATCH /banners/{id}", h.update)
+	h.mux.HandleFunc("GET /banners/display", h.display)
+	h.mux.HandleFunc("GET /banners/{id}", h.get)
+	h.mux.HandleFunc("GET /banners/{id}/audit", h.audit)
+	h.mux.HandleFunc("GET /banners/{id}/revisions", h.revisions)
+	h.mux.HandleFunc("GET /banners/{id}/diff", h.diff)
+	h.mux.HandleFunc("POST /banners/{id}/revert", h.revert)
+	h.mux.HandleFunc("GET /banners", h.list)
+	h.mux.HandleFunc("DELETE /banners/{id}", h.delete)
+
+	return h
+}
+
+// Handler represents banner API HTTP handler
+type Handler struct {
+	svc            *banner.Service
+	trustedProxies []netip.Prefix
+	mux            *http.ServeMux
+}
+
+// ServeHTTP dispatches the request to the matching endpoint. The
+// changes are attributed to the user authenticated by the trusted
+// proxy, and to no one if the request did not come through it
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if _, proxied := h.clientIP(r); proxied {
+		if actor := r.Header.Get(userIDHeader); actor != "" {
+			r = r.WithContext(domain.WithActor(r.Context(), actor))
+		}
+	}
+
+	h.mux.ServeHTTP(w, r)
+}
+
+// bannerJSON represents JSON encoding of the domain banner
+type bannerJSON struct {
+	ID                    domain.BannerID `json:"id"`
+	Name                  string          `json:"name"`
+	CreatedAt             time.Time       `json:"created_at"`
+	ScheduledDisplayingAt time.Time       `json:"scheduled_displaying_at"`
+	ExpiresAt             time.Time       `json:"expires_at"`
+	Weight                int             `json:"weight"`
+	Priority              int             `json:"priority"`
+	Audience              *audienceJSON   `json:"audience,omitempty"`
+	TimeZone              string          `json:"time_zone,omitempty"`
+	Recurrence            string          `json:"recurrence,omitempty"`
+	Content               *contentJSON    `json:"content,omitempty"`
+	Version               int             `json:"version"`
+}
+
+func newBannerJSON(b domain.Banner) bannerJSON {
+	return bannerJSON{
+		ID:                    b.ID,
+		Name:                  b.Name,
+		CreatedAt:             b.CreatedAt,
+		ScheduledDisplayingAt: b.ScheduledDisplayingAt,
+		ExpiresAt:             b.ExpiresAt,
+		Weight:                b.Weight,
+		Priority:              b.Priority,
+		Audience:              newAudienceJSON(b.Audience),
+		TimeZone:              b.TimeZone,
+		Recurrence:            formatRecurrence(b.Recurrence),
+		Content:               newContentJSON(b.Content),
+		Version:               b.Version,
+	}
+}
+
+// contentJSON represents JSON encoding of the banner content
+type contentJSON struct {
+	FallbackLocale string                        `json:"fallback_locale"`
+	Variants       map[string]contentVariantJSON `json:"variants"`
+}
+
+// contentVariantJSON represents JSON encoding of the content in a single locale
+type contentVariantJSON struct {
+	Title        string `json:"title,omitempty"`
+	Body         string `json:"body,omitempty"`
+	ImageURL     string `json:"image_url,omitempty"`
+	LinkURL      string `json:"link_url,omitempty"`
+	CallToAction string `json:"call_to_action,omitempty"`
+	AltText      string `json:"alt_text,omitempty"`
+	Theme        string `json:"theme,omitempty"`
+}
+
+func newContentJSON(c *domain.Content) *contentJSON {
+	if c.IsEmpty() {
+		return nil
+	}
+
+	variants := make(map[string]contentVariantJSON, len(c.Variants))
+	for l, v := range c.Variants {
+		variants[l] = contentVariantJSON(v)
+	}
+
+	return &contentJSON{FallbackLocale: c.FallbackLocale, Variants: variants}
+}
+
+func (c *contentJSON) toDomain() *domain.Content {
+	if c == nil {
+		return nil
+	}
+
+	content := &domain.Content{FallbackLocale: c.FallbackLocale}
+	if len(c.Variants) > 0 {
+		content.Variants = make(map[string]domain.ContentVariant, len(c.Variants))
+		for l, v := range c.Variants {
+			content.Variants[l] = domain.ContentVariant(v)
+		}
+	}
+
+	return content
+}
+
+func formatRecurrence(r *domain.Recurrence) string {
+	if r == nil {
+		return ""
+	}
+	return r.String()
+}
+
+// recurrenceRule is the banner recurrence in RRULE-like format, e.g.
+// FREQ=WEEKLY;BYDAY=MO,TU;BYHOUR=11;DURATION=PT3H, see domain.ParseRecurrence,
+// the empty rule stands for no recurrence
+type recurrenceRule struct {
+	recurrence *domain.Recurrence
+}
+
+func (rr *recurrenceRule) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+
+	if s == "" {
+		rr.recurrence = &domain.Recurrence{}
+		return nil
+	}
+
+	r, err := domain.ParseRecurrence(s)
+	if err != nil {
+		return err
+	}
+	rr.recurrence = r
+
+	return nil
+}
+
+func (rr *recurrenceRule) toDomain() *domain.Recurrence {
+	if rr == nil {
+		return nil
+	}
+	return rr.recurrence
+}
+
+// audienceJSON represents JSON encoding of the banner audience
+type audienceJSON struct {
+	Locales       []string          `json:"locales,omitempty"`
+	Countries     []string          `json:"countries,omitempty"`
+	Platforms     []domain.Platform `json:"platforms,omitempty"`
+	Authenticated *bool             `json:"authenticated,omitempty"`
+	Attributes    map[string]string `json:"attributes,omitempty"`
+}
+
+func newAudienceJSON(a *domain.Audience) *audienceJSON {
+	if a.IsEmpty() {
+		return nil
+	}
+
+	return &audienceJSON{
+		Locales:       a.Locales,
+		Countries:     a.Countries,
+		Platforms:     a.Platforms,
+		Authenticated: a.Authenticated,
+		Attributes:    a.Attributes,
+	}
+}
+
+func (a *audienceJSON) toDomain() *domain.Audience {
+	if a == nil {
+		return nil
+	}
+
+	return &domain.Audience{
+		Locales:       a.Locales,
+		Countries:     a.Countries,
+		Platforms:     a.Platforms,
+		Authenticated: a.Authenticated,
+		Attributes:    a.Attributes,
+	}
+}
+
+type createReq struct {
+	Name                  string          `json:"name"`
+	ScheduledDisplayingAt scheduleTime    `json:"scheduled_displaying_at"`
+	ExpiresAt             scheduleTime    `json:"expires_at"`
+	Weight                int             `json:"weight"`
+	Priority              int             `json:"priority"`
+	Audience              *audienceJSON   `json:"audience"`
+	TimeZone              string          `json:"time_zone"`
+	Recurrence            *recurrenceRule `json:"recurrence"`
+	Content               *contentJSON    `json:"content"`
+
+	// Overlap is warn or reject to check for overlaps with other banners
+	Overlap banner.OverlapMode `json:"overlap"`
+}
+
+// scheduleTime is the time of the banner schedule, which is either
+// RFC 3339 instant or, without the offset, e.g. 2019-06-03T09:00,
+// the wall clock time in the banner time zone
+type scheduleTime struct {
+	instant   time.Time
+	wallClock *domain.WallClock
+}
+
+func (st *scheduleTime) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+
+	if t, err := time.Parse(time.RFC3339Nano, s); err == nil {
+		st.instant = t
+		return nil
+	}
+
+	w, err := domain.ParseWallClock(s)
+	if err != nil {
+		return fmt.Errorf("%q must be RFC 3339 time or wall clock time", s)
+	}
+	st.wallClock = &w
+
+	return nil
+}
+
+type createResp struct {
+	ID        domain.BannerID `json:"id"`
+	Conflicts []conflictJSON  `json:"conflicts,omitempty"`
+}
+
+// conflictJSON represents JSON encoding of the overlap with other banner
+type conflictJSON struct {
+	BannerID domain.BannerID `json:"banner_id"`
+	Name     string          `json:"name"`
+	From     time.Time       `json:"from"`
+	To       time.Time       `json:"to"`
+}
+
+func newConflictsJSON(conflicts []banner.Conflict) []conflictJSON {
+	if len(conflicts) == 0 {
+		return nil
+	}
+
+	out := make([]conflictJSON, 0, len(conflicts))
+	for _, c := range conflicts {
+		out = append(out, conflictJSON{
+			BannerID: c.BannerID,
+			Name:     c.Name,
+			From:     c.Interval.From,
+			To:       c.Interval.To,
+		})
+	}
+
+	return out
+}
+
+func (h *Handler) create(w http.ResponseWriter, r *http.Request) {
+	var body createReq
+	if !decode(w, r, &body) {
+		return
+	}
+
+	req := &banner.CreateReq{
+		Name:                           body.Name,
+		ScheduledDisplayingAt:          body.ScheduledDisplayingAt.instant,
+		ScheduledDisplayingAtWallClock: body.ScheduledDisplayingAt.wallClock,
+		ExpiresAt:                      body.ExpiresAt.instant,
+		ExpiresAtWallClock:             body.ExpiresAt.wallClock,
+		Weight:                         body.Weight,
+		Priority:                       body.Priority,
+		Audience:                       body.Audience.toDomain(),
+		TimeZone:                       body.TimeZone,
+		Recurrence:                     body.Recurrence.toDomain(),
+		Content:                        body.Content.toDomain(),
+		Overlap:                        body.Overlap,
+	}
+	if err := req.Validate(); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	resp, err := h.svc.Create(r.Context(), req)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	w.Header().Set("ETag", etag(resp.Version))
+	writeJSON(w, http.StatusCreated, createResp{ID: resp.ID, Conflicts: newConflictsJSON(resp.Conflicts)})
+}
+
+type updateReq struct {
+	Name                  *string       `json:"name"`
+	ScheduledDisplayingAt *scheduleTime `json:"scheduled_displaying_at"`
+	ExpiresAt             *scheduleTime `json:"expires_at"`
+	Weight                *int          `json:"weight"`
+	Priority              *int          `json:"priority"`
+	Audience              *audienceJSON `json:"audience"`
+	TimeZone              *string       `json:"time_zone"`
+
+	// Recurrence replaces the banner recurrence, the empty one removes it
+	Recurrence *recurrenceRule `json:"recurrence"`
+
+	// Content replaces the banner content, the one without variants removes it
+	Content *contentJSON `json:"content"`
+
+	Overlap banner.OverlapMode `json:"overlap"`
+}
+
+type updateResp struct {
+	Conflicts []conflictJSON `json:"conflicts"`
+}
+
+// set sets either the instant or the wall clock time, whichever was given
+func (st *scheduleTime) set(instant **time.Time, wallClock **domain.WallClock) {
+	if st == nil {
+		return
+	}
+	if st.wallClock != nil {
+		*wallClock = st.wallClock
+		return
+	}
+	*instant = &st.instant
+}
+
+// update makes the change conditional on the banner version
+// given in If-Match header, as returned in ETag header by get
+func (h *Handler) update(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+
+	version, ok := parseIfMatch(r.Header.Get("If-Match"))
+	if !ok {
+		writeError(w, http.StatusPreconditionFailed, errors.New("invalid If-Match header"))
+		return
+	}
+
+	var body updateReq
+	if !decode(w, r, &body) {
+		return
+	}
+
+	req := &banner.UpdateReq{
+		ID:         id,
+		Name:       body.Name,
+		Weight:     body.Weight,
+		Priority:   body.Priority,
+		Audience:   body.Audience.toDomain(),
+		TimeZone:   body.TimeZone,
+		Recurrence: body.Recurrence.toDomain(),
+		Content:    body.Content.toDomain(),
+		Overlap:    body.Overlap,
+		Version:    version,
+	}
+	body.ScheduledDisplayingAt.set(&req.ScheduledDisplayingAt, &req.ScheduledDisplayingAtWallClock)
+	body.ExpiresAt.set(&req.ExpiresAt, &req.ExpiresAtWallClock)
+	if err := req.Validate(); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	resp, err := h.svc.Update(r.Context(), req)
+	var verr *domain.VersionConflictError
+	if errors.As(err, &verr) && version != 0 {
+		writeError(w, http.StatusPreconditionFailed, err)
+		return
+	}
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	writeUpdateResp(w, resp)
+}
+
+// writeUpdateResp writes the response of the banner update
+// along with the new banner version in ETag header
+func writeUpdateResp(w http.ResponseWriter, resp *banner.UpdateResp) {
+	w.Header().Set("ETag", etag(resp.Version))
+
+	// overlaps are only reported in warn mode
+	if len(resp.Conflicts) > 0 {
+		writeJSON(w, http.StatusOK, updateResp{Conflicts: newConflictsJSON(resp.Conflicts)})
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) display(w http.ResponseWriter, r *http.Request) {
+	resp, err := h.svc.Display(r.Context(), &banner.DisplayReq{Viewer: h.viewer(r)})
+	if errors.Is(err, domain.ErrNoActiveBanner) {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	body := displayJSON{bannerJSON: newBannerJSON(resp.Banner)}
+	if resp.Content != nil {
+		w.Header().Set("Content-Language", resp.Locale)
+		body.Locale = resp.Locale
+		v := contentVariantJSON(*resp.Content)
+		body.LocalizedContent = &v
+	}
+
+	writeJSON(w, http.StatusOK, body)
+}
+
+// displayJSON represents JSON encoding of the displayed banner, along
+// with its content in the locale matching the viewer's Accept-Language
+type displayJSON struct {
+	bannerJSON
+	Locale           string              `json:"locale,omitempty"`
+	LocalizedContent *contentVariantJSON `json:"localized_content,omitempty"`
+}
+
+func (h *Handler) get(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+
+	resp, err := h.svc.Get(r.Context(), &banner.GetReq{ID: id})
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	w.Header().Set("ETag", etag(resp.Banner.Version))
+	writeJSON(w, http.StatusOK, newBannerJSON(resp.Banner))
+}
+
+type auditResp struct {
+	Entries []auditEntryJSON `json:"entries"`
+}
+
+// auditEntryJSON represents JSON encoding of the audit log entry
+type auditEntryJSON struct {
+	ID       int64              `json:"id"`
+	BannerID domain.BannerID    `json:"banner_id"`
+	Action   domain.AuditAction `json:"action"`
+	Actor    string             `json:"actor,omitempty"`
+	At       time.Time          `json:"at"`
+	Before   *bannerJSON        `json:"before,omitempty"`
+	After    *bannerJSON        `json:"after,omitempty"`
+}
+
+func newAuditEntryJSON(e domain.AuditEntry) auditEntryJSON {
+	out := auditEntryJSON{
+		ID:       e.ID,
+		BannerID: e.BannerID,
+		Action:   e.Action,
+		Actor:    e.Actor,
+		At:       e.At,
+	}
+	if e.Before != nil {
+		b := newBannerJSON(*e.Before)
+		out.Before = &b
+	}
+	if e.After != nil {
+		b := newBannerJSON(*e.After)
+		out.After = &b
+	}
+
+	return out
+}
+
+// audit lists the changes made to the banner, oldest first
+func (h *Handler) audit(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+
+	resp, err := h.svc.Audit(r.Context(), &banner.AuditReq{BannerID: id})
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	out := auditResp{Entries: make([]auditEntryJSON, 0, len(resp.Entries))}
+	for _, e := range resp.Entries {
+		out.Entries = append(out.Entries, newAuditEntryJSON(e))
+	}
+
+	writeJSON(w, http.StatusOK, out)
+}
+
+type revisionsResp struct {
+	Revisions []revisionJSON `json:"revisions"`
+}
+
+// revisionJSON represents JSON encoding of the past banner revision
+type revisionJSON struct {
+	Version int        `json:"version"`
+	At      time.Time  `json:"at"`
+	Actor   string     `json:"actor,omitempty"`
+	Banner  bannerJSON `json:"banner"`
+}
+
+// revisions lists the past revisions of the banner, oldest first
+func (h *Handler) revisions(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+
+	resp, err := h.svc.History(r.Context(), &banner.HistoryReq{ID: id})
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	out := revisionsResp{Revisions: make([]revisionJSON, 0, len(resp.Revisions))}
+	for _, rev := range resp.Revisions {
+		out.Revisions = append(out.Revisions, revisionJSON{
+			Version: rev.Version,
+			At:      rev.At,
+			Actor:   rev.Actor,
+			Banner:  newBannerJSON(rev.Banner),
+		})
+	}
+
+	writeJSON(w, http.StatusOK, out)
+}
+
+type diffResp struct {
+	Changes []fieldChangeJSON `json:"changes"`
+}
+
+// fieldChangeJSON represents JSON encoding of the change of the banner field
+type fieldChangeJSON struct {
+	Field string `json:"field"`
+	From  string `json:"from"`
+	To    string `json:"to"`
+}
+
+// diff compares the banner revisions given
+// in from and to query parameters
+func (h *Handler) diff(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+
+	q := r.URL.Query()
+	req := &banner.DiffReq{ID: id}
+	var err error
+	if req.From, err = parseIntParam(q.Get("from"), "from"); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	if req.To, err = parseIntParam(q.Get("to"), "to"); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	resp, err := h.svc.Diff(r.Context(), req)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	out := diffResp{Changes: make([]fieldChangeJSON, 0, len(resp.Changes))}
+	for _, c := range resp.Changes {
+		out.Changes = append(out.Changes, fieldChangeJSON(c))
+	}
+
+	writeJSON(w, http.StatusOK, out)
+}
+
+type revertReq struct {
+	Revision int                `json:"revision"`
+	Overlap  banner.OverlapMode `json:"overlap"`
+}
+
+// revert restores the banner revision, conditionally on
+// the current banner version given in If-Match header
+func (h *Handler) revert(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+
+	version, ok := parseIfMatch(r.Header.Get("If-Match"))
+	if !ok {
+		writeError(w, http.StatusPreconditionFailed, errors.New("invalid If-Match header"))
+		return
+	}
+
+	var body revertReq
+	if !decode(w, r, &body) {
+		return
+	}
+
+	resp, err := h.svc.Revert(r.Context(), &banner.RevertReq{
+		ID:       id,
+		Revision: body.Revision,
+		Overlap:  body.Overlap,
+		Version:  version,
+	})
+	var verr *domain.VersionConflictError
+	if errors.As(err, &verr) && version != 0 {
+		writeError(w, http.StatusPreconditionFailed, err)
+		return
+	}
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	writeUpdateResp(w, resp)
+}
+
+type listResp struct {
+	Banners []bannerJSON `json:"banners"`
+	Total   int          `json:"total"`
+}
+
+// list supports status, from, to, sort, order,
+// offset and limit query parameters
+func (h *Handler) list(w http.ResponseWriter, r *http.Request) {
+	req, err := parseListReq(r)
+	if err == nil {
+		err = req.Validate()
+	}
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	resp, err := h.svc.List(r.Context(), req)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	out := listResp{
+		Banners: make([]bannerJSON, 0, len(resp.Banners)),
+		Total:   resp.Total,
+	}
+	for _, b := range resp.Banners {
+		out.Banners = append(out.Banners, newBannerJSON(b))
+	}
+
+	writeJSON(w, http.StatusOK, out)
+}
+
+func parseListReq(r *http.Request) (*banner.ListReq, error) {
+	q := r.URL.Query()
+	req := &banner.ListReq{
+		Status: domain.BannerStatus(q.Get("status")),
+		SortBy: banner.ListSort(q.Get("sort")),
+	}
+
+	switch q.Get("order") {
+	case "", "asc":
+	case "desc":
+		req.Desc = true
+	default:
+		return nil, domain.NewValidationError("order", "must be asc or desc")
+	}
+
+	var err error
+	if req.From, err = parseTimeParam(q.Get("from"), "from"); err != nil {
+		return nil, err
+	}
+	if req.To, err = parseTimeParam(q.Get("to"), "to"); err != nil {
+		return nil, err
+	}
+	if req.Offset, err = parseIntParam(q.Get("offset"), "offset"); err != nil {
+		return nil, err
+	}
+	if req.Limit, err = parseIntParam(q.Get("limit"), "limit"); err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+func parseTimeParam(v, name string) (time.Time, error) {
+	if v == "" {
+		return time.Time{}, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		return time.Time{}, domain.NewValidationError(name, "must be RFC 3339 time")
+	}
+
+	return t, nil
+}
+
+func parseIntParam(v, name string) (int, error) {
+	if v == "" {
+		return 0, nil
+	}
+
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, domain.NewValidationError(name, "must be a number")
+	}
+
+	return n, nil
+}
+
+func (h *Handler) delete(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+
+	err := h.svc.Delete(r.Context(), &banner.DeleteReq{ID: id})
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// pathID parses banner ID from the request path, writing
+// bad request response if the ID is not valid
+func pathID(w http.ResponseWriter, r *http.Request) (domain.BannerID, bool) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil || id <= 0 {
+		writeError(w, http.StatusBadRequest, errors.New("invalid banner id"))
+		return 0, false
+	}
+
+	return domain.BannerID(id), true
+}
+
+// etag formats the banner version as the entity tag
+func etag(version int) string {
+	return `"` + strconv.Itoa(version) + `"`
+}
+
+// parseIfMatch parses the banner version from If-Match header, which
+// is zero if the header is not set or is "*". Only a single strong
+// entity tag is accepted, as set by the handlers
+func parseIfMatch(h string) (int, bool) {
+	if h == "" || h == "*" {
+		return 0, true
+	}
+	if len(h) < 3 || h[0] != '"' || h[len(h)-1] != '"' {
+		return 0, false
+	}
+
+	v, err := strconv.Atoi(h[1 : len(h)-1])
+	if err != nil || v <= 0 {
+		return 0, false
+	}
+
+	return v, true
+}
+
+// decode decodes JSON request body into v, writing
+// bad request response if the body is not valid
+func decode(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+
+	if err := dec.Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, errors.New("invalid request body"))
+		return false
+	}
+
+	return true
+}
+
+type errorResp struct {
+	Error     string           `json:"error"`
+	Fields    []fieldErrorJSON `json:"fields,omitempty"`
+	Conflicts []conflictJSON   `json:"conflicts,omitempty"`
+}
+
+// fieldErrorJSON represents JSON encoding of the invalid field
+type fieldErrorJSON struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// writeServiceError maps the service error to the response status code
+func writeServiceError(w http.ResponseWriter, err error) {
+	var (
+		verr     *domain.ValidationError
+		conflict *banner.ConflictError
+	)
+	switch {
+	case errors.As(err, &verr):
+		resp := errorResp{Error: err.Error()}
+		for _, f := range verr.Fields {
+			resp.Fields = append(resp.Fields, fieldErrorJSON(f))
+		}
+		writeJSON(w, http.StatusBadRequest, resp)
+	case errors.As(err, &conflict):
+		writeJSON(w, http.StatusConflict, errorResp{
+			Error:     err.Error(),
+			Conflicts: newConflictsJSON(conflict.Conflicts),
+		})
+	case errors.Is(err, domain.ErrConflict):
+		writeError(w, http.StatusConflict, err)
+	case errors.Is(err, domain.ErrNotFound):
+		writeError(w, http.StatusNotFound, err)
+	case errors.Is(err, domain.ErrNoActiveBanner):
+		writeError(w, http.StatusNotFound, err)
+	case errors.Is(err, banner.ErrAuditDisabled):
+		writeError(w, http.StatusNotFound, err)
+	default:
+		// internal errors are logged rather than
+		// leaked to the client
+		log.Printf("banner api: %v", err)
+		writeError(w, http.StatusInternalServerError, errors.New("internal server error"))
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, errorResp{Error: err.Error()})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Printf("banner api: encoding response: %v", err)
+	}
+}
@@ -0,0 +1,727 @@
+package http_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strings"
+	"testing"
+	"time"
+
+	domain "github.com/DzananGanic/banner"
+	"github.com/DzananGanic/banner/banner"
+	bannerhttp "github.com/DzananGanic/banner/http"
+	"github.com/DzananGanic/banner/mock"
+	"github.com/DzananGanic/banner/platform/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreate(t *testing.T) {
+	cases := []struct {
+		name       string
+		body       string
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:       "test successfully create",
+			body:       `{"name":"banner","scheduled_displaying_at":"2019-01-01T00:00:00Z","expires_at":"2020-01-01T00:00:00Z"}`,
+			wantStatus: http.StatusCreated,
+			wantBody:   `{"id":1}`,
+		},
+		{
+			name:       "test validation error",
+			body:       `{"scheduled_displaying_at":"2019-01-01T00:00:00Z","expires_at":"2020-01-01T00:00:00Z"}`,
+			wantStatus: http.StatusBadRequest,
+			wantBody:   `{"error":"invalid name: must not be empty","fields":[{"field":"name","message":"must not be empty"}]}`,
+		},
+		{
+			name:       "test negative weight",
+			body:       `{"name":"banner","scheduled_displaying_at":"2019-01-01T00:00:00Z","expires_at":"2020-01-01T00:00:00Z","weight":-1}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "test invalid time",
+			body:       `{"name":"banner","scheduled_displaying_at":"yesterday","expires_at":"2020-01-01T00:00:00Z"}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "test invalid json",
+			body:       `{"name":`,
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			h, _ := newHandler(nil)
+
+			rec := serve(h, http.MethodPost, "/banners", c.body)
+			assert.Equal(t, c.wantStatus, rec.Code)
+			if c.wantBody != "" {
+				assert.JSONEq(t, c.wantBody, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	cases := []struct {
+		name       string
+		path       string
+		body       string
+		ifMatch    string
+		wantStatus int
+		wantName   string
+		wantETag   string
+	}{
+		{
+			name:       "test successfully update",
+			path:       "/banners/1",
+			body:       `{"name":"updated"}`,
+			wantStatus: http.StatusNoContent,
+			wantName:   "updated",
+			wantETag:   `"2"`,
+		},
+		{
+			name:       "test successfully update current version",
+			path:       "/banners/1",
+			body:       `{"name":"updated"}`,
+			ifMatch:    `"1"`,
+			wantStatus: http.StatusNoContent,
+			wantName:   "updated",
+			wantETag:   `"2"`,
+		},
+		{
+			name:       "test stale version",
+			path:       "/banners/1",
+			body:       `{"name":"updated"}`,
+			ifMatch:    `"7"`,
+			wantStatus: http.StatusPreconditionFailed,
+			wantName:   "banner",
+		},
+		{
+			name:       "test invalid if match",
+			path:       "/banners/1",
+			body:       `{"name":"updated"}`,
+			ifMatch:    `W/"1"`,
+			wantStatus: http.StatusPreconditionFailed,
+			wantName:   "banner",
+		},
+		{
+			name:       "test not found",
+			path:       "/banners/5",
+			body:       `{"name":"updated"}`,
+			wantStatus: http.StatusNotFound,
+			wantName:   "banner",
+		},
+		{
+			name:       "test invalid id",
+			path:       "/banners/abc",
+			body:       `{"name":"updated"}`,
+			wantStatus: http.StatusBadRequest,
+			wantName:   "banner",
+		},
+		{
+			name:       "test unknown field",
+			path:       "/banners/1",
+			body:       `{"title":"updated"}`,
+			wantStatus: http.StatusBadRequest,
+			wantName:   "banner",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			h, db := newHandler(nil)
+			_, err := db.Save(domain.Banner{
+				Name:                  "banner",
+				ScheduledDisplayingAt: time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC),
+				ExpiresAt:             time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+			})
+			assert.Nil(t, err)
+
+			req := httptest.NewRequest(http.MethodPatch, c.path, strings.NewReader(c.body))
+			if c.ifMatch != "" {
+				req.Header.Set("If-Match", c.ifMatch)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+			assert.Equal(t, c.wantStatus, rec.Code)
+			assert.Equal(t, c.wantETag, rec.Header().Get("ETag"))
+
+			b, err := db.FetchForID(1)
+			assert.Nil(t, err)
+			assert.Equal(t, c.wantName, b.Name)
+		})
+	}
+}
+
+func TestDisplay(t *testing.T) {
+	cases := []struct {
+		name       string
+		display    func(domain.Viewer) (*domain.Banner, error)
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name: "test display banner",
+			display: func(v domain.Viewer) (*domain.Banner, error) {
+				return &domain.Banner{
+					ID:                    1,
+					Name:                  "banner",
+					CreatedAt:             time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC),
+					ScheduledDisplayingAt: time.Date(2019, 1, 2, 0, 0, 0, 0, time.UTC),
+					ExpiresAt:             time.Date(2019, 1, 3, 0, 0, 0, 0, time.FixedZone("CET", 3600)),
+				}, nil
+			},
+			wantStatus: http.StatusOK,
+			wantBody: `{
+				"id": 1,
+				"name": "banner",
+				"created_at": "2019-01-01T00:00:00Z",
+				"scheduled_displaying_at": "2019-01-02T00:00:00Z",
+				"expires_at": "2019-01-03T00:00:00+01:00",
+				"weight": 0,
+				"priority": 0,
+				"version": 0
+			}`,
+		},
+		{
+			name: "test display localized content",
+			display: func(v domain.Viewer) (*domain.Banner, error) {
+				return &domain.Banner{
+					ID:                    1,
+					Name:                  "banner",
+					CreatedAt:             time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC),
+					ScheduledDisplayingAt: time.Date(2019, 1, 2, 0, 0, 0, 0, time.UTC),
+					ExpiresAt:             time.Date(2019, 1, 3, 0, 0, 0, 0, time.UTC),
+					Content: &domain.Content{
+						FallbackLocale: "bs",
+						Variants: map[string]domain.ContentVariant{
+							"bs": {Title: "Popust", LinkURL: "https://example.com"},
+						},
+					},
+				}, nil
+			},
+			wantStatus: http.StatusOK,
+			wantBody: `{
+				"id": 1,
+				"name": "banner",
+				"created_at": "2019-01-01T00:00:00Z",
+				"scheduled_displaying_at": "2019-01-02T00:00:00Z",
+				"expires_at": "2019-01-03T00:00:00Z",
+				"weight": 0,
+				"priority": 0,
+				"version": 0,
+				"content": {
+					"fallback_locale": "bs",
+					"variants": {"bs": {"title": "Popust", "link_url": "https://example.com"}}
+				},
+				"locale": "bs",
+				"localized_content": {"title": "Popust", "link_url": "https://example.com"}
+			}`,
+		},
+		{
+			name: "test no active banner",
+			display: func(v domain.Viewer) (*domain.Banner, error) {
+				return nil, domain.ErrNoActiveBanner
+			},
+			wantStatus: http.StatusNoContent,
+		},
+		{
+			name: "test internal error",
+			display: func(v domain.Viewer) (*domain.Banner, error) {
+				return nil, fmt.Errorf("database error")
+			},
+			wantStatus: http.StatusInternalServerError,
+			wantBody:   `{"error":"internal server error"}`,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			h, _ := newHandler(c.display)
+
+			rec := serve(h, http.MethodGet, "/banners/display", "")
+			assert.Equal(t, c.wantStatus, rec.Code)
+			if c.wantBody != "" {
+				assert.JSONEq(t, c.wantBody, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestCreateThenDisplayRoundTrip(t *testing.T) {
+	h, db := newHandler(nil)
+
+	rec := serve(h, http.MethodPost, "/banners", `{"name":"banner","scheduled_displaying_at":"2019-01-01T00:00:00Z","expires_at":"2020-01-01T00:00:00Z"}`)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+
+	var resp struct {
+		ID domain.BannerID `json:"id"`
+	}
+	assert.Nil(t, json.NewDecoder(rec.Body).Decode(&resp))
+
+	b, err := db.FetchForID(resp.ID)
+	assert.Nil(t, err)
+	assert.True(t, b.ExpiresAt.Equal(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)))
+}
+
+func TestCreateThenDisplayMixedCaseAttribute(t *testing.T) {
+	var db *memory.BannerDB
+	display := func(v domain.Viewer) (*domain.Banner, error) {
+		banners, err := db.List()
+		if err != nil {
+			return nil, err
+		}
+		for _, b := range banners {
+			if b.Audience.Matches(v) {
+				return &b, nil
+			}
+		}
+		return nil, domain.ErrNoActiveBanner
+	}
+	h, db := newHandlerWithProxies(display, []netip.Prefix{netip.MustParsePrefix("10.0.0.1/32")})
+
+	rec := serve(h, http.MethodPost, "/banners", `{"name":"pro","scheduled_displaying_at":"2019-01-01T00:00:00Z","expires_at":"2020-01-01T00:00:00Z","audience":{"attributes":{"Plan":"pro"}}}`)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+
+	req := httptest.NewRequest(http.MethodGet, "/banners/display", nil)
+	req.RemoteAddr = "10.0.0.1:1234"
+	req.Header.Set("X-Viewer-Attribute-Plan", "pro")
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestCreateWallClock(t *testing.T) {
+	h, db := newHandler(nil)
+
+	rec := serve(h, http.MethodPost, "/banners", `{"name":"banner","time_zone":"Europe/Sarajevo","scheduled_displaying_at":"2019-06-03T09:00","expires_at":"2019-12-02T09:00:00"}`)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+
+	b, err := db.FetchForID(1)
+	assert.Nil(t, err)
+	assert.Equal(t, time.Date(2019, 6, 3, 7, 0, 0, 0, time.UTC), b.ScheduledDisplayingAt)
+	assert.Equal(t, time.Date(2019, 12, 2, 8, 0, 0, 0, time.UTC), b.ExpiresAt)
+
+	rec = serve(h, http.MethodPatch, "/banners/1", `{"time_zone":"Mars/Olympus_Mons"}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestCreateRecurrence(t *testing.T) {
+	h, db := newHandler(nil)
+
+	rec := serve(h, http.MethodPost, "/banners", `{"name":"lunch special","scheduled_displaying_at":"2019-06-01T00:00:00Z","expires_at":"2019-07-01T00:00:00Z","recurrence":"FREQ=WEEKLY;BYDAY=MO,TU,WE,TH,FR;BYHOUR=11;DURATION=PT3H"}`)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+
+	b, err := db.FetchForID(1)
+	assert.Nil(t, err)
+	assert.Equal(t, "FREQ=WEEKLY;BYDAY=MO,TU,WE,TH,FR;BYHOUR=11;BYMINUTE=0;DURATION=PT3H", b.Recurrence.String())
+
+	rec = serve(h, http.MethodPatch, "/banners/1", `{"recurrence":"FREQ=HOURLY;DURATION=PT1H"}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = serve(h, http.MethodPatch, "/banners/1", `{"recurrence":""}`)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+
+	b, err = db.FetchForID(1)
+	assert.Nil(t, err)
+	assert.Nil(t, b.Recurrence)
+}
+
+func TestCreateOverlap(t *testing.T) {
+	h, db := newHandler(nil)
+	_, err := db.Save(domain.Banner{
+		Name:                  "existing",
+		ScheduledDisplayingAt: time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC),
+		ExpiresAt:             time.Date(2019, 2, 1, 0, 0, 0, 0, time.UTC),
+	})
+	assert.Nil(t, err)
+
+	body := `{"name":"banner","scheduled_displaying_at":"2019-01-15T00:00:00Z","expires_at":"2019-03-01T00:00:00Z","overlap":"%s"}`
+	conflicts := `[{"banner_id":1,"name":"existing","from":"2019-01-15T00:00:00Z","to":"2019-02-01T00:00:00Z"}]`
+
+	rec := serve(h, http.MethodPost, "/banners", fmt.Sprintf(body, "reject"))
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	assert.JSONEq(t, `{"error":"banner overlaps with banners 1","conflicts":`+conflicts+`}`, rec.Body.String())
+
+	rec = serve(h, http.MethodPost, "/banners", fmt.Sprintf(body, "warn"))
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.JSONEq(t, `{"id":2,"conflicts":`+conflicts+`}`, rec.Body.String())
+
+	rec = serve(h, http.MethodPost, "/banners", fmt.Sprintf(body, "ignore"))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = serve(h, http.MethodPatch, "/banners/2", `{"expires_at":"2018-01-01T00:00:00Z"}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func newHandler(display func(domain.Viewer) (*domain.Banner, error)) (*bannerhttp.Handler, *memory.BannerDB) {
+	return newHandlerWithProxies(display, nil)
+}
+
+func newHandlerWithProxies(display func(domain.Viewer) (*domain.Banner, error), proxies []netip.Prefix) (*bannerhttp.Handler, *memory.BannerDB) {
+	db := memory.NewBannerDB()
+	disp := &mock.BannerDisplayer{DisplayBannerFn: display}
+
+	svc := banner.New(
+		db,
+		memory.NewActiveBannerProvider(),
+		disp,
+		mock.NewClock(time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)),
+		banner.WithAuditStore(memory.NewAuditStore()),
+	)
+
+	return bannerhttp.NewHandler(svc, proxies), db
+}
+
+func serve(h http.Handler, method, path, body string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(method, path, strings.NewReader(body)))
+	return rec
+}
+
+func TestAudit(t *testing.T) {
+	h, _ := newHandlerWithProxies(nil, []netip.Prefix{netip.MustParsePrefix("10.0.0.1/32")})
+	write := func(method, path, body, remoteAddr string) {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.RemoteAddr = remoteAddr
+		req.Header.Set("X-User-ID", "alice")
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		assert.Less(t, rec.Code, 300)
+	}
+
+	write(http.MethodPost, "/banners", `{"name":"banner","scheduled_displaying_at":"2019-01-01T00:00:00Z","expires_at":"2020-01-01T00:00:00Z"}`, "10.0.0.1:1234")
+	// the user ID is not trusted unless set by the trusted proxy
+	write(http.MethodPatch, "/banners/1", `{"name":"renamed"}`, "192.0.2.1:1234")
+
+	rec := serve(h, http.MethodGet, "/banners/1/audit", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"entries": [
+		{
+			"id": 1,
+			"banner_id": 1,
+			"action": "create",
+			"actor": "alice",
+			"at": "2019-01-01T00:00:00Z",
+			"after": {
+				"id": 1,
+				"name": "banner",
+				"created_at": "2019-01-01T00:00:00Z",
+				"scheduled_displaying_at": "2019-01-01T00:00:00Z",
+				"expires_at": "2020-01-01T00:00:00Z",
+				"weight": 0,
+				"priority": 0,
+				"version": 1
+			}
+		},
+		{
+			"id": 2,
+			"banner_id": 1,
+			"action": "update",
+			"at": "2019-01-01T00:00:00Z",
+			"before": {
+				"id": 1,
+				"name": "banner",
+				"created_at": "2019-01-01T00:00:00Z",
+				"scheduled_displaying_at": "2019-01-01T00:00:00Z",
+				"expires_at": "2020-01-01T00:00:00Z",
+				"weight": 0,
+				"priority": 0,
+				"version": 1
+			},
+			"after": {
+				"id": 1,
+				"name": "renamed",
+				"created_at": "2019-01-01T00:00:00Z",
+				"scheduled_displaying_at": "2019-01-01T00:00:00Z",
+				"expires_at": "2020-01-01T00:00:00Z",
+				"weight": 0,
+				"priority": 0,
+				"version": 2
+			}
+		}
+	]}`, rec.Body.String())
+}
+
+func TestRevisions(t *testing.T) {
+	h, _ := newHandler(nil)
+	assert.Equal(t, http.StatusCreated, serve(h, http.MethodPost, "/banners", `{"name":"banner","scheduled_displaying_at":"2019-01-01T00:00:00Z","expires_at":"2020-01-01T00:00:00Z"}`).Code)
+	assert.Equal(t, http.StatusNoContent, serve(h, http.MethodPatch, "/banners/1", `{"name":"renamed","weight":2}`).Code)
+
+	rec := serve(h, http.MethodGet, "/banners/1/revisions", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var revs struct {
+		Revisions []struct {
+			Version int `json:"version"`
+			Banner  struct {
+				Name string `json:"name"`
+			} `json:"banner"`
+		} `json:"revisions"`
+	}
+	assert.Nil(t, json.Unmarshal(rec.Body.Bytes(), &revs))
+	assert.Len(t, revs.Revisions, 2)
+	assert.Equal(t, 2, revs.Revisions[1].Version)
+	assert.Equal(t, "renamed", revs.Revisions[1].Banner.Name)
+
+	rec = serve(h, http.MethodGet, "/banners/1/diff?from=1&to=2", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"changes": [
+		{"field": "name", "from": "banner", "to": "renamed"},
+		{"field": "weight", "from": "0", "to": "2"}
+	]}`, rec.Body.String())
+
+	rec = serve(h, http.MethodGet, "/banners/1/diff?from=1", "")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = serve(h, http.MethodGet, "/banners/1/diff?from=1&to=3", "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestRevert(t *testing.T) {
+	cases := []struct {
+		name       string
+		body       string
+		ifMatch    string
+		wantStatus int
+		wantName   string
+		wantETag   string
+	}{
+		{
+			name:       "test successfully revert",
+			body:       `{"revision":1}`,
+			wantStatus: http.StatusNoContent,
+			wantName:   "banner",
+			wantETag:   `"3"`,
+		},
+		{
+			name:       "test successfully revert matching version",
+			body:       `{"revision":1}`,
+			ifMatch:    `"2"`,
+			wantStatus: http.StatusNoContent,
+			wantName:   "banner",
+			wantETag:   `"3"`,
+		},
+		{
+			name:       "test stale version",
+			body:       `{"revision":1}`,
+			ifMatch:    `"1"`,
+			wantStatus: http.StatusPreconditionFailed,
+			wantName:   "renamed",
+		},
+		{
+			name:       "test unknown revision",
+			body:       `{"revision":5}`,
+			wantStatus: http.StatusNotFound,
+			wantName:   "renamed",
+		},
+		{
+			name:       "test missing revision",
+			body:       `{}`,
+			wantStatus: http.StatusBadRequest,
+			wantName:   "renamed",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			h, db := newHandler(nil)
+			serve(h, http.MethodPost, "/banners", `{"name":"banner","scheduled_displaying_at":"2019-01-01T00:00:00Z","expires_at":"2020-01-01T00:00:00Z"}`)
+			serve(h, http.MethodPatch, "/banners/1", `{"name":"renamed"}`)
+
+			req := httptest.NewRequest(http.MethodPost, "/banners/1/revert", strings.NewReader(c.body))
+			if c.ifMatch != "" {
+				req.Header.Set("If-Match", c.ifMatch)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			assert.Equal(t, c.wantStatus, rec.Code)
+			assert.Equal(t, c.wantETag, rec.Header().Get("ETag"))
+			b, err := db.FetchForID(1)
+			assert.Nil(t, err)
+			assert.Equal(t, c.wantName, b.Name)
+		})
+	}
+}
+
+func TestGet(t *testing.T) {
+	cases := []struct {
+		name       string
+		path       string
+		wantStatus int
+		wantETag   string
+	}{
+		{
+			name:       "test get banner",
+			path:       "/banners/1",
+			wantStatus: http.StatusOK,
+			wantETag:   `"1"`,
+		},
+		{
+			name:       "test not found",
+			path:       "/banners/5",
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "test invalid id",
+			path:       "/banners/-1",
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			h, db := newHandler(nil)
+			_, err := db.Save(domain.Banner{Name: "banner"})
+			assert.Nil(t, err)
+
+			rec := serve(h, http.MethodGet, c.path, "")
+			assert.Equal(t, c.wantStatus, rec.Code)
+			assert.Equal(t, c.wantETag, rec.Header().Get("ETag"))
+		})
+	}
+}
+
+func TestList(t *testing.T) {
+	cases := []struct {
+		name       string
+		query      string
+		wantStatus int
+		wantNames  []string
+		wantTotal  int
+	}{
+		{
+			name:       "test list all",
+			query:      "",
+			wantStatus: http.StatusOK,
+			wantNames:  []string{"expired", "active", "scheduled"},
+			wantTotal:  3,
+		},
+		{
+			name:       "test filter by status",
+			query:      "?status=active",
+			wantStatus: http.StatusOK,
+			wantNames:  []string{"active"},
+			wantTotal:  1,
+		},
+		{
+			name:       "test sort and paginate",
+			query:      "?sort=name&order=desc&limit=2",
+			wantStatus: http.StatusOK,
+			wantNames:  []string{"scheduled", "expired"},
+			wantTotal:  3,
+		},
+		{
+			name:       "test filter by time range",
+			query:      "?from=2005-01-01T00:00:00Z&to=2050-01-01T00:00:00Z",
+			wantStatus: http.StatusOK,
+			wantNames:  []string{"active"},
+			wantTotal:  1,
+		},
+		{
+			name:       "test invalid status",
+			query:      "?status=archived",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "test invalid limit",
+			query:      "?limit=ten",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "test invalid order",
+			query:      "?order=random",
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			h, db := newHandler(nil)
+			for _, b := range []domain.Banner{
+				{
+					Name:                  "expired",
+					ScheduledDisplayingAt: time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC),
+					ExpiresAt:             time.Date(2001, 1, 1, 0, 0, 0, 0, time.UTC),
+				},
+				{
+					Name:                  "active",
+					ScheduledDisplayingAt: time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC),
+					ExpiresAt:             time.Date(2100, 1, 1, 0, 0, 0, 0, time.UTC),
+				},
+				{
+					Name:                  "scheduled",
+					ScheduledDisplayingAt: time.Date(2099, 1, 1, 0, 0, 0, 0, time.UTC),
+					ExpiresAt:             time.Date(2101, 1, 1, 0, 0, 0, 0, time.UTC),
+				},
+			} {
+				_, err := db.Save(b)
+				assert.Nil(t, err)
+			}
+
+			rec := serve(h, http.MethodGet, "/banners"+c.query, "")
+			assert.Equal(t, c.wantStatus, rec.Code)
+			if c.wantStatus != http.StatusOK {
+				return
+			}
+
+			var resp struct {
+				Banners []struct {
+					Name string `json:"name"`
+				} `json:"banners"`
+				Total int `json:"total"`
+			}
+			assert.Nil(t, json.NewDecoder(rec.Body).Decode(&resp))
+
+			names := []string{}
+			for _, b := range resp.Banners {
+				names = append(names, b.Name)
+			}
+			assert.Equal(t, c.wantNames, names)
+			assert.Equal(t, c.wantTotal, resp.Total)
+		})
+	}
+}
+
+func TestDelete(t *testing.T) {
+	cases := []struct {
+		name       string
+		path       string
+		wantStatus int
+		wantLeft   int
+	}{
+		{
+			name:       "test delete banner",
+			path:       "/banners/1",
+			wantStatus: http.StatusNoContent,
+			wantLeft:   0,
+		},
+		{
+			name:       "test not found",
+			path:       "/banners/5",
+			wantStatus: http.StatusNotFound,
+			wantLeft:   1,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			h, db := newHandler(nil)
+			_, err := db.Save(domain.Banner{Name: "banner"})
+			assert.Nil(t, err)
+
+			rec := serve(h, http.MethodDelete, c.path, "")
+			assert.Equal(t, c.wantStatus, rec.Code)
+
+			banners, err := db.List()
+			assert.Nil(t, err)
+			assert.Len(t, banners, c.wantLeft)
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package http
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+
+	domain "github.com/DzananGanic/banner"
+)
+
+// viewerHeaders lists the request headers which are
+// passed on to the display decision
+var viewerHeaders = []string{"Accept-Language", "User-Agent"}
+
+// userIDHeader holds the ID of the authenticated user, as set by
+// the trusted proxy in front of the service. It is ignored on
+// requests which do not come through a trusted proxy
+const userIDHeader = "X-User-ID"
+
+// countryHeader holds the country code of the viewer, as
+// resolved by the trusted proxy, e.g. from its GeoIP database
+const countryHeader = "X-Country-Code"
+
+// attributeHeaderPrefix prefixes the headers holding custom viewer
+// attributes set by the trusted proxy, e.g. X-Viewer-Attribute-Plan
+const attributeHeaderPrefix = "X-Viewer-Attribute-"
+
+// ParseTrustedProxies parses IP addresses and CIDR blocks of the
+// proxies whose X-Forwarded-For header is trusted
+func ParseTrustedProxies(entries []string) ([]netip.Prefix, error) {
+	var prefixes []netip.Prefix
+	for _, e := range entries {
+		e = strings.TrimSpace(e)
+		if e == "" {
+			continue
+		}
+
+		prefix, err := domain.ParseIPPrefix(e)
+		if err != nil {
+			return nil, fmt.Errorf("trusted proxies: %w", err)
+		}
+		prefixes = append(prefixes, prefix)
+	}
+
+	return prefixes, nil
+}
+
+// viewer builds the viewer out of the request
+func (h *Handler) viewer(r *http.Request) domain.Viewer {
+	ip, proxied := h.clientIP(r)
+
+	v := domain.Viewer{
+		IP:      ip,
+		Headers: make(map[string]string),
+	}
+	for _, name := range viewerHeaders {
+		if value := r.Header.Get(name); value != "" {
+			v.Headers[name] = value
+		}
+	}
+	if proxied {
+		v.UserID = r.Header.Get(userIDHeader)
+		v.Country = r.Header.Get(countryHeader)
+
+		for name, values := range r.Header {
+			attr, ok := strings.CutPrefix(name, attributeHeaderPrefix)
+			if !ok || attr == "" || len(values) == 0 {
+				continue
+			}
+			if v.Attributes == nil {
+				v.Attributes = make(map[string]string)
+			}
+			v.Attributes[strings.ToLower(attr)] = values[0]
+		}
+	}
+
+	return v
+}
+
+// clientIP returns the IP address of the client and whether the
+// request came through the trusted proxy. X-Forwarded-For is only
+// considered when the direct peer is the trusted proxy, and it is
+// walked from the right, skipping the trusted proxies, as the
+// entries left of the first untrusted one may be forged
+func (h *Handler) clientIP(r *http.Request) (string, bool) {
+	peer, ok := parseIP(r.RemoteAddr)
+	if !ok {
+		return "", false
+	}
+	if !h.trusted(peer) {
+		return peer.String(), false
+	}
+
+	var hops []string
+	for _, header := range r.Header.Values("X-Forwarded-For") {
+		hops = append(hops, strings.Split(header, ",")...)
+	}
+
+	client := peer
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop, ok := parseIP(strings.TrimSpace(hops[i]))
+		if !ok {
+			break
+		}
+
+		client = hop
+		if !h.trusted(hop) {
+			break
+		}
+	}
+
+	return client.String(), true
+}
+
+func (h *Handler) trusted(addr netip.Addr) bool {
+	for _, p := range h.trustedProxies {
+		if p.Contains(addr) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// parseIP parses the address which may come with a port
+func parseIP(s string) (netip.Addr, bool) {
+	if host, _, err := net.SplitHostPort(s); err == nil {
+		s = host
+	}
+
+	addr, err := netip.ParseAddr(s)
+	if err != nil {
+		return netip.Addr{}, false
+	}
+
+	return addr.WithZone("").Unmap(), true
+}
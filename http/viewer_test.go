@@ -0,0 +1,157 @@
+package http_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+
+	domain "github.com/DzananGanic/banner"
+	bannerhttp "github.com/DzananGanic/banner/http"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDisplayViewer(t *testing.T) {
+	proxies, err := bannerhttp.ParseTrustedProxies([]string{"10.0.0.1", "192.168.0.0/16", "fd00::/8"})
+	assert.Nil(t, err)
+
+	cases := []struct {
+		name       string
+		remoteAddr string
+		headers    map[string][]string
+		wantViewer domain.Viewer
+	}{
+		{
+			name:       "test direct client",
+			remoteAddr: "203.0.113.7:5000",
+			wantViewer: domain.Viewer{IP: "203.0.113.7", Headers: map[string]string{}},
+		},
+		{
+			name:       "test forwarded header from untrusted peer is ignored",
+			remoteAddr: "203.0.113.7:5000",
+			headers: map[string][]string{
+				"X-Forwarded-For": {"10.0.0.1"},
+				"X-User-Id":       {"42"},
+			},
+			wantViewer: domain.Viewer{IP: "203.0.113.7", Headers: map[string]string{}},
+		},
+		{
+			name:       "test client behind trusted proxy",
+			remoteAddr: "10.0.0.1:5000",
+			headers: map[string][]string{
+				"X-Forwarded-For": {"198.51.100.2"},
+				"X-User-Id":       {"42"},
+			},
+			wantViewer: domain.Viewer{IP: "198.51.100.2", UserID: "42", Headers: map[string]string{}},
+		},
+		{
+			name:       "test country and attributes from trusted proxy",
+			remoteAddr: "10.0.0.1:5000",
+			headers: map[string][]string{
+				"X-Forwarded-For":           {"198.51.100.2"},
+				"X-Country-Code":            {"BA"},
+				"X-Viewer-Attribute-Plan":   {"premium"},
+				"X-Viewer-Attribute-Cohort": {"beta"},
+			},
+			wantViewer: domain.Viewer{
+				IP:         "198.51.100.2",
+				Headers:    map[string]string{},
+				Country:    "BA",
+				Attributes: map[string]string{"plan": "premium", "cohort": "beta"},
+			},
+		},
+		{
+			name:       "test country and attributes from untrusted peer are ignored",
+			remoteAddr: "203.0.113.7:5000",
+			headers: map[string][]string{
+				"X-Country-Code":          {"BA"},
+				"X-Viewer-Attribute-Plan": {"premium"},
+			},
+			wantViewer: domain.Viewer{IP: "203.0.113.7", Headers: map[string]string{}},
+		},
+		{
+			name:       "test client behind chain of trusted proxies",
+			remoteAddr: "10.0.0.1:5000",
+			headers: map[string][]string{
+				"X-Forwarded-For": {"198.51.100.2, 192.168.1.1", "192.168.2.2"},
+			},
+			wantViewer: domain.Viewer{IP: "198.51.100.2", Headers: map[string]string{}},
+		},
+		{
+			name:       "test forged entries left of untrusted hop are ignored",
+			remoteAddr: "10.0.0.1:5000",
+			headers: map[string][]string{
+				"X-Forwarded-For": {"10.0.0.1, 198.51.100.2"},
+			},
+			wantViewer: domain.Viewer{IP: "198.51.100.2", Headers: map[string]string{}},
+		},
+		{
+			name:       "test trusted IPv6 proxy",
+			remoteAddr: "[fd00::1]:5000",
+			headers: map[string][]string{
+				"X-Forwarded-For": {"2001:db8::5"},
+			},
+			wantViewer: domain.Viewer{IP: "2001:db8::5", Headers: map[string]string{}},
+		},
+		{
+			name:       "test trusted proxy without forwarded header",
+			remoteAddr: "10.0.0.1:5000",
+			wantViewer: domain.Viewer{IP: "10.0.0.1", Headers: map[string]string{}},
+		},
+		{
+			name:       "test viewer headers",
+			remoteAddr: "203.0.113.7:5000",
+			headers: map[string][]string{
+				"Accept-Language": {"bs-BA,bs;q=0.9"},
+				"User-Agent":      {"Mozilla/5.0"},
+				"Cookie":          {"session=secret"},
+			},
+			wantViewer: domain.Viewer{
+				IP: "203.0.113.7",
+				Headers: map[string]string{
+					"Accept-Language": "bs-BA,bs;q=0.9",
+					"User-Agent":      "Mozilla/5.0",
+				},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var got domain.Viewer
+			h, _ := newHandlerWithProxies(func(v domain.Viewer) (*domain.Banner, error) {
+				got = v
+				return &domain.Banner{ID: 1}, nil
+			}, proxies)
+
+			req := httptest.NewRequest(http.MethodGet, "/banners/display", nil)
+			req.RemoteAddr = c.remoteAddr
+			for name, values := range c.headers {
+				for _, v := range values {
+					req.Header.Add(name, v)
+				}
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Equal(t, c.wantViewer, got)
+		})
+	}
+}
+
+func TestParseTrustedProxies(t *testing.T) {
+	_, err := bannerhttp.ParseTrustedProxies([]string{"10.0.0.1", "", "fd00::/8"})
+	assert.Nil(t, err)
+
+	_, err = bannerhttp.ParseTrustedProxies([]string{"10.0.0.0/33"})
+	assert.NotNil(t, err)
+
+	_, err = bannerhttp.ParseTrustedProxies([]string{"proxy.local"})
+	assert.NotNil(t, err)
+
+	// IPv4-mapped block matches the plain IPv4 peer address
+	proxies, err := bannerhttp.ParseTrustedProxies([]string{"::ffff:10.0.0.0/104"})
+	assert.Nil(t, err)
+	assert.True(t, proxies[0].Contains(netip.MustParseAddr("10.0.0.1")))
+}
@@ -0,0 +1,66 @@
+package domain_test
+
+import (
+	"net/netip"
+	"testing"
+
+	domain "github.com/DzananGanic/banner"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseIPPrefix(t *testing.T) {
+	cases := []struct {
+		name    string
+		entry   string
+		want    netip.Prefix
+		wantErr bool
+	}{
+		{
+			name:  "test address",
+			entry: " 10.0.0.1 ",
+			want:  netip.MustParsePrefix("10.0.0.1/32"),
+		},
+		{
+			name:  "test block is masked",
+			entry: "10.0.0.1/8",
+			want:  netip.MustParsePrefix("10.0.0.0/8"),
+		},
+		{
+			name:  "test IPv4-mapped address is unmapped",
+			entry: "::ffff:10.0.0.1",
+			want:  netip.MustParsePrefix("10.0.0.1/32"),
+		},
+		{
+			name:  "test IPv4-mapped block is unmapped",
+			entry: "::ffff:10.0.0.0/104",
+			want:  netip.MustParsePrefix("10.0.0.0/8"),
+		},
+		{
+			name:  "test IPv6 block",
+			entry: "fd00::/8",
+			want:  netip.MustParsePrefix("fd00::/8"),
+		},
+		{
+			name:    "test invalid block",
+			entry:   "10.0.0.0/33",
+			wantErr: true,
+		},
+		{
+			name:    "test invalid address",
+			entry:   "proxy.local",
+			wantErr: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got, err := domain.ParseIPPrefix(c.entry)
+			if c.wantErr {
+				assert.NotNil(t, err)
+				return
+			}
+			assert.Nil(t, err)
+			assert.Equal(t, c.want, got)
+		})
+	}
+}
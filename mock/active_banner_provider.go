@@ -1,16 +1,25 @@
 package mock
 
 import (
+	"context"
+
 	domain "github.com/DzananGanic/banner"
 )
 
-// ActiveBannerProvider provides active banner provider repository mock
+// ActiveBannerProvider provides active banner provider repository mock. Context variants
+// of the methods fall back to the plain mock functions when their own is not set
 type ActiveBannerProvider struct {
-	SetFn      func(domain.Banner) error
-	SetInvoked bool
+	SetFn        func(domain.Banner) error
+	SetContextFn func(context.Context, domain.Banner) error
+	SetInvoked   bool
+
+	GetFn        func() (*domain.Banner, error)
+	GetContextFn func(context.Context) (*domain.Banner, error)
+	GetInvoked   bool
 
-	GetFn      func() (*domain.Banner, error)
-	GetInvoked bool
+	ClearFn        func() error
+	ClearContextFn func(context.Context) error
+	ClearInvoked   bool
 }
 
 // Set represents set mock implementation
@@ -19,8 +28,41 @@ func (a *ActiveBannerProvider) Set(b domain.Banner) error {
 	return a.SetFn(b)
 }
 
+// SetContext represents set with context mock implementation
+func (a *ActiveBannerProvider) SetContext(ctx context.Context, b domain.Banner) error {
+	if a.SetContextFn == nil {
+		return a.Set(b)
+	}
+	a.SetInvoked = true
+	return a.SetContextFn(ctx, b)
+}
+
 // Get represents get mock implementation
 func (a *ActiveBannerProvider) Get() (*domain.Banner, error) {
 	a.GetInvoked = true
 	return a.GetFn()
 }
+
+// GetContext represents get with context mock implementation
+func (a *ActiveBannerProvider) GetContext(ctx context.Context) (*domain.Banner, error) {
+	if a.GetContextFn == nil {
+		return a.Get()
+	}
+	a.GetInvoked = true
+	return a.GetContextFn(ctx)
+}
+
+// Clear represents clear mock implementation
+func (a *ActiveBannerProvider) Clear() error {
+	a.ClearInvoked = true
+	return a.ClearFn()
+}
+
+// ClearContext represents clear with context mock implementation
+func (a *ActiveBannerProvider) ClearContext(ctx context.Context) error {
+	if a.ClearContextFn == nil {
+		return a.Clear()
+	}
+	a.ClearInvoked = true
+	return a.ClearContextFn(ctx)
+}
@@ -0,0 +1,28 @@
+package mock
+
+import (
+	"context"
+
+	domain "github.com/DzananGanic/banner"
+)
+
+// AuditStore provides audit store mock
+type AuditStore struct {
+	RecordFn      func(context.Context, domain.AuditEntry) error
+	RecordInvoked bool
+
+	ListForBannerFn      func(context.Context, domain.BannerID) ([]domain.AuditEntry, error)
+	ListForBannerInvoked bool
+}
+
+// Record represents the mock for Record audit store method
+func (s *AuditStore) Record(ctx context.Context, e domain.AuditEntry) error {
+	s.RecordInvoked = true
+	return s.RecordFn(ctx, e)
+}
+
+// ListForBanner represents the mock for ListForBanner audit store method
+func (s *AuditStore) ListForBanner(ctx context.Context, id domain.BannerID) ([]domain.AuditEntry, error) {
+	s.ListForBannerInvoked = true
+	return s.ListForBannerFn(ctx, id)
+}
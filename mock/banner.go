@@ -1,22 +1,29 @@
 package mock
 
 import (
+	"context"
+
 	domain "github.com/DzananGanic/banner"
 )
 
-// BannerDB provides banner repository mock
+// BannerDB provides banner repository mock. Context variants of the
+// methods fall back to the plain mock functions when their own is not set
 type BannerDB struct {
-	SaveFn      func(b domain.Banner) (domain.BannerID, error)
-	SaveInvoked bool
+	SaveFn        func(b domain.Banner) (domain.BannerID, error)
+	SaveContextFn func(ctx context.Context, b domain.Banner) (domain.BannerID, error)
+	SaveInvoked   bool
 
-	FetchForIDFn      func(id domain.BannerID) (*domain.Banner, error)
-	FetchForIDInvoked bool
+	FetchForIDFn        func(id domain.BannerID) (*domain.Banner, error)
+	FetchForIDContextFn func(ctx context.Context, id domain.BannerID) (*domain.Banner, error)
+	FetchForIDInvoked   bool
 
-	ListFn      func() ([]domain.Banner, error)
-	ListInvoked bool
+	ListFn        func() ([]domain.Banner, error)
+	ListContextFn func(ctx context.Context) ([]domain.Banner, error)
+	ListInvoked   bool
 
-	DeleteFn      func(domain.BannerID) error
-	DeleteInvoked bool
+	DeleteFn        func(domain.BannerID) error
+	DeleteContextFn func(context.Context, domain.BannerID) error
+	DeleteInvoked   bool
 }
 
 // Save represents the mock for Save banner repository method
@@ -25,20 +32,56 @@ func (bdb *BannerDB) Save(b domain.Banner) (domain.BannerID, error) {
 	return bdb.SaveFn(b)
 }
 
+// SaveContext represents the mock for SaveContext banner repository method
+func (bdb *BannerDB) SaveContext(ctx context.Context, b domain.Banner) (domain.BannerID, error) {
+	if bdb.SaveContextFn == nil {
+		return bdb.Save(b)
+	}
+	bdb.SaveInvoked = true
+	return bdb.SaveContextFn(ctx, b)
+}
+
 // FetchForID represents the mock for FetchForID banner repository method
 func (bdb *BannerDB) FetchForID(id domain.BannerID) (*domain.Banner, error) {
 	bdb.FetchForIDInvoked = true
 	return bdb.FetchForIDFn(id)
 }
 
+// FetchForIDContext represents the mock for FetchForIDContext banner repository method
+func (bdb *BannerDB) FetchForIDContext(ctx context.Context, id domain.BannerID) (*domain.Banner, error) {
+	if bdb.FetchForIDContextFn == nil {
+		return bdb.FetchForID(id)
+	}
+	bdb.FetchForIDInvoked = true
+	return bdb.FetchForIDContextFn(ctx, id)
+}
+
 // List represents the mock for List banner repository method
 func (bdb *BannerDB) List() ([]domain.Banner, error) {
 	bdb.ListInvoked = true
 	return bdb.ListFn()
 }
 
+// ListContext represents the mock for ListContext banner repository method
+func (bdb *BannerDB) ListContext(ctx context.Context) ([]domain.Banner, error) {
+	if bdb.ListContextFn == nil {
+		return bdb.List()
+	}
+	bdb.ListInvoked = true
+	return bdb.ListContextFn(ctx)
+}
+
 // Delete represents the mock for Delete banner repository method
 func (bdb *BannerDB) Delete(id domain.BannerID) error {
 	bdb.DeleteInvoked = true
 	return bdb.DeleteFn(id)
 }
+
+// DeleteContext represents the mock for DeleteContext banner repository method
+func (bdb *BannerDB) DeleteContext(ctx context.Context, id domain.BannerID) error {
+	if bdb.DeleteContextFn == nil {
+		return bdb.Delete(id)
+	}
+	bdb.DeleteInvoked = true
+	return bdb.DeleteContextFn(ctx, id)
+}
@@ -1,15 +1,30 @@
 package mock
 
-import domain "github.com/DzananGanic/banner"
+import (
+	"context"
+
+	domain "github.com/DzananGanic/banner"
+)
 
 // BannerDisplayer provides banner displayer repository mock
 type BannerDisplayer struct {
-	DisplayBannerFn      func() (*domain.Banner, error)
-	DisplayBannerInvoked bool
+	DisplayBannerFn        func(domain.Viewer) (*domain.Banner, error)
+	DisplayBannerContextFn func(context.Context, domain.Viewer) (*domain.Banner, error)
+	DisplayBannerInvoked   bool
 }
 
 // DisplayBanner represents the mock for DisplayBanner banner repository method
-func (bdb *BannerDisplayer) DisplayBanner() (*domain.Banner, error) {
+func (bdb *BannerDisplayer) DisplayBanner(v domain.Viewer) (*domain.Banner, error) {
+	bdb.DisplayBannerInvoked = true
+	return bdb.DisplayBannerFn(v)
+}
+
+// DisplayBannerContext represents the mock for DisplayBannerContext banner repository method.
+// It falls back to DisplayBannerFn when DisplayBannerContextFn is not set
+func (bdb *BannerDisplayer) DisplayBannerContext(ctx context.Context, v domain.Viewer) (*domain.Banner, error) {
+	if bdb.DisplayBannerContextFn == nil {
+		return bdb.DisplayBanner(v)
+	}
 	bdb.DisplayBannerInvoked = true
-	return bdb.DisplayBannerFn()
+	return bdb.DisplayBannerContextFn(ctx, v)
 }
@@ -0,0 +1,41 @@
+package mock
+
+import (
+	"sync"
+	"time"
+)
+
+// NewClock creates new clock mock stopped at the given time
+func NewClock(now time.Time) *Clock {
+	return &Clock{now: now}
+}
+
+// Clock provides clock mock which only moves when told to
+type Clock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+// Now returns the time the clock is stopped at
+func (c *Clock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+// Set moves the clock to the given time
+func (c *Clock) Set(now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = now
+}
+
+// Advance moves the clock forward by the given duration
+func (c *Clock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = c.now.Add(d)
+}
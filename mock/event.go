@@ -0,0 +1,31 @@
+package mock
+
+import (
+	"context"
+
+	domain "github.com/DzananGanic/banner"
+)
+
+// EventEmitter provides event emitter mock
+type EventEmitter struct {
+	EmitFn      func(context.Context, domain.Event) error
+	EmitInvoked bool
+}
+
+// Emit represents the mock for Emit event emitter method
+func (e *EventEmitter) Emit(ctx context.Context, ev domain.Event) error {
+	e.EmitInvoked = true
+	return e.EmitFn(ctx, ev)
+}
+
+// EventPublisher provides event publisher mock
+type EventPublisher struct {
+	PublishFn      func(context.Context, domain.Event) error
+	PublishInvoked bool
+}
+
+// Publish represents the mock for Publish event publisher method
+func (p *EventPublisher) Publish(ctx context.Context, ev domain.Event) error {
+	p.PublishInvoked = true
+	return p.PublishFn(ctx, ev)
+}
@@ -0,0 +1,17 @@
+package mock
+
+import (
+	"context"
+)
+
+// Transactor provides transactor mock
+type Transactor struct {
+	WithinTxFn      func(context.Context, func(context.Context) error) error
+	WithinTxInvoked bool
+}
+
+// WithinTx represents the mock for WithinTx transactor method
+func (t *Transactor) WithinTx(ctx context.Context, fn func(context.Context) error) error {
+	t.WithinTxInvoked = true
+	return t.WithinTxFn(ctx, fn)
+}
@@ -0,0 +1,18 @@
+// Package clock contains the clock implementation
+// which reads the system time
+package clock
+
+import "time"
+
+// New creates new system clock
+func New() System {
+	return System{}
+}
+
+// System represents the clock which returns the system time
+type System struct{}
+
+// Now returns the current system time
+func (System) Now() time.Time {
+	return time.Now()
+}
@@ -1,8 +1,10 @@
 package displayer
 
 import (
-	"fmt"
+	"context"
+	"errors"
 	"sort"
+	"sync"
 	"time"
 
 	domain "github.com/DzananGanic/banner"
@@ -10,62 +12,172 @@ import (
 
 // NewBasic is factory method that creates new
 // banner displayer with basic banner selection algorithm
+// If the ordering is nil, DefaultOrdering is used, and
+// if the event emitter is nil, no events are emitted
 func NewBasic(
 	banners domain.BannerDB,
 	activeProvider domain.ActiveBannerProvider,
-	ip func() (string, error),
+	preview domain.PreviewPolicy,
+	clock domain.Clock,
+	order Ordering,
+	events domain.EventEmitter,
 ) *BasicBannerDisplayer {
+	if order == nil {
+		order = DefaultOrdering
+	}
+
 	return &BasicBannerDisplayer{
-		banners:        banners,
-		activeProvider: activeProvider,
-		ip:             ip,
+		banners:        domain.BannerDBWithContext(banners),
+		activeProvider: domain.ActiveBannerProviderWithContext(activeProvider),
+		preview:        preview,
+		clock:          clock,
+		order:          order,
+		events:         events,
 	}
 }
 
 // BasicBannerDisplayer represents the basic
 // banner provider interface implementation
 // It implements basic banner selection algorithm
+//
+// As the selected banner is shared with every viewer through
+// the active banner provider, targeted banners are skipped,
+// see TargetedBannerDisplayer
+//
+// The banner becoming the active one is reported with the
+// activated event, and the expired active banner it replaces
+// with the expired event. The previously active banner is the
+// cached one, or the one last activated by the displayer if the
+// cache has been cleared or has expired, and its expiry is checked
+// against its stored version rather than against the cached one
 type BasicBannerDisplayer struct {
-	banners        domain.BannerDB
-	activeProvider domain.ActiveBannerProvider
-	ip             func() (string, error)
+	banners        domain.BannerDBContext
+	activeProvider domain.ActiveBannerProviderContext
+	preview        domain.PreviewPolicy
+	clock          domain.Clock
+	order          Ordering
+	events         domain.EventEmitter
+
+	mu        sync.Mutex
+	activated domain.BannerID
 }
 
-// DisplayBanner returns the banner that should be shown
-func (bp *BasicBannerDisplayer) DisplayBanner() (*domain.Banner, error) {
-	abanner, err := bp.activeProvider.Get()
+// DisplayBanner returns the banner that should be shown to the viewer
+func (bp *BasicBannerDisplayer) DisplayBanner(v domain.Viewer) (*domain.Banner, error) {
+	return bp.DisplayBannerContext(context.Background(), v)
+}
+
+// DisplayBannerContext returns the banner that should be shown to the viewer
+func (bp *BasicBannerDisplayer) DisplayBannerContext(ctx context.Context, v domain.Viewer) (*domain.Banner, error) {
+	now := bp.clock.Now()
+
+	// viewers allowed to preview banners get their own selection,
+	// which is not cached as it may contain banner outside
+	// of its display period that others must not see
+	if bp.preview.Allows(v.IP) {
+		return bp.findNextBanner(ctx, now, true)
+	}
+
+	abanner, err := bp.activeProvider.GetContext(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	if !abanner.IsExpired(time.Now()) {
+	// the cached banner may also be between its recurring windows
+	if abanner != nil && abanner.IsInDisplayPeriod(now) {
 		return abanner, nil
 	}
 
-	nextBanner, err := bp.findNextBanner()
+	nextBanner, err := bp.findNextBanner(ctx, now, false)
 	if err != nil {
 		return nil, err
 	}
 
-	err = bp.activeProvider.Set(*nextBanner)
+	previous := bp.lastActivated()
+	if abanner != nil {
+		previous = abanner.ID
+	}
+
+	// the events are emitted before the banner is set, so that
+	// the failed emit is retried by the next display request.
+	// Repopulating the cache with the same banner emits nothing
+	if previous != nextBanner.ID {
+		err = bp.emitExpired(ctx, previous, now)
+		if err != nil {
+			return nil, err
+		}
+		err = bp.emit(ctx, domain.EventBannerActivated, nextBanner, now)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = bp.activeProvider.SetContext(ctx, *nextBanner)
 	if err != nil {
 		return nil, err
 	}
 
+	bp.mu.Lock()
+	bp.activated = nextBanner.ID
+	bp.mu.Unlock()
+
 	return nextBanner, nil
 }
 
-func (bp *BasicBannerDisplayer) findNextBanner() (*domain.Banner, error) {
-	banners, err := bp.banners.List()
+func (bp *BasicBannerDisplayer) lastActivated() domain.BannerID {
+	bp.mu.Lock()
+	defer bp.mu.Unlock()
+
+	return bp.activated
+}
+
+// emitExpired emits the expired event if the stored version of the
+// previously active banner is expired. Nothing is emitted for the
+// deleted banner, as its removal is reported with the deleted event
+func (bp *BasicBannerDisplayer) emitExpired(ctx context.Context, id domain.BannerID, now time.Time) error {
+	if bp.events == nil || id == 0 {
+		return nil
+	}
+
+	b, err := bp.banners.FetchForIDContext(ctx, id)
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !b.IsExpired(now) {
+		return nil
+	}
+
+	return bp.emit(ctx, domain.EventBannerExpired, b, now)
+}
+
+func (bp *BasicBannerDisplayer) emit(ctx context.Context, typ domain.EventType, b *domain.Banner, now time.Time) error {
+	if bp.events == nil {
+		return nil
+	}
+
+	return bp.events.Emit(ctx, domain.Event{
+		Type:     typ,
+		BannerID: b.ID,
+		At:       now.UTC(),
+		Banner:   b,
+	})
+}
+
+func (bp *BasicBannerDisplayer) findNextBanner(ctx context.Context, now time.Time, preview bool) (*domain.Banner, error) {
+	banners, err := bp.banners.ListContext(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	// we sort the slice by expiration date because of the following requirement:
+	// we sort the slice so that the preferred banner comes first, by default
+	// by priority and then by expiration date because of the following requirement:
 	// "there may be occasions where two banners are considered active. In this case,
 	// the banner with the earlier expiration should be displayed."
-	sort.Slice(banners, func(i, j int) bool {
-		return banners[i].ExpiresAt.Before(banners[j].ExpiresAt)
+	sort.SliceStable(banners, func(i, j int) bool {
+		return bp.order(&banners[i], &banners[j])
 	})
 
 	for _, b := range banners {
@@ -73,26 +185,27 @@ func (bp *BasicBannerDisplayer) findNextBanner() (*domain.Banner, error) {
 		// in the future we would have a better way of handling this
 		// either through database property to filter out expired
 		// banners through query or something like that
-		if b.IsExpired(time.Now()) {
+		if b.IsExpired(now) {
 			continue
 		}
 
-		iip, err := bp.ip()
-		if err != nil {
-			return nil, err
+		// targeted banners must not be shared with
+		// the viewers outside of their audience
+		if b.IsTargeted() {
+			continue
 		}
 
-		// if internal ip address is 10.0.0.1 or 10.0.0.2,
+		// if the viewer is allowed to preview banners,
 		// then it does not matter whether banner is in display period
-		if iip == "10.0.0.1" || iip == "10.0.0.2" {
+		if preview {
 			return &b, nil
 		}
 
 		// if not, we just check whether the banner is in display period
-		if b.IsInDisplayPeriod(time.Now()) {
+		if b.IsInDisplayPeriod(now) {
 			return &b, nil
 		}
 	}
 
-	return nil, fmt.Errorf("no active banners found")
+	return nil, domain.ErrNoActiveBanner
 }
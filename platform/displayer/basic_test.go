@@ -1,15 +1,16 @@
 package displayer_test
 
 import (
+	"context"
 	"fmt"
 	"testing"
 	"time"
 
 	domain "github.com/DzananGanic/banner"
-	"github.com/DzananGanic/banner/platform/ip"
 
 	"github.com/DzananGanic/banner/mock"
 	"github.com/DzananGanic/banner/platform/displayer"
+	"github.com/DzananGanic/banner/platform/preview"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -19,12 +20,13 @@ func TestBasicDisplayBanner(t *testing.T) {
 		now        func() time.Time
 		bdb        func() *mock.BannerDB
 		ap         func() *mock.ActiveBannerProvider
-		ipProvider func() (string, error)
+		viewer     domain.Viewer
 		wantBanner *domain.Banner
 		wantErr    bool
 	}{
 		{
 			name: "test active banner provider error",
+			now:  fixedNow,
 			bdb: func() *mock.BannerDB {
 				return nil
 			},
@@ -35,11 +37,11 @@ func TestBasicDisplayBanner(t *testing.T) {
 				}
 				return active
 			},
-			ipProvider: ip.Internal,
-			wantErr:    true,
+			wantErr: true,
 		},
 		{
 			name: "test return currently active banner",
+			now:  fixedNow,
 			bdb: func() *mock.BannerDB {
 				return nil
 			},
@@ -53,11 +55,11 @@ func TestBasicDisplayBanner(t *testing.T) {
 			wantBanner: &domain.Banner{
 				ExpiresAt: time.Date(2025, 1, 1, 1, 1, 1, 1, time.Local),
 			},
-			ipProvider: ip.Internal,
-			wantErr:    false,
+			wantErr: false,
 		},
 		{
 			name: "test active is expired and find next banner throws error",
+			now:  fixedNow,
 			bdb: func() *mock.BannerDB {
 				db := &mock.BannerDB{}
 				db.ListFn = func() ([]domain.Banner, error) {
@@ -72,11 +74,11 @@ func TestBasicDisplayBanner(t *testing.T) {
 				}
 				return active
 			},
-			ipProvider: ip.Internal,
-			wantErr:    true,
+			wantErr: true,
 		},
 		{
 			name: "test active provider set throws error",
+			now:  fixedNow,
 			bdb: func() *mock.BannerDB {
 				db := &mock.BannerDB{}
 				db.ListFn = func() ([]domain.Banner, error) {
@@ -99,11 +101,11 @@ func TestBasicDisplayBanner(t *testing.T) {
 				}
 				return active
 			},
-			ipProvider: ip.Internal,
-			wantErr:    true,
+			wantErr: true,
 		},
 		{
 			name: "test successfully return new banner",
+			now:  fixedNow,
 			bdb: func() *mock.BannerDB {
 				db := &mock.BannerDB{}
 				db.ListFn = func() ([]domain.Banner, error) {
@@ -126,8 +128,7 @@ func TestBasicDisplayBanner(t *testing.T) {
 				}
 				return active
 			},
-			ipProvider: ip.Internal,
-			wantErr:    false,
+			wantErr: false,
 			wantBanner: &domain.Banner{
 				ScheduledDisplayingAt: time.Date(2017, 1, 1, 1, 1, 1, 1, time.Local),
 				ExpiresAt:             time.Date(2021, 1, 1, 1, 1, 1, 1, time.Local),
@@ -135,6 +136,7 @@ func TestBasicDisplayBanner(t *testing.T) {
 		},
 		{
 			name: "test two active banners, should show one with earlier expiration date",
+			now:  fixedNow,
 			bdb: func() *mock.BannerDB {
 				db := &mock.BannerDB{}
 				db.ListFn = func() ([]domain.Banner, error) {
@@ -161,8 +163,7 @@ func TestBasicDisplayBanner(t *testing.T) {
 				}
 				return active
 			},
-			wantErr:    false,
-			ipProvider: ip.Internal,
+			wantErr: false,
 			wantBanner: &domain.Banner{
 				ScheduledDisplayingAt: time.Date(2017, 1, 1, 1, 1, 1, 1, time.Local),
 				ExpiresAt:             time.Date(2020, 1, 1, 1, 1, 1, 1, time.Local),
@@ -170,6 +171,7 @@ func TestBasicDisplayBanner(t *testing.T) {
 		},
 		{
 			name: "test skip unactive banner",
+			now:  fixedNow,
 			bdb: func() *mock.BannerDB {
 				db := &mock.BannerDB{}
 				db.ListFn = func() ([]domain.Banner, error) {
@@ -196,8 +198,7 @@ func TestBasicDisplayBanner(t *testing.T) {
 				}
 				return active
 			},
-			wantErr:    false,
-			ipProvider: ip.Internal,
+			wantErr: false,
 			wantBanner: &domain.Banner{
 				ScheduledDisplayingAt: time.Date(2017, 1, 1, 1, 1, 1, 1, time.Local),
 				ExpiresAt:             time.Date(2020, 1, 1, 1, 1, 1, 1, time.Local),
@@ -205,6 +206,7 @@ func TestBasicDisplayBanner(t *testing.T) {
 		},
 		{
 			name: "test no active banners found",
+			now:  fixedNow,
 			bdb: func() *mock.BannerDB {
 				db := &mock.BannerDB{}
 				db.ListFn = func() ([]domain.Banner, error) {
@@ -231,11 +233,11 @@ func TestBasicDisplayBanner(t *testing.T) {
 				}
 				return active
 			},
-			wantErr:    true,
-			ipProvider: ip.Internal,
+			wantErr: true,
 		},
 		{
-			name: "test error getting ip address",
+			name: "test show banner if viewer IP is 10.0.0.1 even before display period",
+			now:  fixedNow,
 			bdb: func() *mock.BannerDB {
 				db := &mock.BannerDB{}
 				db.ListFn = func() ([]domain.Banner, error) {
@@ -262,13 +264,16 @@ func TestBasicDisplayBanner(t *testing.T) {
 				}
 				return active
 			},
-			ipProvider: func() (string, error) {
-				return "", fmt.Errorf("error getting ip address")
+			viewer:  domain.Viewer{IP: "10.0.0.1"},
+			wantErr: false,
+			wantBanner: &domain.Banner{
+				ScheduledDisplayingAt: time.Date(2021, 1, 1, 1, 1, 1, 1, time.Local),
+				ExpiresAt:             time.Date(2022, 1, 1, 1, 1, 1, 1, time.Local),
 			},
-			wantErr: true,
 		},
 		{
-			name: "test show banner if internal IP is 10.0.0.1 even before display period",
+			name: "test show banner if viewer IP is in preview CIDR block even before display period",
+			now:  fixedNow,
 			bdb: func() *mock.BannerDB {
 				db := &mock.BannerDB{}
 				db.ListFn = func() ([]domain.Banner, error) {
@@ -295,26 +300,97 @@ func TestBasicDisplayBanner(t *testing.T) {
 				}
 				return active
 			},
-			ipProvider: func() (string, error) {
-				return "10.0.0.1", nil
-			},
+			viewer:  domain.Viewer{IP: "10.1.2.3"},
 			wantErr: false,
 			wantBanner: &domain.Banner{
 				ScheduledDisplayingAt: time.Date(2021, 1, 1, 1, 1, 1, 1, time.Local),
 				ExpiresAt:             time.Date(2022, 1, 1, 1, 1, 1, 1, time.Local),
 			},
 		},
+		{
+			name: "test do not show banner before display period if viewer IP is not allowed to preview",
+			now:  fixedNow,
+			bdb: func() *mock.BannerDB {
+				db := &mock.BannerDB{}
+				db.ListFn = func() ([]domain.Banner, error) {
+					return []domain.Banner{
+						{
+							ScheduledDisplayingAt: time.Date(2017, 1, 1, 1, 1, 1, 1, time.Local),
+							ExpiresAt:             time.Date(2018, 1, 1, 1, 1, 1, 1, time.Local),
+						},
+						{
+							ScheduledDisplayingAt: time.Date(2021, 1, 1, 1, 1, 1, 1, time.Local),
+							ExpiresAt:             time.Date(2022, 1, 1, 1, 1, 1, 1, time.Local),
+						},
+					}, nil
+				}
+				return db
+			},
+			ap: func() *mock.ActiveBannerProvider {
+				active := &mock.ActiveBannerProvider{}
+				active.GetFn = func() (*domain.Banner, error) {
+					return &domain.Banner{ExpiresAt: time.Date(2008, 1, 1, 1, 1, 1, 1, time.Local)}, nil
+				}
+				active.SetFn = func(b domain.Banner) error {
+					return nil
+				}
+				return active
+			},
+			viewer:  domain.Viewer{IP: "10.2.0.1"},
+			wantErr: true,
+		},
+		{
+			name: "test select next banner once clock passes active banner expiration",
+			now: func() time.Time {
+				return time.Date(2020, 6, 1, 0, 0, 0, 0, time.Local)
+			},
+			bdb: func() *mock.BannerDB {
+				db := &mock.BannerDB{}
+				db.ListFn = func() ([]domain.Banner, error) {
+					return []domain.Banner{
+						{
+							ID:                    2,
+							ScheduledDisplayingAt: time.Date(2017, 1, 1, 1, 1, 1, 1, time.Local),
+							ExpiresAt:             time.Date(2021, 1, 1, 1, 1, 1, 1, time.Local),
+						},
+					}, nil
+				}
+				return db
+			},
+			ap: func() *mock.ActiveBannerProvider {
+				active := &mock.ActiveBannerProvider{}
+				active.GetFn = func() (*domain.Banner, error) {
+					return &domain.Banner{ID: 1, ExpiresAt: time.Date(2020, 1, 1, 1, 1, 1, 1, time.Local)}, nil
+				}
+				active.SetFn = func(b domain.Banner) error {
+					return nil
+				}
+				return active
+			},
+			wantErr: false,
+			wantBanner: &domain.Banner{
+				ID:                    2,
+				ScheduledDisplayingAt: time.Date(2017, 1, 1, 1, 1, 1, 1, time.Local),
+				ExpiresAt:             time.Date(2021, 1, 1, 1, 1, 1, 1, time.Local),
+			},
+		},
 	}
 
 	for _, c := range cases {
 		t.Run(c.name, func(t *testing.T) {
+			policy, err := preview.NewPolicy("10.0.0.1", "10.0.0.2", "10.1.0.0/16")
+			assert.Nil(t, err)
+
 			svc := displayer.NewBasic(
 				c.bdb(),
 				c.ap(),
-				c.ipProvider,
+				policy,
+				mock.NewClock(c.now()),
+				nil,
+				nil,
 			)
 
-			resp, err := svc.DisplayBanner()
+			resp, err := svc.DisplayBanner(c.viewer)
 			if c.wantBanner != nil {
 				assert.Equal(t, resp, c.wantBanner)
 			}
@@ -326,3 +402,222 @@ func TestBasicDisplayBanner(t *testing.T) {
 		})
 	}
 }
+
+// fixedNow returns the time the displayer tests are run at,
+// so that they do not depend on the real time passing
+func fixedNow() time.Time {
+	return time.Date(2019, 6, 1, 0, 0, 0, 0, time.Local)
+}
+
+func TestBasicDisplayBannerPreviewIsNotCached(t *testing.T) {
+	scheduled := domain.Banner{
+		ID:                    1,
+		ScheduledDisplayingAt: time.Date(2021, 1, 1, 1, 1, 1, 1, time.Local),
+		ExpiresAt:             time.Date(2022, 1, 1, 1, 1, 1, 1, time.Local),
+	}
+
+	db := &mock.BannerDB{}
+	db.ListFn = func() ([]domain.Banner, error) {
+		return []domain.Banner{scheduled}, nil
+	}
+	active := &mock.ActiveBannerProvider{}
+	active.GetFn = func() (*domain.Banner, error) {
+		return nil, nil
+	}
+	active.SetFn = func(b domain.Banner) error {
+		return nil
+	}
+
+	policy, err := preview.NewPolicy("10.0.0.1")
+	assert.Nil(t, err)
+
+	svc := displayer.NewBasic(db, active, policy, mock.NewClock(fixedNow()), nil, nil)
+
+	resp, err := svc.DisplayBanner(domain.Viewer{IP: "10.0.0.1"})
+	assert.Nil(t, err)
+	assert.Equal(t, &scheduled, resp)
+	assert.False(t, active.GetInvoked)
+	assert.False(t, active.SetInvoked)
+
+	// regular viewer must not see the banner previewed before
+	_, err = svc.DisplayBanner(domain.Viewer{IP: "192.0.2.1"})
+	assert.Equal(t, domain.ErrNoActiveBanner, err)
+	assert.False(t, active.SetInvoked)
+}
+
+func TestBasicDisplayBannerActiveOutsideRecurringWindow(t *testing.T) {
+	lunch := domain.Banner{
+		ID:                    1,
+		ScheduledDisplayingAt: time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC),
+		ExpiresAt:             time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+		Priority:              1,
+		Recurrence: &domain.Recurrence{
+			Frequency: domain.Daily,
+			StartHour: 11,
+			Duration:  3 * time.Hour,
+		},
+	}
+	regular := domain.Banner{
+		ID:                    2,
+		ScheduledDisplayingAt: time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC),
+		ExpiresAt:             time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	db := &mock.BannerDB{}
+	db.ListFn = func() ([]domain.Banner, error) {
+		return []domain.Banner{lunch, regular}, nil
+	}
+	active := &mock.ActiveBannerProvider{}
+	active.GetFn = func() (*domain.Banner, error) {
+		return &lunch, nil
+	}
+	var set domain.Banner
+	active.SetFn = func(b domain.Banner) error {
+		set = b
+		return nil
+	}
+
+	policy, err := preview.NewPolicy()
+	assert.Nil(t, err)
+
+	svc := displayer.NewBasic(db, active, policy, mock.NewClock(fixedNow()), nil, nil)
+
+	// the cached banner is not displayed between its windows
+	resp, err := svc.DisplayBanner(domain.Viewer{IP: "192.0.2.1"})
+	assert.Nil(t, err)
+	assert.Equal(t, &regular, resp)
+	assert.Equal(t, regular, set)
+}
+
+func TestBasicDisplayBannerEmitsEvents(t *testing.T) {
+	expired := domain.Banner{
+		ID:                    1,
+		ScheduledDisplayingAt: time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC),
+		ExpiresAt:             time.Date(2019, 5, 1, 0, 0, 0, 0, time.UTC),
+	}
+	next := domain.Banner{
+		ID:                    2,
+		ScheduledDisplayingAt: time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC),
+		ExpiresAt:             time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	cases := []struct {
+		name      string
+		active    *domain.Banner
+		emitErr   error
+		wantTypes []domain.EventType
+		wantSet   bool
+	}{
+		{
+			name:      "test emit activated",
+			wantTypes: []domain.EventType{domain.EventBannerActivated},
+			wantSet:   true,
+		},
+		{
+			name:      "test emit expired and activated",
+			active:    &expired,
+			wantTypes: []domain.EventType{domain.EventBannerExpired, domain.EventBannerActivated},
+			wantSet:   true,
+		},
+		{
+			name:      "test cached banner emits nothing",
+			active:    &next,
+			wantTypes: nil,
+		},
+		{
+			name:      "test failed emit leaves banner unset",
+			emitErr:   fmt.Errorf("database error"),
+			wantTypes: []domain.EventType{domain.EventBannerActivated},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			db := &mock.BannerDB{}
+			db.ListFn = func() ([]domain.Banner, error) {
+				return []domain.Banner{expired, next}, nil
+			}
+			db.FetchForIDFn = func(id domain.BannerID) (*domain.Banner, error) {
+				return &expired, nil
+			}
+			active := &mock.ActiveBannerProvider{}
+			active.GetFn = func() (*domain.Banner, error) {
+				return c.active, nil
+			}
+			active.SetFn = func(b domain.Banner) error {
+				return nil
+			}
+			var types []domain.EventType
+			events := &mock.EventEmitter{
+				EmitFn: func(ctx context.Context, e domain.Event) error {
+					types = append(types, e.Type)
+					return c.emitErr
+				},
+			}
+
+			svc := displayer.NewBasic(db, active, &preview.Policy{}, mock.NewClock(fixedNow()), nil, events)
+
+			_, err := svc.DisplayBanner(domain.Viewer{IP: "192.0.2.1"})
+			assert.Equal(t, c.emitErr == nil, err == nil)
+			assert.Equal(t, c.wantTypes, types)
+			assert.Equal(t, c.wantSet, active.SetInvoked)
+		})
+	}
+}
+
+func TestBasicDisplayBannerEmitsEventsAfterCacheLoss(t *testing.T) {
+	first := domain.Banner{
+		ID:                    1,
+		ScheduledDisplayingAt: time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC),
+		ExpiresAt:             time.Date(2019, 7, 1, 0, 0, 0, 0, time.UTC),
+	}
+	second := domain.Banner{
+		ID:                    2,
+		ScheduledDisplayingAt: time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC),
+		ExpiresAt:             time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	db := &mock.BannerDB{}
+	db.ListFn = func() ([]domain.Banner, error) {
+		return []domain.Banner{first, second}, nil
+	}
+	db.FetchForIDFn = func(id domain.BannerID) (*domain.Banner, error) {
+		return &first, nil
+	}
+	// the cache never holds the banner, as if it was cleared or expired
+	active := &mock.ActiveBannerProvider{}
+	active.GetFn = func() (*domain.Banner, error) {
+		return nil, nil
+	}
+	active.SetFn = func(b domain.Banner) error {
+		return nil
+	}
+	var types []domain.EventType
+	events := &mock.EventEmitter{
+		EmitFn: func(ctx context.Context, e domain.Event) error {
+			types = append(types, e.Type)
+			return nil
+		},
+	}
+	clock := mock.NewClock(fixedNow())
+
+	svc := displayer.NewBasic(db, active, &preview.Policy{}, clock, nil, events)
+
+	resp, err := svc.DisplayBanner(domain.Viewer{IP: "192.0.2.1"})
+	assert.Nil(t, err)
+	assert.Equal(t, first.ID, resp.ID)
+	assert.Equal(t, []domain.EventType{domain.EventBannerActivated}, types)
+
+	// repopulating the cache with the same banner emits nothing
+	types = nil
+	_, err = svc.DisplayBanner(domain.Viewer{IP: "192.0.2.1"})
+	assert.Nil(t, err)
+	assert.Nil(t, types)
+
+	// the expiry is detected from the stored banner
+	clock.Set(time.Date(2019, 8, 1, 0, 0, 0, 0, time.UTC))
+	resp, err = svc.DisplayBanner(domain.Viewer{IP: "192.0.2.1"})
+	assert.Nil(t, err)
+	assert.Equal(t, second.ID, resp.ID)
+	assert.Equal(t, []domain.EventType{domain.EventBannerExpired, domain.EventBannerActivated}, types)
+}
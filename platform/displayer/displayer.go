@@ -0,0 +1,39 @@
+// Package displayer contains banner displayer implementations,
+// i.e. strategies selecting the banner shown to the viewer
+package displayer
+
+import (
+	"fmt"
+
+	domain "github.com/DzananGanic/banner"
+)
+
+// Kinds lists the supported banner displayers
+var Kinds = []string{"basic", "weighted"}
+
+// New creates the banner displayer of the given kind, which displays
+// targeted banners to their audience and selects among the untargeted
+// ones with the algorithm of its kind. The ordering is used by
+// the displayers which select a single banner, and the events
+// are emitted by the ones sharing the active banner
+func New(
+	kind string,
+	banners domain.BannerDB,
+	activeProvider domain.ActiveBannerProvider,
+	preview domain.PreviewPolicy,
+	clock domain.Clock,
+	order Ordering,
+	events domain.EventEmitter,
+) (domain.BannerDisplayer, error) {
+	var fallback domain.BannerDisplayer
+	switch kind {
+	case "basic":
+		fallback = NewBasic(banners, activeProvider, preview, clock, order, events)
+	case "weighted":
+		fallback = NewWeighted(banners, preview, clock, nil)
+	default:
+		return nil, fmt.Errorf("unsupported displayer %q", kind)
+	}
+
+	return NewTargeted(banners, preview, clock, order, fallback), nil
+}
@@ -0,0 +1,74 @@
+package displayer
+
+import (
+	"fmt"
+	"strings"
+
+	domain "github.com/DzananGanic/banner"
+)
+
+// Ordering reports whether banner a should be displayed
+// rather than banner b, when both of them could be displayed
+type Ordering func(a, b *domain.Banner) bool
+
+// ByPriority prefers the banner with higher priority
+func ByPriority(a, b *domain.Banner) bool {
+	return a.Priority > b.Priority
+}
+
+// ByExpiry prefers the banner which expires earlier
+func ByExpiry(a, b *domain.Banner) bool {
+	return a.ExpiresAt.Before(b.ExpiresAt)
+}
+
+// ByScheduledDisplayingAt prefers the banner whose display period started earlier
+func ByScheduledDisplayingAt(a, b *domain.Banner) bool {
+	return a.ScheduledDisplayingAt.Before(b.ScheduledDisplayingAt)
+}
+
+// Then returns the ordering which breaks the ties
+// of the ordering o with the next one
+func (o Ordering) Then(next Ordering) Ordering {
+	return func(a, b *domain.Banner) bool {
+		if o(a, b) {
+			return true
+		}
+		if o(b, a) {
+			return false
+		}
+		return next(a, b)
+	}
+}
+
+// DefaultOrdering prefers the banner with higher priority, so that
+// e.g. an urgent outage notice overrides a long running promotion,
+// and breaks the ties by preferring the banner which expires earlier
+var DefaultOrdering = Ordering(ByPriority).Then(ByExpiry)
+
+// orderings lists the orderings which can be referred to by name
+var orderings = map[string]Ordering{
+	"priority":  ByPriority,
+	"expiry":    ByExpiry,
+	"scheduled": ByScheduledDisplayingAt,
+}
+
+// ParseOrdering parses comma separated list of ordering names, e.g.
+// "priority,expiry", into the ordering applying them in turn
+func ParseOrdering(s string) (Ordering, error) {
+	var order Ordering
+	for _, name := range strings.Split(s, ",") {
+		name = strings.TrimSpace(name)
+		next, ok := orderings[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown banner ordering %q", name)
+		}
+
+		if order == nil {
+			order = next
+		} else {
+			order = order.Then(next)
+		}
+	}
+
+	return order, nil
+}
@@ -0,0 +1,129 @@
+package displayer_test
+
+import (
+	"testing"
+	"time"
+
+	domain "github.com/DzananGanic/banner"
+	"github.com/DzananGanic/banner/mock"
+	"github.com/DzananGanic/banner/platform/displayer"
+	"github.com/DzananGanic/banner/platform/preview"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseOrdering(t *testing.T) {
+	urgent := &domain.Banner{
+		ID:        1,
+		Priority:  10,
+		ExpiresAt: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	promo := &domain.Banner{
+		ID:        2,
+		ExpiresAt: time.Date(2019, 7, 1, 0, 0, 0, 0, time.UTC),
+	}
+	sale := &domain.Banner{
+		ID:        3,
+		ExpiresAt: time.Date(2019, 8, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	cases := []struct {
+		name    string
+		order   string
+		a, b    *domain.Banner
+		want    bool
+		wantErr bool
+	}{
+		{
+			name:  "test priority first",
+			order: "priority,expiry",
+			a:     urgent,
+			b:     promo,
+			want:  true,
+		},
+		{
+			name:  "test expiry breaks the priority tie",
+			order: "priority,expiry",
+			a:     promo,
+			b:     sale,
+			want:  true,
+		},
+		{
+			name:  "test expiry only",
+			order: "expiry",
+			a:     urgent,
+			b:     promo,
+			want:  false,
+		},
+		{
+			name:    "test unknown ordering",
+			order:   "priority,name",
+			wantErr: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			order, err := displayer.ParseOrdering(c.order)
+			if c.wantErr {
+				assert.NotNil(t, err)
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.Equal(t, c.want, order(c.a, c.b))
+		})
+	}
+}
+
+func TestBasicDisplayBannerOrdering(t *testing.T) {
+	promo := domain.Banner{
+		ID:                    1,
+		ScheduledDisplayingAt: time.Date(2019, 1, 1, 0, 0, 0, 0, time.Local),
+		ExpiresAt:             time.Date(2019, 7, 1, 0, 0, 0, 0, time.Local),
+	}
+	outage := domain.Banner{
+		ID:                    2,
+		ScheduledDisplayingAt: time.Date(2019, 5, 1, 0, 0, 0, 0, time.Local),
+		ExpiresAt:             time.Date(2019, 12, 1, 0, 0, 0, 0, time.Local),
+		Priority:              10,
+	}
+
+	cases := []struct {
+		name  string
+		order displayer.Ordering
+		want  domain.Banner
+	}{
+		{
+			name:  "test default ordering prefers priority",
+			order: nil,
+			want:  outage,
+		},
+		{
+			name:  "test expiry ordering ignores priority",
+			order: displayer.ByExpiry,
+			want:  promo,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			db := &mock.BannerDB{}
+			db.ListFn = func() ([]domain.Banner, error) {
+				return []domain.Banner{promo, outage}, nil
+			}
+			active := &mock.ActiveBannerProvider{}
+			active.GetFn = func() (*domain.Banner, error) {
+				return nil, nil
+			}
+			active.SetFn = func(b domain.Banner) error {
+				return nil
+			}
+
+			svc := displayer.NewBasic(db, active, &preview.Policy{}, mock.NewClock(fixedNow()), c.order, nil)
+
+			b, err := svc.DisplayBanner(domain.Viewer{})
+			assert.Nil(t, err)
+			assert.Equal(t, &c.want, b)
+		})
+	}
+}
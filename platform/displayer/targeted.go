@@ -0,0 +1,105 @@
+package displayer
+
+import (
+	"context"
+	"errors"
+	"sort"
+
+	domain "github.com/DzananGanic/banner"
+)
+
+// NewTargeted is factory method that creates new banner displayer
+// which displays the targeted banners to the viewers in their
+// audience, falling back to the given displayer otherwise
+// If the ordering is nil, DefaultOrdering is used
+func NewTargeted(
+	banners domain.BannerDB,
+	preview domain.PreviewPolicy,
+	clock domain.Clock,
+	order Ordering,
+	fallback domain.BannerDisplayer,
+) *TargetedBannerDisplayer {
+	if order == nil {
+		order = DefaultOrdering
+	}
+
+	return &TargetedBannerDisplayer{
+		banners:  domain.BannerDBWithContext(banners),
+		preview:  preview,
+		clock:    clock,
+		order:    order,
+		fallback: domain.BannerDisplayerWithContext(fallback),
+	}
+}
+
+// TargetedBannerDisplayer represents the banner displayer which
+// selects among the targeted banners whose audience the viewer
+// belongs to. The fallback displayer selects among the untargeted
+// banners, as both BasicBannerDisplayer and WeightedBannerDisplayer
+// skip the targeted ones, and its banner is displayed instead
+// when there is no targeted banner for the viewer or when
+// the ordering prefers it to the targeted one
+type TargetedBannerDisplayer struct {
+	banners  domain.BannerDBContext
+	preview  domain.PreviewPolicy
+	clock    domain.Clock
+	order    Ordering
+	fallback domain.BannerDisplayerContext
+}
+
+// DisplayBanner returns the banner that should be shown to the viewer
+func (td *TargetedBannerDisplayer) DisplayBanner(v domain.Viewer) (*domain.Banner, error) {
+	return td.DisplayBannerContext(context.Background(), v)
+}
+
+// DisplayBannerContext returns the banner that should be shown to the viewer
+func (td *TargetedBannerDisplayer) DisplayBannerContext(ctx context.Context, v domain.Viewer) (*domain.Banner, error) {
+	targeted, err := td.findTargetedBanner(ctx, v)
+	if err != nil {
+		return nil, err
+	}
+
+	untargeted, err := td.fallback.DisplayBannerContext(ctx, v)
+	if errors.Is(err, domain.ErrNoActiveBanner) && targeted != nil {
+		return targeted, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if targeted == nil || td.order(untargeted, targeted) {
+		return untargeted, nil
+	}
+
+	return targeted, nil
+}
+
+func (td *TargetedBannerDisplayer) findTargetedBanner(ctx context.Context, v domain.Viewer) (*domain.Banner, error) {
+	now := td.clock.Now()
+	preview := td.preview.Allows(v.IP)
+
+	banners, err := td.banners.ListContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var candidates []domain.Banner
+	for _, b := range banners {
+		if !b.IsTargeted() || b.IsExpired(now) || !b.Audience.Matches(v) {
+			continue
+		}
+		if preview || b.IsInDisplayPeriod(now) {
+			candidates = append(candidates, b)
+		}
+	}
+
+	if len(candidates) == 0 {
+		return nil, nil
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return td.order(&candidates[i], &candidates[j])
+	})
+
+	return &candidates[0], nil
+}
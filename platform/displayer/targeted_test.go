@@ -0,0 +1,136 @@
+package displayer_test
+
+import (
+	"testing"
+	"time"
+
+	domain "github.com/DzananGanic/banner"
+	"github.com/DzananGanic/banner/mock"
+	"github.com/DzananGanic/banner/platform/displayer"
+	"github.com/DzananGanic/banner/platform/preview"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTargetedDisplayBanner(t *testing.T) {
+	global := domain.Banner{
+		ID:                    1,
+		ScheduledDisplayingAt: time.Date(2019, 1, 1, 0, 0, 0, 0, time.Local),
+		ExpiresAt:             time.Date(2019, 12, 1, 0, 0, 0, 0, time.Local),
+	}
+	bosnian := domain.Banner{
+		ID:                    2,
+		ScheduledDisplayingAt: time.Date(2019, 1, 1, 0, 0, 0, 0, time.Local),
+		ExpiresAt:             time.Date(2019, 12, 1, 0, 0, 0, 0, time.Local),
+		Audience:              &domain.Audience{Countries: []string{"BA"}},
+	}
+	outage := domain.Banner{
+		ID:                    3,
+		ScheduledDisplayingAt: time.Date(2019, 1, 1, 0, 0, 0, 0, time.Local),
+		ExpiresAt:             time.Date(2019, 12, 1, 0, 0, 0, 0, time.Local),
+		Priority:              10,
+	}
+	scheduled := domain.Banner{
+		ID:                    4,
+		ScheduledDisplayingAt: time.Date(2020, 1, 1, 0, 0, 0, 0, time.Local),
+		ExpiresAt:             time.Date(2020, 12, 1, 0, 0, 0, 0, time.Local),
+		Audience:              &domain.Audience{Countries: []string{"BA"}},
+		Priority:              1,
+	}
+	expiring := domain.Banner{
+		ID:                    5,
+		ScheduledDisplayingAt: time.Date(2019, 1, 1, 0, 0, 0, 0, time.Local),
+		ExpiresAt:             time.Date(2019, 9, 1, 0, 0, 0, 0, time.Local),
+	}
+
+	cases := []struct {
+		name       string
+		banners    []domain.Banner
+		order      displayer.Ordering
+		viewer     domain.Viewer
+		wantBanner *domain.Banner
+		wantErr    error
+	}{
+		{
+			name:       "test targeted banner for the viewer in audience",
+			banners:    []domain.Banner{global, bosnian},
+			viewer:     domain.Viewer{IP: "192.0.2.1", Country: "BA"},
+			wantBanner: &bosnian,
+		},
+		{
+			name:       "test fallback to untargeted banner",
+			banners:    []domain.Banner{global, bosnian},
+			viewer:     domain.Viewer{IP: "192.0.2.1", Country: "HR"},
+			wantBanner: &global,
+		},
+		{
+			name:       "test untargeted banner with higher priority wins",
+			banners:    []domain.Banner{bosnian, outage},
+			viewer:     domain.Viewer{IP: "192.0.2.1", Country: "BA"},
+			wantBanner: &outage,
+		},
+		{
+			name:       "test untargeted banner expiring earlier wins the priority tie",
+			banners:    []domain.Banner{bosnian, expiring},
+			viewer:     domain.Viewer{IP: "192.0.2.1", Country: "BA"},
+			wantBanner: &expiring,
+		},
+		{
+			name:       "test configured ordering decides between targeted and untargeted banner",
+			banners:    []domain.Banner{bosnian, outage},
+			order:      displayer.ByExpiry,
+			viewer:     domain.Viewer{IP: "192.0.2.1", Country: "BA"},
+			wantBanner: &bosnian,
+		},
+		{
+			name:       "test targeted banner without untargeted ones",
+			banners:    []domain.Banner{bosnian},
+			viewer:     domain.Viewer{IP: "192.0.2.1", Country: "BA"},
+			wantBanner: &bosnian,
+		},
+		{
+			name:    "test targeted banner is not shown outside of audience",
+			banners: []domain.Banner{bosnian},
+			viewer:  domain.Viewer{IP: "192.0.2.1", Country: "HR"},
+			wantErr: domain.ErrNoActiveBanner,
+		},
+		{
+			name:       "test preview of scheduled targeted banner",
+			banners:    []domain.Banner{global, bosnian, scheduled},
+			viewer:     domain.Viewer{IP: "10.0.0.1", Country: "BA"},
+			wantBanner: &scheduled,
+		},
+	}
+
+	policy, err := preview.NewPolicy("10.0.0.1")
+	assert.Nil(t, err)
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			db := &mock.BannerDB{}
+			db.ListFn = func() ([]domain.Banner, error) {
+				return c.banners, nil
+			}
+			active := &mock.ActiveBannerProvider{}
+			active.GetFn = func() (*domain.Banner, error) {
+				return nil, nil
+			}
+			active.SetFn = func(b domain.Banner) error {
+				assert.False(t, b.IsTargeted(), "targeted banner must not be shared")
+				return nil
+			}
+
+			clock := mock.NewClock(fixedNow())
+			svc := displayer.NewTargeted(
+				db,
+				policy,
+				clock,
+				c.order,
+				displayer.NewBasic(db, active, policy, clock, c.order, nil),
+			)
+
+			b, err := svc.DisplayBanner(c.viewer)
+			assert.Equal(t, c.wantErr, err)
+			assert.Equal(t, c.wantBanner, b)
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package displayer
+
+import (
+	"context"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+
+	domain "github.com/DzananGanic/banner"
+)
+
+// NewWeighted is factory method that creates new banner displayer
+// which rotates among the banners displayed at the same time.
+// Random source drives the rotation, if it is nil
+// the source seeded with the current time is used
+func NewWeighted(
+	banners domain.BannerDB,
+	preview domain.PreviewPolicy,
+	clock domain.Clock,
+	src rand.Source,
+) *WeightedBannerDisplayer {
+	if src == nil {
+		src = rand.NewSource(time.Now().UnixNano())
+	}
+
+	return &WeightedBannerDisplayer{
+		banners: domain.BannerDBWithContext(banners),
+		preview: preview,
+		clock:   clock,
+		rnd:     rand.New(src),
+	}
+}
+
+// WeightedBannerDisplayer represents the banner displayer which
+// picks one of the banners in display period for every viewer,
+// proportionally to the banner weights. Only the banners with
+// the highest priority among them take part in the rotation
+//
+// Unlike BasicBannerDisplayer it does not use active banner
+// provider, as there is no single active banner to share
+// Like it, it skips targeted banners, see TargetedBannerDisplayer
+type WeightedBannerDisplayer struct {
+	banners domain.BannerDBContext
+	preview domain.PreviewPolicy
+	clock   domain.Clock
+
+	// rand.Rand is not safe for concurrent use
+	mu  sync.Mutex
+	rnd *rand.Rand
+}
+
+// DisplayBanner returns the banner that should be shown to the viewer
+func (wd *WeightedBannerDisplayer) DisplayBanner(v domain.Viewer) (*domain.Banner, error) {
+	return wd.DisplayBannerContext(context.Background(), v)
+}
+
+// DisplayBannerContext returns the banner that should be shown to the viewer
+func (wd *WeightedBannerDisplayer) DisplayBannerContext(ctx context.Context, v domain.Viewer) (*domain.Banner, error) {
+	now := wd.clock.Now()
+	preview := wd.preview.Allows(v.IP)
+
+	banners, err := wd.banners.ListContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		candidates []domain.Banner
+		total      int64
+	)
+	for _, b := range banners {
+		if b.IsExpired(now) || b.IsTargeted() {
+			continue
+		}
+
+		// viewers allowed to preview banners rotate
+		// among the banners yet to be displayed as well
+		if !preview && !b.IsInDisplayPeriod(now) {
+			continue
+		}
+
+		// banner with higher priority pre-empts the
+		// ones with lower priority from the rotation
+		if len(candidates) > 0 && b.Priority > candidates[0].Priority {
+			candidates, total = candidates[:0], 0
+		}
+		if len(candidates) == 0 || b.Priority == candidates[0].Priority {
+			candidates = append(candidates, b)
+			total += int64(b.DisplayWeight())
+		}
+	}
+
+	if len(candidates) == 0 {
+		return nil, domain.ErrNoActiveBanner
+	}
+
+	// candidates are walked in a stable order so that
+	// the same random source always picks the same banners
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].ID < candidates[j].ID
+	})
+
+	n := wd.int63n(total)
+	for _, b := range candidates {
+		n -= int64(b.DisplayWeight())
+		if n < 0 {
+			return &b, nil
+		}
+	}
+
+	// unreachable, as n is always lower than the total weight
+	return &candidates[len(candidates)-1], nil
+}
+
+func (wd *WeightedBannerDisplayer) int63n(n int64) int64 {
+	wd.mu.Lock()
+	defer wd.mu.Unlock()
+
+	return wd.rnd.Int63n(n)
+}
@@ -0,0 +1,164 @@
+package displayer_test
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	domain "github.com/DzananGanic/banner"
+	"github.com/DzananGanic/banner/mock"
+	"github.com/DzananGanic/banner/platform/displayer"
+	"github.com/DzananGanic/banner/platform/preview"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWeightedDisplayBanner(t *testing.T) {
+	heavy := domain.Banner{
+		ID:                    1,
+		ScheduledDisplayingAt: time.Date(2019, 1, 1, 1, 1, 1, 1, time.Local),
+		ExpiresAt:             time.Date(2020, 1, 1, 1, 1, 1, 1, time.Local),
+		Weight:                3,
+	}
+	light := domain.Banner{
+		ID:                    2,
+		ScheduledDisplayingAt: time.Date(2019, 1, 1, 1, 1, 1, 1, time.Local),
+		ExpiresAt:             time.Date(2019, 12, 1, 1, 1, 1, 1, time.Local),
+	}
+	expired := domain.Banner{
+		ID:                    3,
+		ScheduledDisplayingAt: time.Date(2018, 1, 1, 1, 1, 1, 1, time.Local),
+		ExpiresAt:             time.Date(2019, 1, 1, 1, 1, 1, 1, time.Local),
+		Weight:                100,
+	}
+	scheduled := domain.Banner{
+		ID:                    4,
+		ScheduledDisplayingAt: time.Date(2021, 1, 1, 1, 1, 1, 1, time.Local),
+		ExpiresAt:             time.Date(2022, 1, 1, 1, 1, 1, 1, time.Local),
+	}
+
+	cases := []struct {
+		name    string
+		banners []domain.Banner
+		viewer  domain.Viewer
+		want    map[domain.BannerID]bool
+		wantErr error
+	}{
+		{
+			name:    "test rotation among banners in display period",
+			banners: []domain.Banner{scheduled, expired, light, heavy},
+			viewer:  domain.Viewer{IP: "192.0.2.1"},
+			want:    map[domain.BannerID]bool{1: true, 2: true},
+		},
+		{
+			name:    "test rotation with preview among all unexpired banners",
+			banners: []domain.Banner{scheduled, expired, light, heavy},
+			viewer:  domain.Viewer{IP: "10.0.0.1"},
+			want:    map[domain.BannerID]bool{1: true, 2: true, 4: true},
+		},
+		{
+			name:    "test no banners in display period",
+			banners: []domain.Banner{scheduled, expired},
+			viewer:  domain.Viewer{IP: "192.0.2.1"},
+			wantErr: domain.ErrNoActiveBanner,
+		},
+	}
+
+	policy, err := preview.NewPolicy("10.0.0.1")
+	assert.Nil(t, err)
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			db := &mock.BannerDB{}
+			db.ListFn = func() ([]domain.Banner, error) {
+				return c.banners, nil
+			}
+
+			svc := displayer.NewWeighted(db, policy, mock.NewClock(fixedNow()), rand.NewSource(1))
+
+			for i := 0; i < 50; i++ {
+				b, err := svc.DisplayBanner(c.viewer)
+				assert.Equal(t, c.wantErr, err)
+				if c.wantErr != nil {
+					return
+				}
+				assert.True(t, c.want[b.ID], "unexpected banner %d", b.ID)
+			}
+		})
+	}
+}
+
+func TestWeightedDisplayBannerDistribution(t *testing.T) {
+	banners := []domain.Banner{
+		{
+			ID:                    1,
+			ScheduledDisplayingAt: time.Date(2019, 1, 1, 1, 1, 1, 1, time.Local),
+			ExpiresAt:             time.Date(2020, 1, 1, 1, 1, 1, 1, time.Local),
+			Weight:                3,
+		},
+		{
+			ID:                    2,
+			ScheduledDisplayingAt: time.Date(2019, 1, 1, 1, 1, 1, 1, time.Local),
+			ExpiresAt:             time.Date(2020, 1, 1, 1, 1, 1, 1, time.Local),
+			Weight:                1,
+		},
+	}
+
+	db := &mock.BannerDB{}
+	db.ListFn = func() ([]domain.Banner, error) {
+		return banners, nil
+	}
+
+	svc := displayer.NewWeighted(db, &preview.Policy{}, mock.NewClock(fixedNow()), rand.NewSource(42))
+
+	const n = 4000
+	counts := map[domain.BannerID]int{}
+	for i := 0; i < n; i++ {
+		b, err := svc.DisplayBanner(domain.Viewer{})
+		assert.Nil(t, err)
+		counts[b.ID]++
+	}
+
+	// the banner with weight 3 gets roughly three quarters of the traffic
+	assert.InDelta(t, 0.75, float64(counts[1])/n, 0.05)
+	assert.InDelta(t, 0.25, float64(counts[2])/n, 0.05)
+
+	// the same seed always leads to the same rotation
+	first := displayer.NewWeighted(db, &preview.Policy{}, mock.NewClock(fixedNow()), rand.NewSource(7))
+	second := displayer.NewWeighted(db, &preview.Policy{}, mock.NewClock(fixedNow()), rand.NewSource(7))
+	for i := 0; i < 20; i++ {
+		a, err := first.DisplayBanner(domain.Viewer{})
+		assert.Nil(t, err)
+		b, err := second.DisplayBanner(domain.Viewer{})
+		assert.Nil(t, err)
+		assert.Equal(t, a.ID, b.ID)
+	}
+}
+
+func TestWeightedDisplayBannerPriority(t *testing.T) {
+	promo := domain.Banner{
+		ID:                    1,
+		ScheduledDisplayingAt: time.Date(2019, 1, 1, 1, 1, 1, 1, time.Local),
+		ExpiresAt:             time.Date(2020, 1, 1, 1, 1, 1, 1, time.Local),
+		Weight:                100,
+	}
+	outage := domain.Banner{
+		ID:                    2,
+		ScheduledDisplayingAt: time.Date(2019, 1, 1, 1, 1, 1, 1, time.Local),
+		ExpiresAt:             time.Date(2020, 1, 1, 1, 1, 1, 1, time.Local),
+		Priority:              1,
+	}
+
+	db := &mock.BannerDB{}
+	db.ListFn = func() ([]domain.Banner, error) {
+		return []domain.Banner{promo, outage}, nil
+	}
+
+	svc := displayer.NewWeighted(db, &preview.Policy{}, mock.NewClock(fixedNow()), rand.NewSource(1))
+
+	// banner with higher priority pre-empts the rotation regardless of weights
+	for i := 0; i < 20; i++ {
+		b, err := svc.DisplayBanner(domain.Viewer{})
+		assert.Nil(t, err)
+		assert.Equal(t, outage.ID, b.ID)
+	}
+}
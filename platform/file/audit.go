@@ -0,0 +1,123 @@
+package file
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	domain "github.com/DzananGanic/banner"
+)
+
+const (
+	auditLogName  = "audit.log"
+	auditLockName = "audit.lock"
+)
+
+// NewAuditStore opens the audit store kept in the given directory,
+// which is usually the directory of the banner repository, creating
+// it if needed, and recovers the entries from its log
+func NewAuditStore(dir string) (*AuditStore, error) {
+	err := os.MkdirAll(dir, 0o755)
+	if err != nil {
+		return nil, err
+	}
+
+	lock, err := lockDir(dir, auditLockName)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &AuditStore{
+		lock:    lock,
+		entries: make(map[domain.BannerID][]domain.AuditEntry),
+	}
+
+	s.log, err = openLog(filepath.Join(dir, auditLogName), func(payload []byte) error {
+		var e domain.AuditEntry
+		if err := json.Unmarshal(payload, &e); err != nil {
+			return err
+		}
+		s.add(e)
+		return nil
+	})
+	if err != nil {
+		lock.Close()
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// AuditStore represents file-backed audit store implementation.
+// Entries are kept in memory and appended to the log which is
+// synced to disk before Record returns. The log is never
+// compacted, as the entries are never removed
+type AuditStore struct {
+	mu      sync.RWMutex
+	lock    *os.File
+	log     *logFile
+	lastID  int64
+	entries map[domain.BannerID][]domain.AuditEntry
+}
+
+// Record stores the copy of the entry under the next available ID
+func (s *AuditStore) Record(ctx context.Context, e domain.AuditEntry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.log == nil {
+		return fmt.Errorf("audit store is closed")
+	}
+
+	e.ID = s.lastID + 1
+	err := s.log.append(e)
+	if err != nil {
+		return err
+	}
+
+	s.add(e.Clone())
+
+	return nil
+}
+
+// ListForBanner returns copies of the entries of the
+// banner in the order they were recorded
+func (s *AuditStore) ListForBanner(ctx context.Context, id domain.BannerID) ([]domain.AuditEntry, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	entries := make([]domain.AuditEntry, 0, len(s.entries[id]))
+	for _, e := range s.entries[id] {
+		entries = append(entries, e.Clone())
+	}
+
+	return entries, nil
+}
+
+// Close closes the underlying log file and releases the directory lock
+func (s *AuditStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.log == nil {
+		return nil
+	}
+
+	err := s.log.close()
+	s.log = nil
+	if lerr := s.lock.Close(); err == nil {
+		err = lerr
+	}
+
+	return err
+}
+
+func (s *AuditStore) add(e domain.AuditEntry) {
+	s.entries[e.BannerID] = append(s.entries[e.BannerID], e)
+	if e.ID > s.lastID {
+		s.lastID = e.ID
+	}
+}
@@ -0,0 +1,57 @@
+package file_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	domain "github.com/DzananGanic/banner"
+	"github.com/DzananGanic/banner/platform/file"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuditStoreSurvivesRestart(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+	at := time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	store := openAuditStore(t, dir)
+	after := &domain.Banner{ID: 1, Name: "banner", Audience: &domain.Audience{Countries: []string{"BA"}}, Version: 1}
+	assert.Nil(t, store.Record(ctx, domain.AuditEntry{BannerID: 1, Action: domain.AuditCreate, Actor: "alice", At: at, After: after}))
+	assert.Nil(t, store.Record(ctx, domain.AuditEntry{BannerID: 2, Action: domain.AuditCreate, At: at}))
+	assert.Nil(t, store.Close())
+
+	store = openAuditStore(t, dir)
+	assert.Nil(t, store.Record(ctx, domain.AuditEntry{BannerID: 1, Action: domain.AuditDelete, At: at, Before: after}))
+
+	entries, err := store.ListForBanner(ctx, 1)
+	assert.Nil(t, err)
+	assert.Len(t, entries, 2)
+	assert.Equal(t, []int64{1, 3}, []int64{entries[0].ID, entries[1].ID})
+	assert.Equal(t, "alice", entries[0].Actor)
+	assert.Equal(t, after, entries[0].After)
+	assert.Equal(t, domain.AuditDelete, entries[1].Action)
+}
+
+func TestAuditStoreLocksDirectory(t *testing.T) {
+	dir := t.TempDir()
+
+	openAuditStore(t, dir)
+	_, err := file.NewAuditStore(dir)
+	assert.NotNil(t, err)
+
+	// the banner repository in the same directory is locked separately
+	openDB(t, dir)
+}
+
+func openAuditStore(t *testing.T, dir string) *file.AuditStore {
+	t.Helper()
+
+	s, err := file.NewAuditStore(dir)
+	if err != nil {
+		t.Fatalf("failed to open audit store: %v", err)
+	}
+	t.Cleanup(func() { s.Close() })
+
+	return s
+}
@@ -0,0 +1,354 @@
+// Package file contains embedded file-backed implementation of the
+// banner repository and the audit store, meant for small single node
+// deployments where running a database server is not worth it
+//
+// Banners are kept in memory and every change is appended to a
+// write-ahead log which is synced to disk before the change is
+// applied. On startup the log is replayed, and a partially written
+// record left behind by a crash is cut off. The corrupted record
+// followed by other ones fails the recovery instead, as cutting it
+// off would drop them. The log is periodically compacted by
+// rewriting it with only the live banners
+//
+// The directory is locked while the repository is open, so that
+// only one process at a time reads and appends to the log
+package file
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	domain "github.com/DzananGanic/banner"
+)
+
+const (
+	logName  = "banners.log"
+	tmpName  = "banners.log.tmp"
+	lockName = "banners.lock"
+
+	// compactThreshold is the number of stale records in the
+	// log after which the log gets compacted
+	compactThreshold = 1024
+)
+
+const (
+	opSave   = "save"
+	opDelete = "delete"
+	opMeta   = "meta"
+)
+
+// record represents a single write-ahead log entry
+type record struct {
+	Op     string          `json:"op"`
+	Banner *domain.Banner  `json:"banner,omitempty"`
+	ID     domain.BannerID `json:"id,omitempty"`
+	LastID domain.BannerID `json:"last_id,omitempty"`
+}
+
+// NewBannerDB opens the banner repository stored in the given
+// directory, creating it if needed, and recovers its state from the log.
+// It fails if the repository is already opened by another process
+func NewBannerDB(dir string) (*BannerDB, error) {
+	err := os.MkdirAll(dir, 0o755)
+	if err != nil {
+		return nil, err
+	}
+
+	lock, err := lockDir(dir, lockName)
+	if err != nil {
+		return nil, err
+	}
+
+	// leftover of compaction interrupted by a crash,
+	// the log itself is still intact in that case
+	err = os.Remove(filepath.Join(dir, tmpName))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		lock.Close()
+		return nil, err
+	}
+
+	db := &BannerDB{
+		dir:     dir,
+		lock:    lock,
+		banners: make(map[domain.BannerID]domain.Banner),
+	}
+
+	err = db.recover()
+	if err != nil {
+		lock.Close()
+		return nil, err
+	}
+
+	return db, nil
+}
+
+// BannerDB represents file-backed banner repository implementation
+type BannerDB struct {
+	mu      sync.RWMutex
+	dir     string
+	lock    *os.File
+	log     *logFile
+	lastID  domain.BannerID
+	banners map[domain.BannerID]domain.Banner
+
+	// stale is the number of log records which
+	// do not contribute to the current state
+	stale int
+}
+
+// Save stores the banner. Banners without ID get the next
+// available one, while banners with ID are inserted or
+// replaced under that ID, if their version matches
+func (db *BannerDB) Save(b domain.Banner) (domain.BannerID, error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	if db.log == nil {
+		return 0, fmt.Errorf("banner repository is closed")
+	}
+
+	if err := b.CheckNormalized(); err != nil {
+		return 0, err
+	}
+
+	var stored *domain.Banner
+	if s, ok := db.banners[b.ID]; ok {
+		stored = &s
+	}
+	if err := b.CheckVersion(stored); err != nil {
+		return 0, err
+	}
+	b.Version = domain.NextVersion(stored)
+
+	if b.ID == 0 {
+		b.ID = db.lastID + 1
+	}
+
+	err := db.log.append(record{Op: opSave, Banner: &b})
+	if err != nil {
+		return 0, err
+	}
+
+	db.apply(record{Op: opSave, Banner: &b})
+	db.maybeCompact()
+
+	return b.ID, nil
+}
+
+// FetchForID returns the copy of the banner with the given ID
+func (db *BannerDB) FetchForID(id domain.BannerID) (*domain.Banner, error) {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	b, ok := db.banners[id]
+	if !ok {
+		return nil, fmt.Errorf("banner %d: %w", id, domain.ErrNotFound)
+	}
+	b = b.Clone()
+
+	return &b, nil
+}
+
+// List returns copies of all stored banners ordered by ID
+func (db *BannerDB) List() ([]domain.Banner, error) {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	return db.sorted(), nil
+}
+
+// Delete removes the banner with the given ID
+func (db *BannerDB) Delete(id domain.BannerID) error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	if db.log == nil {
+		return fmt.Errorf("banner repository is closed")
+	}
+
+	if _, ok := db.banners[id]; !ok {
+		return fmt.Errorf("banner %d: %w", id, domain.ErrNotFound)
+	}
+
+	err := db.log.append(record{Op: opDelete, ID: id})
+	if err != nil {
+		return err
+	}
+
+	db.apply(record{Op: opDelete, ID: id})
+	db.maybeCompact()
+
+	return nil
+}
+
+// Compact rewrites the log so that it holds only the live banners
+func (db *BannerDB) Compact() error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	if db.log == nil {
+		return fmt.Errorf("banner repository is closed")
+	}
+
+	return db.compact()
+}
+
+// Close closes the underlying log file and releases the directory lock
+func (db *BannerDB) Close() error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	if db.log == nil {
+		return nil
+	}
+
+	err := db.log.close()
+	db.log = nil
+	if lerr := db.lock.Close(); err == nil {
+		err = lerr
+	}
+
+	return err
+}
+
+// recover replays the log and truncates it after
+// the last record which was completely written
+func (db *BannerDB) recover() error {
+	l, err := openLog(filepath.Join(db.dir, logName), func(payload []byte) error {
+		var rec record
+		if err := json.Unmarshal(payload, &rec); err != nil {
+			return err
+		}
+		db.apply(rec)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	db.log = l
+
+	return nil
+}
+
+// apply applies the record to the in-memory state
+func (db *BannerDB) apply(rec record) {
+	switch rec.Op {
+	case opSave:
+		if _, ok := db.banners[rec.Banner.ID]; ok {
+			db.stale++
+		}
+		db.banners[rec.Banner.ID] = rec.Banner.Clone()
+		if rec.Banner.ID > db.lastID {
+			db.lastID = rec.Banner.ID
+		}
+	case opDelete:
+		if _, ok := db.banners[rec.ID]; ok {
+			// both the delete record and the record
+			// of the deleted banner are stale now
+			db.stale += 2
+		}
+		delete(db.banners, rec.ID)
+	case opMeta:
+		if rec.LastID > db.lastID {
+			db.lastID = rec.LastID
+		}
+	}
+}
+
+// maybeCompact compacts the log once enough of it is stale. The change
+// which triggers the compaction is already durable, so the compaction
+// failure is only logged, and the compaction is retried on the next one
+func (db *BannerDB) maybeCompact() {
+	if db.stale < compactThreshold || db.stale < len(db.banners) {
+		return
+	}
+
+	if err := db.compact(); err != nil {
+		log.Printf("banner file store: compacting log: %v", err)
+	}
+}
+
+// compact writes live banners to a temporary file and atomically
+// replaces the log with it, so a crash at any point leaves
+// either the old or the new log in place
+func (db *BannerDB) compact() error {
+	tmpPath := filepath.Join(db.dir, tmpName)
+	logPath := filepath.Join(db.dir, logName)
+
+	tmp, err := os.OpenFile(tmpPath, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+
+	// meta record keeps the last allocated ID, so IDs
+	// of deleted banners are never handed out again
+	recs := []record{{Op: opMeta, LastID: db.lastID}}
+	for _, b := range db.sorted() {
+		b := b
+		recs = append(recs, record{Op: opSave, Banner: &b})
+	}
+
+	w := bufio.NewWriter(tmp)
+	for _, rec := range recs {
+		data, err := encodeRecord(rec)
+		if err == nil {
+			_, err = w.Write(data)
+		}
+		if err != nil {
+			tmp.Close()
+			return err
+		}
+	}
+
+	err = w.Flush()
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if err == nil {
+		err = os.Rename(tmpPath, logPath)
+	}
+	if err != nil {
+		tmp.Close()
+		return err
+	}
+
+	// once renamed the new log is the one in place,
+	// so it is written to even if the sync fails
+	db.log.f.Close()
+	db.log.f = tmp
+	db.stale = 0
+
+	return syncDir(db.dir)
+}
+
+func (db *BannerDB) sorted() []domain.Banner {
+	banners := make([]domain.Banner, 0, len(db.banners))
+	for _, b := range db.banners {
+		banners = append(banners, b.Clone())
+	}
+
+	sort.Slice(banners, func(i, j int) bool {
+		return banners[i].ID < banners[j].ID
+	})
+
+	return banners
+}
+
+// syncDir syncs the directory so that the rename is durable
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+
+	return d.Sync()
+}
@@ -0,0 +1,251 @@
+package file_test
+
+import (
+	"encoding/binary"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	domain "github.com/DzananGanic/banner"
+	"github.com/DzananGanic/banner/platform/file"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBannerDBSurvivesRestart(t *testing.T) {
+	dir := t.TempDir()
+	createdAt := time.Date(2019, 1, 1, 1, 1, 1, 0, time.UTC)
+
+	db := openDB(t, dir)
+	first, err := db.Save(domain.Banner{Name: "first", CreatedAt: createdAt})
+	assert.Nil(t, err)
+	second, err := db.Save(domain.Banner{Name: "second", CreatedAt: createdAt})
+	assert.Nil(t, err)
+	_, err = db.Save(domain.Banner{ID: first, Name: "first updated", CreatedAt: createdAt})
+	assert.Nil(t, err)
+	assert.Nil(t, db.Delete(second))
+	assert.Nil(t, db.Close())
+
+	db = openDB(t, dir)
+	banners, err := db.List()
+	assert.Nil(t, err)
+	assert.Equal(t, []domain.Banner{
+		{ID: first, Name: "first updated", CreatedAt: createdAt, Version: 2},
+	}, banners)
+
+	// the version survives the restart as well
+	_, err = db.Save(domain.Banner{ID: first, Name: "stale", CreatedAt: createdAt, Version: 1})
+	assert.True(t, errors.Is(err, domain.ErrConflict))
+
+	// deleted banner IDs must not be reused
+	id, err := db.Save(domain.Banner{Name: "third"})
+	assert.Nil(t, err)
+	assert.Equal(t, domain.BannerID(3), id)
+}
+
+func TestBannerDBRecoversFromTornWrite(t *testing.T) {
+	cases := []struct {
+		name string
+		tail []byte
+	}{
+		{
+			name: "test partial header",
+			tail: []byte{0, 0},
+		},
+		{
+			name: "test partial payload",
+			tail: []byte{0, 0, 0, 100, 1, 2, 3, 4, '{', '"'},
+		},
+		{
+			name: "test checksum mismatch",
+			tail: []byte{0, 0, 0, 2, 1, 2, 3, 4, '{', '}'},
+		},
+		{
+			name: "test garbage length",
+			tail: []byte{255, 255, 255, 255, 1, 2, 3, 4},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			dir := t.TempDir()
+
+			db := openDB(t, dir)
+			_, err := db.Save(domain.Banner{Name: "survivor"})
+			assert.Nil(t, err)
+			assert.Nil(t, db.Close())
+
+			// simulate the crash in the middle of appending the next record
+			f, err := os.OpenFile(filepath.Join(dir, "banners.log"), os.O_WRONLY|os.O_APPEND, 0o644)
+			assert.Nil(t, err)
+			_, err = f.Write(c.tail)
+			assert.Nil(t, err)
+			assert.Nil(t, f.Close())
+
+			db = openDB(t, dir)
+			banners, err := db.List()
+			assert.Nil(t, err)
+			assert.Len(t, banners, 1)
+			assert.Equal(t, "survivor", banners[0].Name)
+
+			// writes after recovery must not be lost behind the torn tail
+			_, err = db.Save(domain.Banner{Name: "after crash"})
+			assert.Nil(t, err)
+			assert.Nil(t, db.Close())
+
+			db = openDB(t, dir)
+			banners, err = db.List()
+			assert.Nil(t, err)
+			assert.Len(t, banners, 2)
+		})
+	}
+}
+
+func TestBannerDBCorruptedRecordFailsRecovery(t *testing.T) {
+	dir := t.TempDir()
+
+	db := openDB(t, dir)
+	for _, name := range []string{"first", "second", "third"} {
+		_, err := db.Save(domain.Banner{Name: name})
+		assert.Nil(t, err)
+	}
+	assert.Nil(t, db.Close())
+
+	// flip a bit in the payload of the second record
+	path := filepath.Join(dir, "banners.log")
+	data, err := os.ReadFile(path)
+	assert.Nil(t, err)
+	first := 8 + int(binary.BigEndian.Uint32(data[0:4]))
+	data[first+8+1] ^= 1
+	assert.Nil(t, os.WriteFile(path, data, 0o644))
+
+	_, err = file.NewBannerDB(dir)
+	assert.NotNil(t, err)
+
+	// the records after the corrupted one are kept for the repair
+	after, err := os.ReadFile(path)
+	assert.Nil(t, err)
+	assert.Equal(t, data, after)
+}
+
+func TestBannerDBCompactionFailureKeepsSave(t *testing.T) {
+	dir := t.TempDir()
+	db := openDB(t, dir)
+
+	// the directory in place of the temporary log makes the compaction fail
+	assert.Nil(t, os.Mkdir(filepath.Join(dir, "banners.log.tmp"), 0o755))
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, "banners.log.tmp", "keep"), nil, 0o644))
+
+	id, err := db.Save(domain.Banner{Name: "banner"})
+	assert.Nil(t, err)
+	for i := 0; i < 1100; i++ {
+		_, err = db.Save(domain.Banner{ID: id, Name: "banner"})
+		assert.Nil(t, err)
+	}
+	assert.NotNil(t, db.Compact())
+	assert.Nil(t, db.Close())
+
+	assert.Nil(t, os.RemoveAll(filepath.Join(dir, "banners.log.tmp")))
+	db = openDB(t, dir)
+	b, err := db.FetchForID(id)
+	assert.Nil(t, err)
+	assert.Equal(t, 1101, b.Version)
+}
+
+func TestBannerDBCompact(t *testing.T) {
+	dir := t.TempDir()
+
+	db := openDB(t, dir)
+	id, err := db.Save(domain.Banner{Name: "banner"})
+	assert.Nil(t, err)
+	for i := 0; i < 50; i++ {
+		_, err = db.Save(domain.Banner{ID: id, Name: "banner"})
+		assert.Nil(t, err)
+	}
+	deleted, err := db.Save(domain.Banner{Name: "deleted"})
+	assert.Nil(t, err)
+	assert.Nil(t, db.Delete(deleted))
+
+	before := logSize(t, dir)
+	assert.Nil(t, db.Compact())
+	assert.True(t, logSize(t, dir) < before)
+	assert.Nil(t, db.Close())
+
+	db = openDB(t, dir)
+	banners, err := db.List()
+	assert.Nil(t, err)
+	assert.Len(t, banners, 1)
+	assert.Equal(t, id, banners[0].ID)
+
+	next, err := db.Save(domain.Banner{Name: "next"})
+	assert.Nil(t, err)
+	assert.Equal(t, deleted+1, next)
+}
+
+func TestBannerDBLocksDirectory(t *testing.T) {
+	dir := t.TempDir()
+
+	db := openDB(t, dir)
+	_, err := file.NewBannerDB(dir)
+	assert.NotNil(t, err)
+
+	// the lock is released once the repository is closed
+	assert.Nil(t, db.Close())
+	db = openDB(t, dir)
+	_, err = db.Save(domain.Banner{Name: "banner"})
+	assert.Nil(t, err)
+}
+
+func TestBannerDBNotFound(t *testing.T) {
+	db := openDB(t, t.TempDir())
+
+	_, err := db.FetchForID(1)
+	assert.True(t, errors.Is(err, domain.ErrNotFound))
+
+	err = db.Delete(1)
+	assert.True(t, errors.Is(err, domain.ErrNotFound))
+}
+
+func TestBannerDBRemovesInterruptedCompaction(t *testing.T) {
+	dir := t.TempDir()
+
+	db := openDB(t, dir)
+	_, err := db.Save(domain.Banner{Name: "banner"})
+	assert.Nil(t, err)
+	assert.Nil(t, db.Close())
+
+	err = os.WriteFile(filepath.Join(dir, "banners.log.tmp"), []byte("partial"), 0o644)
+	assert.Nil(t, err)
+
+	db = openDB(t, dir)
+	banners, err := db.List()
+	assert.Nil(t, err)
+	assert.Len(t, banners, 1)
+
+	_, err = os.Stat(filepath.Join(dir, "banners.log.tmp"))
+	assert.True(t, errors.Is(err, os.ErrNotExist))
+}
+
+func openDB(t *testing.T, dir string) *file.BannerDB {
+	t.Helper()
+
+	db, err := file.NewBannerDB(dir)
+	if err != nil {
+		t.Fatalf("failed to open banner repository: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	return db
+}
+
+func logSize(t *testing.T, dir string) int64 {
+	t.Helper()
+
+	fi, err := os.Stat(filepath.Join(dir, "banners.log"))
+	if err != nil {
+		t.Fatalf("failed to stat log: %v", err)
+	}
+
+	return fi.Size()
+}
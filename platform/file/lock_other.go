@@ -0,0 +1,14 @@
+//go:build !unix
+
+package file
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// lockDir only creates the lock file, as the directory
+// is not locked on platforms without flock
+func lockDir(dir, name string) (*os.File, error) {
+	return os.OpenFile(filepath.Join(dir, name), os.O_RDWR|os.O_CREATE, 0o644)
+}
@@ -0,0 +1,194 @@
+package file
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+)
+
+const (
+	// headerSize is the size of the record header,
+	// which holds payload length and its checksum
+	headerSize = 8
+
+	// maxRecordSize guards against allocating huge buffers
+	// when the length in a corrupted header is garbage
+	maxRecordSize = 1 << 24
+)
+
+// logFile represents the write-ahead log, i.e. the
+// append-only file of the checksummed JSON records
+type logFile struct {
+	f *os.File
+
+	// failed is set when the torn record could not be cut off the
+	// log, as the records written after it would be lost on recovery
+	failed error
+}
+
+// openLog opens the log at the path, creating it if needed, and
+// passes the payload of every record to the replay function. The
+// record torn by the crash in the middle of appending it is cut off
+func openLog(path string, replay func(payload []byte) error) (*logFile, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, err
+	}
+
+	var offset int64
+	r := bufio.NewReader(f)
+	for {
+		payload, n, err := readRecord(r)
+		if err == nil {
+			err = replay(payload)
+		}
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			err = checkTornTail(f, offset, err)
+			if err != nil {
+				f.Close()
+				return nil, err
+			}
+			break
+		}
+
+		offset += n
+	}
+
+	l := &logFile{f: f}
+	err = l.truncate(offset)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	return l, nil
+}
+
+// append writes the record to the end of the log and syncs it to disk.
+// The record which fails to be written is cut off the log, so that
+// the records written after it are not lost on recovery
+func (l *logFile) append(v interface{}) error {
+	if l.failed != nil {
+		return fmt.Errorf("log failed: %w", l.failed)
+	}
+
+	data, err := encodeRecord(v)
+	if err != nil {
+		return err
+	}
+
+	offset, err := l.f.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return err
+	}
+
+	_, err = l.f.Write(data)
+	if err == nil {
+		err = l.f.Sync()
+	}
+	if err != nil {
+		if terr := l.truncate(offset); terr != nil {
+			l.failed = terr
+		}
+		return err
+	}
+
+	return nil
+}
+
+// truncate cuts the log at the offset
+func (l *logFile) truncate(offset int64) error {
+	err := l.f.Truncate(offset)
+	if err == nil {
+		_, err = l.f.Seek(offset, io.SeekStart)
+	}
+	if err == nil {
+		err = l.f.Sync()
+	}
+
+	return err
+}
+
+// close closes the log file
+func (l *logFile) close() error {
+	return l.f.Close()
+}
+
+// checkTornTail returns nil if the record at the offset, which failed
+// to be read with the given error, is the last one in the log. Such
+// record is the one torn by the crash in the middle of appending it,
+// and is dropped. The corrupted record followed by other ones is not,
+// as dropping it would drop the records written after it as well
+func checkTornTail(f *os.File, offset int64, readErr error) error {
+	if errors.Is(readErr, io.ErrUnexpectedEOF) {
+		return nil
+	}
+
+	fi, err := f.Stat()
+	if err != nil {
+		return err
+	}
+
+	header := make([]byte, headerSize)
+	_, err = f.ReadAt(header, offset)
+	if err != nil {
+		return err
+	}
+	end := offset + headerSize + int64(binary.BigEndian.Uint32(header[0:4]))
+	if end >= fi.Size() {
+		return nil
+	}
+
+	return fmt.Errorf("%s corrupted at offset %d: %w", f.Name(), offset, readErr)
+}
+
+// encodeRecord encodes the record as 4 byte payload length,
+// 4 byte CRC32 checksum of the payload and JSON payload itself
+func encodeRecord(v interface{}) ([]byte, error) {
+	payload, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	data := make([]byte, headerSize+len(payload))
+	binary.BigEndian.PutUint32(data[0:4], uint32(len(payload)))
+	binary.BigEndian.PutUint32(data[4:8], crc32.ChecksumIEEE(payload))
+	copy(data[headerSize:], payload)
+
+	return data, nil
+}
+
+// readRecord reads the next record from the log and returns
+// its JSON payload together with its size in bytes
+func readRecord(r io.Reader) ([]byte, int64, error) {
+	header := make([]byte, headerSize)
+	_, err := io.ReadFull(r, header)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	size := binary.BigEndian.Uint32(header[0:4])
+	if size > maxRecordSize {
+		return nil, 0, fmt.Errorf("record size %d exceeds limit", size)
+	}
+
+	payload := make([]byte, size)
+	_, err = io.ReadFull(r, payload)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if crc32.ChecksumIEEE(payload) != binary.BigEndian.Uint32(header[4:8]) {
+		return nil, 0, fmt.Errorf("record checksum mismatch")
+	}
+
+	return payload, int64(headerSize + len(payload)), nil
+}
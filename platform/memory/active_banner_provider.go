@@ -0,0 +1,55 @@
+package memory
+
+import (
+	"sync"
+
+	domain "github.com/DzananGanic/banner"
+)
+
+// NewActiveBannerProvider creates new in-memory active banner provider
+func NewActiveBannerProvider() *ActiveBannerProvider {
+	return &ActiveBannerProvider{}
+}
+
+// ActiveBannerProvider represents in-memory active banner
+// provider implementation
+type ActiveBannerProvider struct {
+	mu     sync.RWMutex
+	banner *domain.Banner
+}
+
+// Set stores the copy of the given banner as the active one
+func (a *ActiveBannerProvider) Set(b domain.Banner) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	b = b.Clone()
+	a.banner = &b
+
+	return nil
+}
+
+// Get returns the copy of the active banner, or nil
+// if no banner has been set yet
+func (a *ActiveBannerProvider) Get() (*domain.Banner, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	if a.banner == nil {
+		return nil, nil
+	}
+
+	b := a.banner.Clone()
+
+	return &b, nil
+}
+
+// Clear removes the active banner
+func (a *ActiveBannerProvider) Clear() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.banner = nil
+
+	return nil
+}
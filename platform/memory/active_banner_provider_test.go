@@ -0,0 +1,70 @@
+package memory_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	domain "github.com/DzananGanic/banner"
+	"github.com/DzananGanic/banner/banner"
+	"github.com/DzananGanic/banner/platform/clock"
+	"github.com/DzananGanic/banner/platform/displayer"
+	"github.com/DzananGanic/banner/platform/memory"
+	"github.com/DzananGanic/banner/platform/preview"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestActiveBannerProvider(t *testing.T) {
+	ap := memory.NewActiveBannerProvider()
+
+	b, err := ap.Get()
+	assert.Nil(t, err)
+	assert.Nil(t, b)
+
+	assert.Nil(t, ap.Set(domain.Banner{ID: 1, Name: "active"}))
+
+	b, err = ap.Get()
+	assert.Nil(t, err)
+	assert.Equal(t, &domain.Banner{ID: 1, Name: "active"}, b)
+
+	// modifying the returned banner must not affect the stored one
+	b.Name = "modified"
+	b, err = ap.Get()
+	assert.Nil(t, err)
+	assert.Equal(t, "active", b.Name)
+
+	assert.Nil(t, ap.Clear())
+	b, err = ap.Get()
+	assert.Nil(t, err)
+	assert.Nil(t, b)
+}
+
+func TestServiceEndToEnd(t *testing.T) {
+	db := memory.NewBannerDB()
+	ap := memory.NewActiveBannerProvider()
+	svc := banner.New(
+		db,
+		ap,
+		displayer.NewBasic(
+			db,
+			ap,
+			&preview.Policy{},
+			clock.New(),
+			nil,
+			nil,
+		),
+		clock.New(),
+	)
+
+	resp, err := svc.Create(context.Background(), &banner.CreateReq{
+		Name:                  "memory banner",
+		ScheduledDisplayingAt: time.Now().Add(-time.Hour),
+		ExpiresAt:             time.Now().Add(time.Hour),
+	})
+	assert.Nil(t, err)
+
+	disp, err := svc.Display(context.Background(), &banner.DisplayReq{})
+	assert.Nil(t, err)
+	assert.Equal(t, resp.ID, disp.Banner.ID)
+	assert.Equal(t, "memory banner", disp.Banner.Name)
+}
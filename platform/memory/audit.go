@@ -0,0 +1,48 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	domain "github.com/DzananGanic/banner"
+)
+
+// NewAuditStore creates new in-memory audit store
+func NewAuditStore() *AuditStore {
+	return &AuditStore{
+		entries: make(map[domain.BannerID][]domain.AuditEntry),
+	}
+}
+
+// AuditStore represents in-memory audit store implementation
+type AuditStore struct {
+	mu      sync.RWMutex
+	lastID  int64
+	entries map[domain.BannerID][]domain.AuditEntry
+}
+
+// Record stores the copy of the entry under the next available ID
+func (s *AuditStore) Record(ctx context.Context, e domain.AuditEntry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.lastID++
+	e.ID = s.lastID
+	s.entries[e.BannerID] = append(s.entries[e.BannerID], e.Clone())
+
+	return nil
+}
+
+// ListForBanner returns copies of the entries of the
+// banner in the order they were recorded
+func (s *AuditStore) ListForBanner(ctx context.Context, id domain.BannerID) ([]domain.AuditEntry, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	entries := make([]domain.AuditEntry, 0, len(s.entries[id]))
+	for _, e := range s.entries[id] {
+		entries = append(entries, e.Clone())
+	}
+
+	return entries, nil
+}
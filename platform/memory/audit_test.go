@@ -0,0 +1,37 @@
+package memory_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	domain "github.com/DzananGanic/banner"
+	"github.com/DzananGanic/banner/platform/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuditStoreRecordAndList(t *testing.T) {
+	store := memory.NewAuditStore()
+	ctx := context.Background()
+	at := time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	after := &domain.Banner{ID: 1, Name: "banner", Audience: &domain.Audience{Countries: []string{"BA"}}}
+	assert.Nil(t, store.Record(ctx, domain.AuditEntry{BannerID: 1, Action: domain.AuditCreate, At: at, After: after}))
+	assert.Nil(t, store.Record(ctx, domain.AuditEntry{BannerID: 2, Action: domain.AuditCreate, At: at}))
+	assert.Nil(t, store.Record(ctx, domain.AuditEntry{BannerID: 1, Action: domain.AuditDelete, At: at, Before: after}))
+
+	// modifying the recorded banner must not affect the stored entry
+	after.Audience.Countries[0] = "HR"
+
+	entries, err := store.ListForBanner(ctx, 1)
+	assert.Nil(t, err)
+	assert.Len(t, entries, 2)
+	assert.Equal(t, []int64{1, 3}, []int64{entries[0].ID, entries[1].ID})
+	assert.Equal(t, domain.AuditCreate, entries[0].Action)
+	assert.Equal(t, []string{"BA"}, entries[0].After.Audience.Countries)
+	assert.Equal(t, domain.AuditDelete, entries[1].Action)
+
+	entries, err = store.ListForBanner(ctx, 3)
+	assert.Nil(t, err)
+	assert.Empty(t, entries)
+}
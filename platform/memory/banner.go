@@ -0,0 +1,110 @@
+// Package memory contains in-memory implementations of the
+// domain repositories. They are safe for concurrent use and
+// are meant for development and tests, where running an
+// external store is not desirable
+package memory
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	domain "github.com/DzananGanic/banner"
+)
+
+// NewBannerDB creates new in-memory banner repository
+func NewBannerDB() *BannerDB {
+	return &BannerDB{
+		banners: make(map[domain.BannerID]domain.Banner),
+	}
+}
+
+// BannerDB represents in-memory banner repository implementation
+type BannerDB struct {
+	mu      sync.RWMutex
+	lastID  domain.BannerID
+	banners map[domain.BannerID]domain.Banner
+}
+
+// Save stores the banner. Banners without ID get the next
+// available one, while banners with ID are inserted or
+// replaced under that ID, if their version matches
+func (db *BannerDB) Save(b domain.Banner) (domain.BannerID, error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	if err := b.CheckNormalized(); err != nil {
+		return 0, err
+	}
+
+	stored := db.stored(b.ID)
+	if err := b.CheckVersion(stored); err != nil {
+		return 0, err
+	}
+	b.Version = domain.NextVersion(stored)
+
+	if b.ID == 0 {
+		db.lastID++
+		b.ID = db.lastID
+	} else if b.ID > db.lastID {
+		db.lastID = b.ID
+	}
+
+	db.banners[b.ID] = b.Clone()
+
+	return b.ID, nil
+}
+
+// stored returns the stored banner with the given ID, or nil if there is none
+func (db *BannerDB) stored(id domain.BannerID) *domain.Banner {
+	b, ok := db.banners[id]
+	if !ok {
+		return nil
+	}
+	return &b
+}
+
+// FetchForID returns the copy of the banner with the given ID
+func (db *BannerDB) FetchForID(id domain.BannerID) (*domain.Banner, error) {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	b, ok := db.banners[id]
+	if !ok {
+		return nil, fmt.Errorf("banner %d: %w", id, domain.ErrNotFound)
+	}
+	b = b.Clone()
+
+	return &b, nil
+}
+
+// List returns copies of all stored banners ordered by ID
+func (db *BannerDB) List() ([]domain.Banner, error) {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	banners := make([]domain.Banner, 0, len(db.banners))
+	for _, b := range db.banners {
+		banners = append(banners, b.Clone())
+	}
+
+	sort.Slice(banners, func(i, j int) bool {
+		return banners[i].ID < banners[j].ID
+	})
+
+	return banners, nil
+}
+
+// Delete removes the banner with the given ID
+func (db *BannerDB) Delete(id domain.BannerID) error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	if _, ok := db.banners[id]; !ok {
+		return fmt.Errorf("banner %d: %w", id, domain.ErrNotFound)
+	}
+
+	delete(db.banners, id)
+
+	return nil
+}
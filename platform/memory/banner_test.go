@@ -0,0 +1,197 @@
+package memory_test
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	domain "github.com/DzananGanic/banner"
+	"github.com/DzananGanic/banner/platform/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBannerDBSave(t *testing.T) {
+	cases := []struct {
+		name    string
+		banners []domain.Banner
+		wantIDs []domain.BannerID
+	}{
+		{
+			name: "test allocate sequential ids",
+			banners: []domain.Banner{
+				{Name: "first"},
+				{Name: "second"},
+			},
+			wantIDs: []domain.BannerID{1, 2},
+		},
+		{
+			name: "test replace existing banner",
+			banners: []domain.Banner{
+				{Name: "first"},
+				{ID: 1, Name: "first updated"},
+			},
+			wantIDs: []domain.BannerID{1, 1},
+		},
+		{
+			name: "test allocate after explicit id",
+			banners: []domain.Banner{
+				{ID: 5, Name: "explicit"},
+				{Name: "allocated"},
+			},
+			wantIDs: []domain.BannerID{5, 6},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			db := memory.NewBannerDB()
+
+			for i, b := range c.banners {
+				id, err := db.Save(b)
+				assert.Nil(t, err)
+				assert.Equal(t, c.wantIDs[i], id)
+			}
+		})
+	}
+}
+
+func TestBannerDBFetchForID(t *testing.T) {
+	db := memory.NewBannerDB()
+	createdAt := time.Date(2019, 1, 1, 1, 1, 1, 1, time.UTC)
+
+	id, err := db.Save(domain.Banner{Name: "banner", CreatedAt: createdAt})
+	assert.Nil(t, err)
+
+	b, err := db.FetchForID(id)
+	assert.Nil(t, err)
+	assert.Equal(t, &domain.Banner{ID: id, Name: "banner", CreatedAt: createdAt, Version: 1}, b)
+
+	// modifying the returned banner must not affect the stored one
+	b.Name = "modified"
+	stored, err := db.FetchForID(id)
+	assert.Nil(t, err)
+	assert.Equal(t, "banner", stored.Name)
+
+	_, err = db.FetchForID(id + 1)
+	assert.True(t, errors.Is(err, domain.ErrNotFound))
+}
+
+func TestBannerDBSaveVersion(t *testing.T) {
+	cases := []struct {
+		name        string
+		version     int
+		wantErr     error
+		wantVersion int
+	}{
+		{
+			name:        "successfully save current version",
+			version:     2,
+			wantVersion: 3,
+		},
+		{
+			name:        "successfully save without version",
+			version:     0,
+			wantVersion: 3,
+		},
+		{
+			name:        "failed save stale version",
+			version:     1,
+			wantErr:     &domain.VersionConflictError{ID: 1, Expected: 1, Actual: 2},
+			wantVersion: 2,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			db := memory.NewBannerDB()
+			id, err := db.Save(domain.Banner{Name: "banner"})
+			assert.Nil(t, err)
+			_, err = db.Save(domain.Banner{ID: id, Name: "updated", Version: 1})
+			assert.Nil(t, err)
+
+			_, err = db.Save(domain.Banner{ID: id, Name: "updated again", Version: c.version})
+			assert.Equal(t, c.wantErr, err)
+
+			b, err := db.FetchForID(id)
+			assert.Nil(t, err)
+			assert.Equal(t, c.wantVersion, b.Version)
+		})
+	}
+}
+
+func TestBannerDBSaveKeepsCreatedAt(t *testing.T) {
+	db := memory.NewBannerDB()
+	createdAt := time.Date(2019, 1, 1, 1, 1, 1, 0, time.UTC)
+
+	id, err := db.Save(domain.Banner{Name: "banner", CreatedAt: createdAt})
+	assert.Nil(t, err)
+
+	b, err := db.FetchForID(id)
+	assert.Nil(t, err)
+	assert.Equal(t, createdAt, b.CreatedAt)
+}
+
+func TestBannerDBSaveRequiresUTC(t *testing.T) {
+	db := memory.NewBannerDB()
+
+	_, err := db.Save(domain.Banner{
+		Name:      "banner",
+		ExpiresAt: time.Date(2020, 1, 1, 0, 0, 0, 0, time.FixedZone("CET", 3600)),
+	})
+	assert.NotNil(t, err)
+
+	banners, err := db.List()
+	assert.Nil(t, err)
+	assert.Empty(t, banners)
+}
+
+func TestBannerDBList(t *testing.T) {
+	db := memory.NewBannerDB()
+
+	for _, name := range []string{"first", "second", "third"} {
+		_, err := db.Save(domain.Banner{Name: name})
+		assert.Nil(t, err)
+	}
+
+	banners, err := db.List()
+	assert.Nil(t, err)
+	assert.Len(t, banners, 3)
+	for i, b := range banners {
+		assert.Equal(t, domain.BannerID(i+1), b.ID)
+	}
+}
+
+func TestBannerDBDelete(t *testing.T) {
+	db := memory.NewBannerDB()
+
+	id, err := db.Save(domain.Banner{Name: "banner"})
+	assert.Nil(t, err)
+
+	assert.Nil(t, db.Delete(id))
+
+	_, err = db.FetchForID(id)
+	assert.True(t, errors.Is(err, domain.ErrNotFound))
+
+	err = db.Delete(id)
+	assert.True(t, errors.Is(err, domain.ErrNotFound))
+}
+
+func TestBannerDBConcurrentSave(t *testing.T) {
+	db := memory.NewBannerDB()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := db.Save(domain.Banner{Name: "banner"})
+			assert.Nil(t, err)
+		}()
+	}
+	wg.Wait()
+
+	banners, err := db.List()
+	assert.Nil(t, err)
+	assert.Len(t, banners, 100)
+}
@@ -0,0 +1,103 @@
+// Package preview contains the preview access policy, which
+// allows the listed IP addresses and CIDR blocks to see banners
+// before their display period starts
+package preview
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/netip"
+	"os"
+	"strings"
+	"sync/atomic"
+
+	domain "github.com/DzananGanic/banner"
+)
+
+// NewPolicy creates new preview policy which allows the given
+// entries. Every entry is either IPv4 or IPv6 address, or CIDR block
+func NewPolicy(entries ...string) (*Policy, error) {
+	p := &Policy{}
+
+	err := p.Set(entries)
+	if err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// Policy represents preview access policy backed by the allowlist
+// The allowlist can be replaced at runtime, e.g. when the
+// configuration is reloaded, without blocking the readers
+// Zero value policy allows no addresses
+type Policy struct {
+	prefixes atomic.Pointer[[]netip.Prefix]
+}
+
+// Set replaces the allowlist with the given entries. Blank entries
+// are skipped, and if any of the other entries is not valid
+// the current allowlist is left intact
+func (p *Policy) Set(entries []string) error {
+	prefixes := make([]netip.Prefix, 0, len(entries))
+	for _, e := range entries {
+		if strings.TrimSpace(e) == "" {
+			continue
+		}
+
+		prefix, err := domain.ParseIPPrefix(e)
+		if err != nil {
+			return fmt.Errorf("preview allowlist: %w", err)
+		}
+		prefixes = append(prefixes, prefix)
+	}
+
+	p.prefixes.Store(&prefixes)
+
+	return nil
+}
+
+// Allows checks whether the given IP address is in the allowlist
+func (p *Policy) Allows(ip string) bool {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return false
+	}
+	addr = addr.WithZone("").Unmap()
+
+	prefixes := p.prefixes.Load()
+	if prefixes == nil {
+		return false
+	}
+
+	for _, prefix := range *prefixes {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Config represents preview policy configuration file
+type Config struct {
+	Allow []string `json:"allow"`
+}
+
+// LoadConfig reads the JSON configuration file in the form of
+// {"allow": ["10.0.0.1", "10.1.0.0/16", "fd00::/8"]}
+// and returns the allowlist entries it holds
+func LoadConfig(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var cfg Config
+	err = json.Unmarshal(data, &cfg)
+	if err != nil {
+		return nil, fmt.Errorf("parsing preview config %s: %w", path, err)
+	}
+
+	return cfg.Allow, nil
+}
@@ -0,0 +1,138 @@
+package preview_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/DzananGanic/banner/platform/preview"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPolicyAllows(t *testing.T) {
+	policy, err := preview.NewPolicy(
+		"10.0.0.1",
+		"192.168.10.0/24",
+		"2001:db8::1",
+		"fd00::/8",
+		"::ffff:172.16.0.0/108",
+	)
+	assert.Nil(t, err)
+
+	cases := []struct {
+		name string
+		ip   string
+		want bool
+	}{
+		{
+			name: "test single IPv4 address",
+			ip:   "10.0.0.1",
+			want: true,
+		},
+		{
+			name: "test IPv4 address not in allowlist",
+			ip:   "10.0.0.2",
+			want: false,
+		},
+		{
+			name: "test IPv4 address in CIDR block",
+			ip:   "192.168.10.42",
+			want: true,
+		},
+		{
+			name: "test IPv4 address outside CIDR block",
+			ip:   "192.168.11.1",
+			want: false,
+		},
+		{
+			name: "test IPv4-mapped IPv6 address",
+			ip:   "::ffff:10.0.0.1",
+			want: true,
+		},
+		{
+			name: "test IPv4 address in IPv4-mapped CIDR block",
+			ip:   "172.16.5.5",
+			want: true,
+		},
+		{
+			name: "test single IPv6 address",
+			ip:   "2001:db8::1",
+			want: true,
+		},
+		{
+			name: "test IPv6 address in CIDR block",
+			ip:   "fd12:3456::1",
+			want: true,
+		},
+		{
+			name: "test IPv6 address with zone",
+			ip:   "fd12:3456::1%eth0",
+			want: true,
+		},
+		{
+			name: "test IPv6 address outside CIDR block",
+			ip:   "2001:db8::2",
+			want: false,
+		},
+		{
+			name: "test invalid address",
+			ip:   "not an ip",
+			want: false,
+		},
+		{
+			name: "test empty address",
+			ip:   "",
+			want: false,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			assert.Equal(t, c.want, policy.Allows(c.ip))
+		})
+	}
+}
+
+func TestPolicySet(t *testing.T) {
+	policy, err := preview.NewPolicy("10.0.0.1")
+	assert.Nil(t, err)
+
+	assert.Nil(t, policy.Set([]string{"10.1.0.0/16"}))
+	assert.False(t, policy.Allows("10.0.0.1"))
+	assert.True(t, policy.Allows("10.1.2.3"))
+
+	// invalid entries leave the current allowlist intact
+	assert.NotNil(t, policy.Set([]string{"10.2.0.0/16", "10.3.0.0/99"}))
+	assert.True(t, policy.Allows("10.1.2.3"))
+	assert.False(t, policy.Allows("10.2.0.1"))
+}
+
+func TestPolicyEmpty(t *testing.T) {
+	policy, err := preview.NewPolicy("", " ")
+	assert.Nil(t, err)
+	assert.False(t, policy.Allows("10.0.0.1"))
+
+	assert.False(t, (&preview.Policy{}).Allows("10.0.0.1"))
+}
+
+func TestNewPolicyInvalidEntry(t *testing.T) {
+	_, err := preview.NewPolicy("10.0.0.256")
+	assert.NotNil(t, err)
+}
+
+func TestLoadConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "preview.json")
+
+	err := os.WriteFile(path, []byte(`{"allow": ["10.0.0.1", "fd00::/8"]}`), 0o644)
+	assert.Nil(t, err)
+
+	entries, err := preview.LoadConfig(path)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"10.0.0.1", "fd00::/8"}, entries)
+
+	err = os.WriteFile(path, []byte(`{"allow": `), 0o644)
+	assert.Nil(t, err)
+
+	_, err = preview.LoadConfig(path)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,99 @@
+// Package redis contains Redis implementation of the active
+// banner provider, which lets multiple replicas of the service
+// share the same cached active banner, and Redis stream
+// publisher of the banner events
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	domain "github.com/DzananGanic/banner"
+	goredis "github.com/redis/go-redis/v9"
+)
+
+// DefaultKey is the key under which the active banner is stored
+// when no other key is provided
+const DefaultKey = "banner:active"
+
+// NewActiveBannerProvider creates new Redis active banner provider
+// which stores the active banner under the given key
+func NewActiveBannerProvider(client goredis.Cmdable, key string) *ActiveBannerProvider {
+	if key == "" {
+		key = DefaultKey
+	}
+
+	return &ActiveBannerProvider{
+		client: client,
+		key:    key,
+	}
+}
+
+// ActiveBannerProvider represents Redis active banner provider implementation
+// The banner is stored as JSON and the key expires together with the banner,
+// so once the banner expires every replica sees the cache miss and
+// selects the next banner instead of serving a stale one
+type ActiveBannerProvider struct {
+	client goredis.Cmdable
+	key    string
+}
+
+// Set stores the given banner as the active one, with the
+// key expiring at the banner expiration time
+func (a *ActiveBannerProvider) Set(b domain.Banner) error {
+	return a.SetContext(context.Background(), b)
+}
+
+// SetContext is Set which passes the context to the Redis client
+func (a *ActiveBannerProvider) SetContext(ctx context.Context, b domain.Banner) error {
+	// banner without expiration time is always considered expired,
+	// so there is nothing worth caching
+	if b.ExpiresAt.IsZero() {
+		return a.client.Del(ctx, a.key).Err()
+	}
+
+	data, err := json.Marshal(b)
+	if err != nil {
+		return err
+	}
+
+	return a.client.SetArgs(ctx, a.key, data, goredis.SetArgs{
+		ExpireAt: b.ExpiresAt,
+	}).Err()
+}
+
+// Get returns the active banner, or nil if there is no active
+// banner or it has already expired
+func (a *ActiveBannerProvider) Get() (*domain.Banner, error) {
+	return a.GetContext(context.Background())
+}
+
+// GetContext is Get which passes the context to the Redis client
+func (a *ActiveBannerProvider) GetContext(ctx context.Context) (*domain.Banner, error) {
+	data, err := a.client.Get(ctx, a.key).Bytes()
+	if errors.Is(err, goredis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var b domain.Banner
+	err = json.Unmarshal(data, &b)
+	if err != nil {
+		return nil, err
+	}
+
+	return &b, nil
+}
+
+// Clear removes the active banner
+func (a *ActiveBannerProvider) Clear() error {
+	return a.ClearContext(context.Background())
+}
+
+// ClearContext is Clear which passes the context to the Redis client
+func (a *ActiveBannerProvider) ClearContext(ctx context.Context) error {
+	return a.client.Del(ctx, a.key).Err()
+}
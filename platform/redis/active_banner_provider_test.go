@@ -0,0 +1,106 @@
+package redis_test
+
+import (
+	"testing"
+	"time"
+
+	domain "github.com/DzananGanic/banner"
+	"github.com/DzananGanic/banner/platform/redis"
+	"github.com/alicebob/miniredis/v2"
+	goredis "github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestActiveBannerProvider(t *testing.T) {
+	now := time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		name       string
+		banner     *domain.Banner
+		elapsed    time.Duration
+		wantBanner *domain.Banner
+	}{
+		{
+			name:       "test no active banner",
+			wantBanner: nil,
+		},
+		{
+			name: "test return active banner",
+			banner: &domain.Banner{
+				ID:        1,
+				Name:      "active",
+				ExpiresAt: now.Add(time.Hour),
+			},
+			elapsed: time.Minute,
+			wantBanner: &domain.Banner{
+				ID:        1,
+				Name:      "active",
+				ExpiresAt: now.Add(time.Hour),
+			},
+		},
+		{
+			name: "test key expires together with banner",
+			banner: &domain.Banner{
+				ID:        1,
+				Name:      "active",
+				ExpiresAt: now.Add(time.Hour),
+			},
+			elapsed:    time.Hour + time.Second,
+			wantBanner: nil,
+		},
+		{
+			name: "test banner without expiration is not cached",
+			banner: &domain.Banner{
+				ID:   1,
+				Name: "active",
+			},
+			wantBanner: nil,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			mr := miniredis.RunT(t)
+			mr.SetTime(now)
+
+			ap := redis.NewActiveBannerProvider(
+				goredis.NewClient(&goredis.Options{Addr: mr.Addr()}),
+				"",
+			)
+
+			if c.banner != nil {
+				assert.Nil(t, ap.Set(*c.banner))
+			}
+			mr.FastForward(c.elapsed)
+
+			b, err := ap.Get()
+			assert.Nil(t, err)
+			if c.wantBanner == nil {
+				assert.Nil(t, b)
+				return
+			}
+			assert.Equal(t, c.wantBanner.ID, b.ID)
+			assert.Equal(t, c.wantBanner.Name, b.Name)
+			assert.True(t, c.wantBanner.ExpiresAt.Equal(b.ExpiresAt))
+		})
+	}
+}
+
+func TestActiveBannerProviderSharedBetweenReplicas(t *testing.T) {
+	mr := miniredis.RunT(t)
+
+	first := redis.NewActiveBannerProvider(goredis.NewClient(&goredis.Options{Addr: mr.Addr()}), "")
+	second := redis.NewActiveBannerProvider(goredis.NewClient(&goredis.Options{Addr: mr.Addr()}), "")
+
+	err := first.Set(domain.Banner{ID: 7, ExpiresAt: time.Now().Add(time.Hour)})
+	assert.Nil(t, err)
+
+	b, err := second.Get()
+	assert.Nil(t, err)
+	assert.Equal(t, domain.BannerID(7), b.ID)
+
+	assert.Nil(t, second.Clear())
+	b, err = first.Get()
+	assert.Nil(t, err)
+	assert.Nil(t, b)
+}
@@ -0,0 +1,55 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+
+	domain "github.com/DzananGanic/banner"
+	goredis "github.com/redis/go-redis/v9"
+)
+
+// DefaultStream is the stream the events are published
+// to when no other stream is provided
+const DefaultStream = "banner:events"
+
+// NewEventPublisher creates new Redis event publisher
+// which appends the events to the given stream
+func NewEventPublisher(client goredis.Cmdable, stream string) *EventPublisher {
+	if stream == "" {
+		stream = DefaultStream
+	}
+
+	return &EventPublisher{
+		client: client,
+		stream: stream,
+	}
+}
+
+// EventPublisher represents Redis stream event publisher implementation
+// Every event is appended as the stream entry with id, type and banner_id
+// fields, and the JSON encoded event in the event field. The consumers
+// read the stream with consumer groups, and recognise the events
+// delivered more than once by their id
+type EventPublisher struct {
+	client goredis.Cmdable
+	stream string
+}
+
+// Publish appends the event to the stream
+func (p *EventPublisher) Publish(ctx context.Context, e domain.Event) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	return p.client.XAdd(ctx, &goredis.XAddArgs{
+		Stream: p.stream,
+		Values: []interface{}{
+			"id", strconv.FormatInt(e.ID, 10),
+			"type", string(e.Type),
+			"banner_id", strconv.FormatInt(int64(e.BannerID), 10),
+			"event", data,
+		},
+	}).Err()
+}
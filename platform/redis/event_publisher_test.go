@@ -0,0 +1,42 @@
+package redis_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	domain "github.com/DzananGanic/banner"
+	"github.com/DzananGanic/banner/platform/redis"
+	"github.com/alicebob/miniredis/v2"
+	goredis "github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEventPublisher(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := goredis.NewClient(&goredis.Options{Addr: mr.Addr()})
+	pub := redis.NewEventPublisher(client, "")
+	ctx := context.Background()
+
+	e := domain.Event{
+		ID:       3,
+		Type:     domain.EventBannerUpdated,
+		BannerID: 7,
+		At:       time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC),
+		Actor:    "alice",
+		Banner:   &domain.Banner{ID: 7, Name: "banner", Version: 2},
+	}
+	assert.Nil(t, pub.Publish(ctx, e))
+
+	entries, err := client.XRange(ctx, redis.DefaultStream, "-", "+").Result()
+	assert.Nil(t, err)
+	assert.Len(t, entries, 1)
+	assert.Equal(t, "3", entries[0].Values["id"])
+	assert.Equal(t, "banner.updated", entries[0].Values["type"])
+	assert.Equal(t, "7", entries[0].Values["banner_id"])
+
+	var got domain.Event
+	assert.Nil(t, json.Unmarshal([]byte(entries[0].Values["event"].(string)), &got))
+	assert.Equal(t, e, got)
+}
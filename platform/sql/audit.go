@@ -0,0 +1,117 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	domain "github.com/DzananGanic/banner"
+)
+
+// NewAuditStore creates new SQL audit store. The schema
+// is expected to be up to date, see Migrate
+func NewAuditStore(db *sql.DB) *AuditStore {
+	return &AuditStore{db: db}
+}
+
+// AuditStore represents database/sql audit store implementation
+type AuditStore struct {
+	db *sql.DB
+}
+
+// Record inserts the entry, the ID is assigned by the database
+func (s *AuditStore) Record(ctx context.Context, e domain.AuditEntry) error {
+	before, err := marshalSnapshot(e.Before)
+	if err != nil {
+		return err
+	}
+	after, err := marshalSnapshot(e.After)
+	if err != nil {
+		return err
+	}
+
+	_, err = conn(ctx, s.db).ExecContext(
+		ctx,
+		`INSERT INTO audit_log (banner_id, action, actor, at, before, after)
+		VALUES ($1, $2, $3, $4, $5, $6)`,
+		e.BannerID,
+		e.Action,
+		e.Actor,
+		e.At.UTC(),
+		before,
+		after,
+	)
+
+	return err
+}
+
+// ListForBanner returns the entries of the banner ordered by ID
+func (s *AuditStore) ListForBanner(ctx context.Context, id domain.BannerID) ([]domain.AuditEntry, error) {
+	rows, err := conn(ctx, s.db).QueryContext(
+		ctx,
+		`SELECT id, banner_id, action, actor, at, before, after
+		FROM audit_log
+		WHERE banner_id = $1
+		ORDER BY id`,
+		id,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []domain.AuditEntry{}
+	for rows.Next() {
+		var (
+			e             domain.AuditEntry
+			before, after sql.NullString
+		)
+		err := rows.Scan(&e.ID, &e.BannerID, &e.Action, &e.Actor, &e.At, &before, &after)
+		if err != nil {
+			return nil, err
+		}
+
+		// drivers return the instants in the session time zone
+		e.At = e.At.UTC()
+
+		if e.Before, err = unmarshalSnapshot(before); err != nil {
+			return nil, fmt.Errorf("audit entry %d before: %w", e.ID, err)
+		}
+		if e.After, err = unmarshalSnapshot(after); err != nil {
+			return nil, fmt.Errorf("audit entry %d after: %w", e.ID, err)
+		}
+
+		entries = append(entries, e)
+	}
+
+	return entries, rows.Err()
+}
+
+// marshalSnapshot encodes the banner snapshot as JSON,
+// leaving the missing one NULL
+func marshalSnapshot(b *domain.Banner) (sql.NullString, error) {
+	if b == nil {
+		return sql.NullString{}, nil
+	}
+
+	data, err := json.Marshal(b)
+	if err != nil {
+		return sql.NullString{}, err
+	}
+
+	return sql.NullString{String: string(data), Valid: true}, nil
+}
+
+func unmarshalSnapshot(s sql.NullString) (*domain.Banner, error) {
+	if !s.Valid {
+		return nil, nil
+	}
+
+	var b domain.Banner
+	if err := json.Unmarshal([]byte(s.String), &b); err != nil {
+		return nil, err
+	}
+
+	return &b, nil
+}
@@ -0,0 +1,46 @@
+package sql_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	domain "github.com/DzananGanic/banner"
+	bannersql "github.com/DzananGanic/banner/platform/sql"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuditStoreRecordAndList(t *testing.T) {
+	store := bannersql.NewAuditStore(openMigrated(t))
+	ctx := context.Background()
+	before := &domain.Banner{
+		ID:        1,
+		Name:      "before",
+		CreatedAt: time.Date(2019, 1, 1, 1, 1, 1, 0, time.UTC),
+		ExpiresAt: time.Date(2020, 1, 1, 1, 1, 1, 0, time.UTC),
+		Audience:  &domain.Audience{Countries: []string{"BA"}},
+		Version:   1,
+	}
+	after := before.Clone()
+	after.Name = "after"
+	after.Version = 2
+
+	entries := []domain.AuditEntry{
+		{BannerID: 1, Action: domain.AuditUpdate, Actor: "alice", At: time.Date(2019, 2, 1, 0, 0, 0, 0, time.UTC), Before: before, After: &after},
+		{BannerID: 2, Action: domain.AuditCreate, At: time.Date(2019, 2, 2, 0, 0, 0, 0, time.UTC), After: &domain.Banner{ID: 2}},
+		{BannerID: 1, Action: domain.AuditDelete, Actor: "bob", At: time.Date(2019, 2, 3, 0, 0, 0, 0, time.UTC), Before: &after},
+	}
+	for _, e := range entries {
+		assert.Nil(t, store.Record(ctx, e))
+	}
+
+	got, err := store.ListForBanner(ctx, 1)
+	assert.Nil(t, err)
+	entries[0].ID = 1
+	entries[2].ID = 3
+	assert.Equal(t, []domain.AuditEntry{entries[0], entries[2]}, got)
+
+	got, err = store.ListForBanner(ctx, 3)
+	assert.Nil(t, err)
+	assert.Empty(t, got)
+}
@@ -0,0 +1,323 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	domain "github.com/DzananGanic/banner"
+)
+
+// NewBannerDB creates new SQL banner repository. The schema
+// is expected to be up to date, see Migrate
+func NewBannerDB(db *sql.DB) *BannerDB {
+	return &BannerDB{db: db}
+}
+
+// BannerDB represents database/sql banner repository implementation
+type BannerDB struct {
+	db *sql.DB
+}
+
+// Save inserts the banner if it has no ID, or updates the banner
+// with the given ID otherwise. The banner with version is updated
+// only if the version matches. Unlike the other repositories, the
+// banner with ID which is not stored is not inserted, as inserting
+// the explicit ID does not advance Postgres ID sequence, which
+// would make the next insert fail on the duplicate key
+func (bdb *BannerDB) Save(b domain.Banner) (domain.BannerID, error) {
+	return bdb.SaveContext(context.Background(), b)
+}
+
+// SaveContext is Save which passes the context to the database
+func (bdb *BannerDB) SaveContext(ctx context.Context, b domain.Banner) (domain.BannerID, error) {
+	if err := b.CheckNormalized(); err != nil {
+		return 0, err
+	}
+
+	audience, err := marshalAudience(b.Audience)
+	if err != nil {
+		return 0, err
+	}
+	content, err := marshalContent(b.Content)
+	if err != nil {
+		return 0, err
+	}
+
+	if b.ID == 0 {
+		if err := b.CheckVersion(nil); err != nil {
+			return 0, err
+		}
+
+		var id domain.BannerID
+		err = conn(ctx, bdb.db).QueryRowContext(
+			ctx,
+			`INSERT INTO banners (name, created_at, scheduled_displaying_at, expires_at, weight, priority, audience, time_zone, recurrence, content)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+			RETURNING id`,
+			b.Name,
+			b.CreatedAt,
+			b.ScheduledDisplayingAt,
+			b.ExpiresAt,
+			b.Weight,
+			b.Priority,
+			audience,
+			b.TimeZone,
+			marshalRecurrence(b.Recurrence),
+			content,
+		).Scan(&id)
+		if err != nil {
+			return 0, err
+		}
+
+		return id, nil
+	}
+
+	if err := bdb.update(ctx, b, audience, content); err != nil {
+		return 0, err
+	}
+
+	return b.ID, nil
+}
+
+// update updates the stored banner, and returns not found error if
+// there is none. The banner with version is updated only if its stored
+// version matches, and VersionConflictError is returned otherwise.
+// created_at is deliberately left out of the update so that the
+// original creation time is preserved
+func (bdb *BannerDB) update(ctx context.Context, b domain.Banner, audience, content sql.NullString) error {
+	query := `UPDATE banners SET
+			name = $1,
+			scheduled_displaying_at = $2,
+			expires_at = $3,
+			weight = $4,
+			priority = $5,
+			audience = $6,
+			time_zone = $7,
+			recurrence = $8,
+			content = $9,
+			version = version + 1
+		WHERE id = $10`
+	args := []interface{}{
+		b.Name,
+		b.ScheduledDisplayingAt,
+		b.ExpiresAt,
+		b.Weight,
+		b.Priority,
+		audience,
+		b.TimeZone,
+		marshalRecurrence(b.Recurrence),
+		content,
+		b.ID,
+	}
+	if b.Version != 0 {
+		query += ` AND version = $11`
+		args = append(args, b.Version)
+	}
+
+	res, err := conn(ctx, bdb.db).ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n > 0 {
+		return nil
+	}
+	if b.Version == 0 {
+		return fmt.Errorf("banner %d: %w", b.ID, domain.ErrNotFound)
+	}
+
+	// the actual version is only looked up for the error,
+	// the banner is not stored when there is none
+	var actual int
+	err = conn(ctx, bdb.db).QueryRowContext(ctx, `SELECT version FROM banners WHERE id = $1`, b.ID).Scan(&actual)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+
+	return &domain.VersionConflictError{ID: b.ID, Expected: b.Version, Actual: actual}
+}
+
+// FetchForID returns the banner with the given ID
+func (bdb *BannerDB) FetchForID(id domain.BannerID) (*domain.Banner, error) {
+	return bdb.FetchForIDContext(context.Background(), id)
+}
+
+// FetchForIDContext is FetchForID which passes the context to the database
+func (bdb *BannerDB) FetchForIDContext(ctx context.Context, id domain.BannerID) (*domain.Banner, error) {
+	row := conn(ctx, bdb.db).QueryRowContext(
+		ctx,
+		`SELECT id, name, created_at, scheduled_displaying_at, expires_at, weight, priority, audience, time_zone, recurrence, content, version
+		FROM banners
+		WHERE id = $1`,
+		id,
+	)
+
+	b, err := scanBanner(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("banner %d: %w", id, domain.ErrNotFound)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// List returns all banners ordered by ID
+func (bdb *BannerDB) List() ([]domain.Banner, error) {
+	return bdb.ListContext(context.Background())
+}
+
+// ListContext is List which passes the context to the database
+func (bdb *BannerDB) ListContext(ctx context.Context) ([]domain.Banner, error) {
+	rows, err := conn(ctx, bdb.db).QueryContext(
+		ctx,
+		`SELECT id, name, created_at, scheduled_displaying_at, expires_at, weight, priority, audience, time_zone, recurrence, content, version
+		FROM banners
+		ORDER BY id`,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var banners []domain.Banner
+	for rows.Next() {
+		b, err := scanBanner(rows)
+		if err != nil {
+			return nil, err
+		}
+		banners = append(banners, *b)
+	}
+
+	return banners, rows.Err()
+}
+
+// Delete removes the banner with the given ID
+func (bdb *BannerDB) Delete(id domain.BannerID) error {
+	return bdb.DeleteContext(context.Background(), id)
+}
+
+// DeleteContext is Delete which passes the context to the database
+func (bdb *BannerDB) DeleteContext(ctx context.Context, id domain.BannerID) error {
+	res, err := conn(ctx, bdb.db).ExecContext(ctx, `DELETE FROM banners WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return fmt.Errorf("banner %d: %w", id, domain.ErrNotFound)
+	}
+
+	return nil
+}
+
+// scanner is implemented by both *sql.Row and *sql.Rows
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanBanner(s scanner) (*domain.Banner, error) {
+	var (
+		b          domain.Banner
+		audience   sql.NullString
+		recurrence sql.NullString
+		content    sql.NullString
+	)
+	err := s.Scan(
+		&b.ID,
+		&b.Name,
+		&b.CreatedAt,
+		&b.ScheduledDisplayingAt,
+		&b.ExpiresAt,
+		&b.Weight,
+		&b.Priority,
+		&audience,
+		&b.TimeZone,
+		&recurrence,
+		&content,
+		&b.Version,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// drivers return the instants in the session time zone
+	b.CreatedAt = b.CreatedAt.UTC()
+	b.ScheduledDisplayingAt = b.ScheduledDisplayingAt.UTC()
+	b.ExpiresAt = b.ExpiresAt.UTC()
+
+	if audience.Valid {
+		err = json.Unmarshal([]byte(audience.String), &b.Audience)
+		if err != nil {
+			return nil, fmt.Errorf("banner %d audience: %w", b.ID, err)
+		}
+	}
+
+	if recurrence.Valid {
+		b.Recurrence, err = domain.ParseRecurrence(recurrence.String)
+		if err != nil {
+			return nil, fmt.Errorf("banner %d recurrence: %w", b.ID, err)
+		}
+	}
+
+	if content.Valid {
+		err = json.Unmarshal([]byte(content.String), &b.Content)
+		if err != nil {
+			return nil, fmt.Errorf("banner %d content: %w", b.ID, err)
+		}
+	}
+
+	return &b, nil
+}
+
+// marshalRecurrence encodes the recurrence in RRULE-like
+// format, leaving the banner without one NULL
+func marshalRecurrence(r *domain.Recurrence) sql.NullString {
+	if r == nil {
+		return sql.NullString{}
+	}
+
+	return sql.NullString{String: r.String(), Valid: true}
+}
+
+// marshalAudience encodes the audience as JSON,
+// leaving the banner without one NULL
+func marshalAudience(a *domain.Audience) (sql.NullString, error) {
+	if a.IsEmpty() {
+		return sql.NullString{}, nil
+	}
+
+	data, err := json.Marshal(a)
+	if err != nil {
+		return sql.NullString{}, err
+	}
+
+	return sql.NullString{String: string(data), Valid: true}, nil
+}
+
+// marshalContent encodes the content as JSON,
+// leaving the banner without one NULL
+func marshalContent(c *domain.Content) (sql.NullString, error) {
+	if c.IsEmpty() {
+		return sql.NullString{}, nil
+	}
+
+	data, err := json.Marshal(c)
+	if err != nil {
+		return sql.NullString{}, err
+	}
+
+	return sql.NullString{String: string(data), Valid: true}, nil
+}
@@ -0,0 +1,176 @@
+package sql_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	domain "github.com/DzananGanic/banner"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBannerDBSaveAndFetch(t *testing.T) {
+	bdb := newBannerDB(t)
+
+	b := domain.Banner{
+		Name:                  "sql banner",
+		CreatedAt:             time.Date(2019, 1, 1, 1, 1, 1, 0, time.UTC),
+		ScheduledDisplayingAt: time.Date(2019, 2, 1, 1, 1, 1, 0, time.UTC),
+		ExpiresAt:             time.Date(2020, 1, 1, 1, 1, 1, 0, time.UTC),
+		Weight:                2,
+		Priority:              5,
+		Audience: &domain.Audience{
+			Locales:    []string{"bs"},
+			Attributes: map[string]string{"plan": "premium"},
+		},
+		TimeZone: "Europe/Sarajevo",
+		Recurrence: &domain.Recurrence{
+			Frequency: domain.Weekly,
+			Weekdays:  []time.Weekday{time.Monday, time.Friday},
+			StartHour: 11,
+			Duration:  3 * time.Hour,
+			Except:    []domain.Date{{Year: 2019, Month: time.June, Day: 10}},
+		},
+		Content: &domain.Content{
+			FallbackLocale: "en",
+			Variants: map[string]domain.ContentVariant{
+				"en": {Title: "Lunch special", ImageURL: "https://example.com/lunch.png", AltText: "Lunch"},
+				"bs": {Title: "Ručak"},
+			},
+		},
+	}
+
+	id, err := bdb.Save(b)
+	assert.Nil(t, err)
+	assert.Equal(t, domain.BannerID(1), id)
+
+	got, err := bdb.FetchForID(id)
+	assert.Nil(t, err)
+	b.ID = id
+	b.Version = 1
+	assert.Equal(t, &b, got)
+}
+
+func TestBannerDBSaveUpdatesExisting(t *testing.T) {
+	bdb := newBannerDB(t)
+	createdAt := time.Date(2019, 1, 1, 1, 1, 1, 0, time.UTC)
+
+	id, err := bdb.Save(domain.Banner{Name: "before", CreatedAt: createdAt})
+	assert.Nil(t, err)
+
+	b, err := bdb.FetchForID(id)
+	assert.Nil(t, err)
+
+	b.Name = "after"
+	b.CreatedAt = time.Time{}
+	b.ExpiresAt = time.Date(2021, 1, 1, 1, 1, 1, 0, time.UTC)
+
+	updatedID, err := bdb.Save(*b)
+	assert.Nil(t, err)
+	assert.Equal(t, id, updatedID)
+
+	got, err := bdb.FetchForID(id)
+	assert.Nil(t, err)
+	assert.Equal(t, "after", got.Name)
+	assert.Equal(t, b.ExpiresAt, got.ExpiresAt)
+	assert.Equal(t, createdAt, got.CreatedAt)
+	assert.Equal(t, 2, got.Version)
+
+	banners, err := bdb.List()
+	assert.Nil(t, err)
+	assert.Len(t, banners, 1)
+}
+
+func TestBannerDBSaveVersion(t *testing.T) {
+	bdb := newBannerDB(t)
+
+	id, err := bdb.Save(domain.Banner{Name: "banner"})
+	assert.Nil(t, err)
+
+	_, err = bdb.Save(domain.Banner{ID: id, Name: "updated", Version: 1})
+	assert.Nil(t, err)
+
+	_, err = bdb.Save(domain.Banner{ID: id, Name: "stale", Version: 1})
+	assert.Equal(t, &domain.VersionConflictError{ID: id, Expected: 1, Actual: 2}, err)
+
+	_, err = bdb.Save(domain.Banner{ID: id + 1, Name: "deleted", Version: 1})
+	assert.Equal(t, &domain.VersionConflictError{ID: id + 1, Expected: 1}, err)
+
+	// the banner without version is saved unconditionally
+	_, err = bdb.Save(domain.Banner{ID: id, Name: "forced"})
+	assert.Nil(t, err)
+
+	got, err := bdb.FetchForID(id)
+	assert.Nil(t, err)
+	assert.Equal(t, "forced", got.Name)
+	assert.Equal(t, 3, got.Version)
+}
+
+func TestBannerDBSaveMissingID(t *testing.T) {
+	bdb := newBannerDB(t)
+
+	// the explicit ID is not inserted, so the IDs
+	// assigned by the database never collide with it
+	_, err := bdb.Save(domain.Banner{ID: 1, Name: "explicit"})
+	assert.True(t, errors.Is(err, domain.ErrNotFound))
+
+	id, err := bdb.Save(domain.Banner{Name: "banner"})
+	assert.Nil(t, err)
+	assert.Equal(t, domain.BannerID(1), id)
+}
+
+func TestBannerDBFetchForIDNotFound(t *testing.T) {
+	bdb := newBannerDB(t)
+
+	_, err := bdb.FetchForID(42)
+	assert.True(t, errors.Is(err, domain.ErrNotFound))
+}
+
+func TestBannerDBList(t *testing.T) {
+	bdb := newBannerDB(t)
+
+	banners, err := bdb.List()
+	assert.Nil(t, err)
+	assert.Empty(t, banners)
+
+	for _, name := range []string{"first", "second"} {
+		_, err := bdb.Save(domain.Banner{Name: name})
+		assert.Nil(t, err)
+	}
+
+	banners, err = bdb.List()
+	assert.Nil(t, err)
+	assert.Len(t, banners, 2)
+	assert.Equal(t, "first", banners[0].Name)
+	assert.Equal(t, "second", banners[1].Name)
+}
+
+func TestBannerDBDelete(t *testing.T) {
+	bdb := newBannerDB(t)
+
+	id, err := bdb.Save(domain.Banner{Name: "banner"})
+	assert.Nil(t, err)
+
+	assert.Nil(t, bdb.Delete(id))
+
+	_, err = bdb.FetchForID(id)
+	assert.True(t, errors.Is(err, domain.ErrNotFound))
+
+	err = bdb.Delete(id)
+	assert.True(t, errors.Is(err, domain.ErrNotFound))
+}
+
+func TestBannerDBContextCanceled(t *testing.T) {
+	bdb := newBannerDB(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := bdb.SaveContext(ctx, domain.Banner{Name: "canceled"})
+	assert.True(t, errors.Is(err, context.Canceled))
+
+	banners, err := bdb.List()
+	assert.Nil(t, err)
+	assert.Empty(t, banners)
+}
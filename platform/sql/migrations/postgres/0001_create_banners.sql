@@ -0,0 +1,9 @@
+CREATE TABLE banners (
+	id BIGSERIAL PRIMARY KEY,
+	name TEXT NOT NULL,
+	created_at TIMESTAMPTZ NOT NULL,
+	scheduled_displaying_at TIMESTAMPTZ NOT NULL,
+	expires_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX banners_expires_at_idx ON banners (expires_at);
@@ -0,0 +1 @@
+ALTER TABLE banners ADD COLUMN weight INTEGER NOT NULL DEFAULT 0;
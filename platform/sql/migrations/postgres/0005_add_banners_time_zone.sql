@@ -0,0 +1 @@
+ALTER TABLE banners ADD COLUMN time_zone TEXT NOT NULL DEFAULT '';
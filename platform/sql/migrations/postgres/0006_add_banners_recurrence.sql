@@ -0,0 +1,3 @@
+-- recurrence holds the RRULE-like recurring display windows,
+-- it is NULL for banners displayed throughout their display period
+ALTER TABLE banners ADD COLUMN recurrence TEXT;
@@ -0,0 +1,9 @@
+CREATE TABLE banners (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	name TEXT NOT NULL,
+	created_at TIMESTAMP NOT NULL,
+	scheduled_displaying_at TIMESTAMP NOT NULL,
+	expires_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX banners_expires_at_idx ON banners (expires_at);
@@ -0,0 +1,3 @@
+-- audience holds the JSON encoded targeting criteria,
+-- it is NULL for banners displayed to everyone
+ALTER TABLE banners ADD COLUMN audience TEXT;
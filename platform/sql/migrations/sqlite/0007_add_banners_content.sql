@@ -0,0 +1,3 @@
+-- content holds the JSON encoded content variants by locale,
+-- it is NULL for banners without content
+ALTER TABLE banners ADD COLUMN content TEXT;
@@ -0,0 +1,2 @@
+-- version is incremented on every save, existing banners start at 1
+ALTER TABLE banners ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
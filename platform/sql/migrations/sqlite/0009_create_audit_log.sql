@@ -0,0 +1,13 @@
+-- audit_log records every change made to banners, before and
+-- after hold the JSON encoded banner snapshots around the change
+CREATE TABLE audit_log (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	banner_id INTEGER NOT NULL,
+	action TEXT NOT NULL,
+	actor TEXT NOT NULL DEFAULT '',
+	at TIMESTAMP NOT NULL,
+	before TEXT,
+	after TEXT
+);
+
+CREATE INDEX audit_log_banner_id_idx ON audit_log (banner_id);
@@ -0,0 +1,13 @@
+-- outbox holds the banner events until the relay publishes them,
+-- banner holds the JSON encoded banner snapshot of the event
+CREATE TABLE outbox (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	type TEXT NOT NULL,
+	banner_id INTEGER NOT NULL,
+	actor TEXT NOT NULL DEFAULT '',
+	at TIMESTAMP NOT NULL,
+	banner TEXT,
+	published_at TIMESTAMP
+);
+
+CREATE INDEX outbox_unpublished_idx ON outbox (id) WHERE published_at IS NULL;
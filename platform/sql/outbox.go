@@ -0,0 +1,100 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	domain "github.com/DzananGanic/banner"
+)
+
+// NewOutbox creates new SQL outbox. The schema
+// is expected to be up to date, see Migrate
+func NewOutbox(db *sql.DB) *Outbox {
+	return &Outbox{db: db}
+}
+
+// Outbox represents database/sql event outbox implementation. The
+// events emitted within the Transactor transaction are stored only
+// if the transaction commits, together with the banner change
+// they describe, and are kept until Relay publishes them
+type Outbox struct {
+	db *sql.DB
+}
+
+// Emit inserts the event, the ID is assigned by the database
+func (o *Outbox) Emit(ctx context.Context, e domain.Event) error {
+	b, err := marshalSnapshot(e.Banner)
+	if err != nil {
+		return err
+	}
+
+	_, err = conn(ctx, o.db).ExecContext(
+		ctx,
+		`INSERT INTO outbox (type, banner_id, actor, at, banner)
+		VALUES ($1, $2, $3, $4, $5)`,
+		e.Type,
+		e.BannerID,
+		e.Actor,
+		e.At.UTC(),
+		b,
+	)
+
+	return err
+}
+
+// Pending returns at most limit events which were not
+// published yet, in the order they were emitted
+func (o *Outbox) Pending(ctx context.Context, limit int) ([]domain.Event, error) {
+	rows, err := conn(ctx, o.db).QueryContext(
+		ctx,
+		`SELECT id, type, banner_id, actor, at, banner
+		FROM outbox
+		WHERE published_at IS NULL
+		ORDER BY id
+		LIMIT $1`,
+		limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := []domain.Event{}
+	for rows.Next() {
+		var (
+			e domain.Event
+			b sql.NullString
+		)
+		err := rows.Scan(&e.ID, &e.Type, &e.BannerID, &e.Actor, &e.At, &b)
+		if err != nil {
+			return nil, err
+		}
+
+		// drivers return the instants in the session time zone
+		e.At = e.At.UTC()
+
+		if e.Banner, err = unmarshalSnapshot(b); err != nil {
+			return nil, fmt.Errorf("outbox event %d banner: %w", e.ID, err)
+		}
+
+		events = append(events, e)
+	}
+
+	return events, rows.Err()
+}
+
+// MarkPublished marks the event as published, so it is not
+// returned by Pending anymore. Published events are kept
+// in the outbox table until they are removed by the operator
+func (o *Outbox) MarkPublished(ctx context.Context, id int64) error {
+	_, err := conn(ctx, o.db).ExecContext(
+		ctx,
+		`UPDATE outbox SET published_at = $1 WHERE id = $2`,
+		time.Now().UTC(),
+		id,
+	)
+
+	return err
+}
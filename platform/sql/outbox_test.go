@@ -0,0 +1,35 @@
+package sql_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	domain "github.com/DzananGanic/banner"
+	bannersql "github.com/DzananGanic/banner/platform/sql"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOutboxEmitAndPending(t *testing.T) {
+	outbox := bannersql.NewOutbox(openMigrated(t))
+	ctx := context.Background()
+
+	events := []domain.Event{
+		{Type: domain.EventBannerCreated, BannerID: 1, Actor: "alice", At: time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC), Banner: &domain.Banner{ID: 1, Name: "banner", Version: 1}},
+		{Type: domain.EventBannerActivated, BannerID: 1, At: time.Date(2019, 1, 2, 0, 0, 0, 0, time.UTC), Banner: &domain.Banner{ID: 1, Name: "banner", Version: 1}},
+		{Type: domain.EventBannerDeleted, BannerID: 2, At: time.Date(2019, 1, 3, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, e := range events {
+		assert.Nil(t, outbox.Emit(ctx, e))
+	}
+
+	got, err := outbox.Pending(ctx, 2)
+	assert.Nil(t, err)
+	events[0].ID, events[1].ID, events[2].ID = 1, 2, 3
+	assert.Equal(t, events[:2], got)
+
+	assert.Nil(t, outbox.MarkPublished(ctx, 1))
+	got, err = outbox.Pending(ctx, 10)
+	assert.Nil(t, err)
+	assert.Equal(t, events[1:], got)
+}
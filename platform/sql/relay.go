@@ -0,0 +1,88 @@
+package sql
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	domain "github.com/DzananGanic/banner"
+)
+
+// DefaultRelayInterval is the interval at which the relay looks
+// for the events to publish when no other interval is provided
+const DefaultRelayInterval = time.Second
+
+// relayBatch is the number of events the relay loads at once
+const relayBatch = 100
+
+// NewRelay creates new relay which publishes the events
+// from the outbox to the publisher every interval
+func NewRelay(outbox *Outbox, pub domain.EventPublisher, interval time.Duration) *Relay {
+	if interval <= 0 {
+		interval = DefaultRelayInterval
+	}
+
+	return &Relay{
+		outbox:   outbox,
+		pub:      pub,
+		interval: interval,
+	}
+}
+
+// Relay publishes the outbox events in the order they were emitted.
+// The event is marked as published only after the publisher accepts
+// it, so the events are delivered at least once: the event is
+// published again if the relay stops before marking it, or if
+// multiple relays run on the same outbox
+type Relay struct {
+	outbox   *Outbox
+	pub      domain.EventPublisher
+	interval time.Duration
+}
+
+// Run publishes the pending events every interval until the context
+// is done. The publishing errors are logged, and the event which
+// failed is retried on the next interval before the later ones
+func (r *Relay) Run(ctx context.Context) error {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := r.Flush(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("banner relay: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Flush publishes all pending events and returns the number of the
+// events published. It stops at the first event which fails to publish
+func (r *Relay) Flush(ctx context.Context) (int, error) {
+	var n int
+	for {
+		events, err := r.outbox.Pending(ctx, relayBatch)
+		if err != nil {
+			return n, err
+		}
+
+		for _, e := range events {
+			if err := r.pub.Publish(ctx, e); err != nil {
+				return n, fmt.Errorf("publishing event %d: %w", e.ID, err)
+			}
+			if err := r.outbox.MarkPublished(ctx, e.ID); err != nil {
+				return n, fmt.Errorf("marking event %d published: %w", e.ID, err)
+			}
+			n++
+		}
+
+		if len(events) < relayBatch {
+			return n, nil
+		}
+	}
+}
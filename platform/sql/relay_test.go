@@ -0,0 +1,50 @@
+package sql_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	domain "github.com/DzananGanic/banner"
+	"github.com/DzananGanic/banner/mock"
+	bannersql "github.com/DzananGanic/banner/platform/sql"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRelayFlush(t *testing.T) {
+	outbox := bannersql.NewOutbox(openMigrated(t))
+	ctx := context.Background()
+	for _, id := range []domain.BannerID{1, 2, 3} {
+		assert.Nil(t, outbox.Emit(ctx, domain.Event{Type: domain.EventBannerCreated, BannerID: id, At: time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)}))
+	}
+
+	var published []domain.BannerID
+	failing := domain.BannerID(2)
+	pub := &mock.EventPublisher{
+		PublishFn: func(ctx context.Context, e domain.Event) error {
+			if e.BannerID == failing {
+				return errors.New("unavailable")
+			}
+			published = append(published, e.BannerID)
+			return nil
+		},
+	}
+	relay := bannersql.NewRelay(outbox, pub, 0)
+
+	// the events after the failed one wait for it to keep the order
+	n, err := relay.Flush(ctx)
+	assert.NotNil(t, err)
+	assert.Equal(t, 1, n)
+	assert.Equal(t, []domain.BannerID{1}, published)
+
+	failing = 0
+	n, err = relay.Flush(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, n)
+	assert.Equal(t, []domain.BannerID{1, 2, 3}, published)
+
+	n, err = relay.Flush(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, n)
+}
@@ -0,0 +1,157 @@
+// Package sql contains database/sql implementations of the
+// domain repositories. It works with any database/sql driver
+// for one of the supported dialects, and carries its own
+// versioned schema migrations
+package sql
+
+import (
+	"database/sql"
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Dialect represents the SQL dialect of the underlying database
+type Dialect string
+
+const (
+	// Postgres is the dialect used for PostgreSQL databases
+	Postgres Dialect = "postgres"
+
+	// SQLite is the dialect used for SQLite databases
+	SQLite Dialect = "sqlite"
+)
+
+//go:embed migrations
+var migrations embed.FS
+
+// migration represents a single versioned schema change
+type migration struct {
+	version int
+	name    string
+	query   string
+}
+
+// Migrate applies all migrations for the given dialect which
+// were not applied yet. Every migration runs in its own transaction
+// and is recorded in the schema_migrations table
+func Migrate(db *sql.DB, d Dialect) error {
+	ms, err := loadMigrations(d)
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (
+		version INTEGER PRIMARY KEY,
+		applied_at TIMESTAMP NOT NULL
+	)`)
+	if err != nil {
+		return err
+	}
+
+	applied, err := appliedVersions(db)
+	if err != nil {
+		return err
+	}
+
+	for _, m := range ms {
+		if applied[m.version] {
+			continue
+		}
+
+		err = applyMigration(db, m)
+		if err != nil {
+			return fmt.Errorf("applying migration %s: %w", m.name, err)
+		}
+	}
+
+	return nil
+}
+
+func loadMigrations(d Dialect) ([]migration, error) {
+	dir := path.Join("migrations", string(d))
+
+	entries, err := fs.ReadDir(migrations, dir)
+	if err != nil {
+		return nil, fmt.Errorf("unsupported dialect %q", d)
+	}
+
+	var ms []migration
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".sql") {
+			continue
+		}
+
+		// migration file names are prefixed with their version,
+		// e.g. 0001_create_banners.sql
+		prefix := strings.SplitN(e.Name(), "_", 2)[0]
+		version, err := strconv.Atoi(prefix)
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration file name %s", e.Name())
+		}
+
+		query, err := fs.ReadFile(migrations, path.Join(dir, e.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		ms = append(ms, migration{
+			version: version,
+			name:    e.Name(),
+			query:   string(query),
+		})
+	}
+
+	sort.Slice(ms, func(i, j int) bool {
+		return ms[i].version < ms[j].version
+	})
+
+	return ms, nil
+}
+
+func appliedVersions(db *sql.DB) (map[int]bool, error) {
+	rows, err := db.Query(`SELECT version FROM schema_migrations`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := make(map[int]bool)
+	for rows.Next() {
+		var v int
+		if err := rows.Scan(&v); err != nil {
+			return nil, err
+		}
+		applied[v] = true
+	}
+
+	return applied, rows.Err()
+}
+
+func applyMigration(db *sql.DB, m migration) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(m.query)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(
+		`INSERT INTO schema_migrations (version, applied_at) VALUES ($1, $2)`,
+		m.version, time.Now().UTC(),
+	)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,62 @@
+package sql_test
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	bannersql "github.com/DzananGanic/banner/platform/sql"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMigrate(t *testing.T) {
+	db := openSQLite(t)
+
+	assert.Nil(t, bannersql.Migrate(db, bannersql.SQLite))
+	// running migrations again must be a no-op
+	assert.Nil(t, bannersql.Migrate(db, bannersql.SQLite))
+
+	var count int
+	err := db.QueryRow(`SELECT COUNT(*) FROM schema_migrations`).Scan(&count)
+	assert.Nil(t, err)
+	assert.Equal(t, 10, count)
+}
+
+func TestMigrateUnsupportedDialect(t *testing.T) {
+	db := openSQLite(t)
+
+	err := bannersql.Migrate(db, bannersql.Dialect("oracle"))
+	assert.NotNil(t, err)
+}
+
+// openSQLite opens a fresh SQLite database in a temporary directory
+func openSQLite(t *testing.T) *sql.DB {
+	t.Helper()
+
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "banner.db"))
+	if err != nil {
+		t.Fatalf("failed to open sqlite database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	return db
+}
+
+// newBannerDB returns banner repository on top of freshly migrated SQLite database
+func newBannerDB(t *testing.T) *bannersql.BannerDB {
+	t.Helper()
+
+	return bannersql.NewBannerDB(openMigrated(t))
+}
+
+func openMigrated(t *testing.T) *sql.DB {
+	t.Helper()
+
+	db := openSQLite(t)
+	if err := bannersql.Migrate(db, bannersql.SQLite); err != nil {
+		t.Fatalf("failed to migrate sqlite database: %v", err)
+	}
+
+	return db
+}
@@ -0,0 +1,60 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+)
+
+// NewTransactor creates new transactor whose transactions
+// span the repositories created on top of the same database
+func NewTransactor(db *sql.DB) *Transactor {
+	return &Transactor{db: db}
+}
+
+// Transactor represents database/sql transactor implementation
+type Transactor struct {
+	db *sql.DB
+}
+
+// txKey is the context key of the transaction, the one
+// of the other database is ignored by the repositories
+type txKey struct {
+	db *sql.DB
+}
+
+// WithinTx runs the function in the transaction carried by the context
+// it is passed. The function called within the transaction already
+// running on the same database joins it instead of starting new one
+func (t *Transactor) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{t.db}).(*sql.Tx); ok {
+		return fn(ctx)
+	}
+
+	tx, err := t.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := fn(context.WithValue(ctx, txKey{t.db}, tx)); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// querier is implemented by both *sql.DB and *sql.Tx
+type querier interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// conn returns the transaction on the database carried
+// by the context, or the database if there is none
+func conn(ctx context.Context, db *sql.DB) querier {
+	if tx, ok := ctx.Value(txKey{db}).(*sql.Tx); ok {
+		return tx
+	}
+	return db
+}
@@ -0,0 +1,61 @@
+package sql_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	domain "github.com/DzananGanic/banner"
+	bannersql "github.com/DzananGanic/banner/platform/sql"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransactor(t *testing.T) {
+	db := openMigrated(t)
+	tx := bannersql.NewTransactor(db)
+	bdb := bannersql.NewBannerDB(db)
+	outbox := bannersql.NewOutbox(db)
+	ctx := context.Background()
+	b := domain.Banner{
+		Name:                  "banner",
+		CreatedAt:             time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC),
+		ScheduledDisplayingAt: time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC),
+		ExpiresAt:             time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	save := func(ctx context.Context) error {
+		id, err := bdb.SaveContext(ctx, b)
+		if err != nil {
+			return err
+		}
+		return outbox.Emit(ctx, domain.Event{Type: domain.EventBannerCreated, BannerID: id, At: b.CreatedAt})
+	}
+
+	errFailed := errors.New("failed")
+	err := tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := save(ctx); err != nil {
+			return err
+		}
+		return errFailed
+	})
+	assert.Equal(t, errFailed, err)
+	banners, err := bdb.ListContext(ctx)
+	assert.Nil(t, err)
+	assert.Empty(t, banners)
+	pending, err := outbox.Pending(ctx, 10)
+	assert.Nil(t, err)
+	assert.Empty(t, pending)
+
+	// the nested call joins the transaction already running
+	err = tx.WithinTx(ctx, func(ctx context.Context) error {
+		return tx.WithinTx(ctx, save)
+	})
+	assert.Nil(t, err)
+	banners, err = bdb.ListContext(ctx)
+	assert.Nil(t, err)
+	assert.Len(t, banners, 1)
+	pending, err = outbox.Pending(ctx, 10)
+	assert.Nil(t, err)
+	assert.Len(t, pending, 1)
+	assert.Equal(t, banners[0].ID, pending[0].BannerID)
+}
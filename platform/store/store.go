@@ -0,0 +1,128 @@
+// Package store opens the banner repositories selected
+// by configuration, so that commands share the same
+// set of supported backends
+package store
+
+import (
+	"database/sql"
+	"fmt"
+
+	domain "github.com/DzananGanic/banner"
+	"github.com/DzananGanic/banner/platform/file"
+	"github.com/DzananGanic/banner/platform/memory"
+	bannerredis "github.com/DzananGanic/banner/platform/redis"
+	bannersql "github.com/DzananGanic/banner/platform/sql"
+	_ "github.com/lib/pq"
+	_ "github.com/mattn/go-sqlite3"
+	goredis "github.com/redis/go-redis/v9"
+)
+
+// Kinds lists supported banner store kinds
+var Kinds = []string{"memory", "file", "sqlite", "postgres"}
+
+// Stores holds the repositories of the opened store
+type Stores struct {
+	Banners domain.BannerDB
+
+	// Audit is stored in the database for sqlite and postgres
+	// stores, next to the banners for file store, and in
+	// process memory for memory store
+	Audit domain.AuditStore
+
+	// Tx, Events and Outbox are set only for sqlite and postgres
+	// stores. Events is the Outbox the events are emitted to, which
+	// keeps them until they are published by the Outbox relay
+	Tx     domain.Transactor
+	Events domain.EventEmitter
+	Outbox *bannersql.Outbox
+}
+
+// Open opens the repositories of the given store kind and returns
+// them together with the function which closes them. For sqlite
+// and postgres dsn is the data source name and schema is migrated
+// on open, while for file store it is the data directory
+func Open(kind, dsn string) (*Stores, func() error, error) {
+	switch kind {
+	case "memory":
+		return &Stores{
+			Banners: memory.NewBannerDB(),
+			Audit:   memory.NewAuditStore(),
+		}, func() error { return nil }, nil
+	case "file":
+		if dsn == "" {
+			dsn = "banners"
+		}
+		db, err := file.NewBannerDB(dsn)
+		if err != nil {
+			return nil, nil, err
+		}
+		audit, err := file.NewAuditStore(dsn)
+		if err != nil {
+			db.Close()
+			return nil, nil, err
+		}
+		closeAll := func() error {
+			err := audit.Close()
+			if dberr := db.Close(); err == nil {
+				err = dberr
+			}
+			return err
+		}
+		return &Stores{Banners: db, Audit: audit}, closeAll, nil
+	case "sqlite":
+		return openSQL("sqlite3", bannersql.SQLite, dsn)
+	case "postgres":
+		return openSQL("postgres", bannersql.Postgres, dsn)
+	}
+
+	return nil, nil, fmt.Errorf("unknown store %q", kind)
+}
+
+// OpenActiveBannerProvider returns Redis active banner provider if
+// redis address is set, and in-memory provider otherwise
+func OpenActiveBannerProvider(redisAddr string) domain.ActiveBannerProvider {
+	if redisAddr == "" {
+		return memory.NewActiveBannerProvider()
+	}
+
+	return bannerredis.NewActiveBannerProvider(
+		goredis.NewClient(&goredis.Options{Addr: redisAddr}),
+		bannerredis.DefaultKey,
+	)
+}
+
+// OpenEventPublisher returns Redis stream event publisher
+// if redis address is set, and nil otherwise
+func OpenEventPublisher(redisAddr, stream string) domain.EventPublisher {
+	if redisAddr == "" {
+		return nil
+	}
+
+	return bannerredis.NewEventPublisher(
+		goredis.NewClient(&goredis.Options{Addr: redisAddr}),
+		stream,
+	)
+}
+
+func openSQL(driver string, dialect bannersql.Dialect, dsn string) (*Stores, func() error, error) {
+	conn, err := sql.Open(driver, dsn)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = bannersql.Migrate(conn, dialect)
+	if err != nil {
+		conn.Close()
+		return nil, nil, err
+	}
+
+	outbox := bannersql.NewOutbox(conn)
+
+	return &Stores{
+		Banners: bannersql.NewBannerDB(conn),
+		Audit:   bannersql.NewAuditStore(conn),
+		Tx:      bannersql.NewTransactor(conn),
+		Events:  outbox,
+		Outbox:  outbox,
+	}, conn.Close, nil
+}
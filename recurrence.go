@@ -0,0 +1,371 @@
+package domain
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Frequency represents how often the recurring banner is displayed
+type Frequency string
+
+const (
+	// Daily is the frequency of the banner displayed every day
+	Daily Frequency = "DAILY"
+
+	// Weekly is the frequency of the banner displayed every week
+	Weekly Frequency = "WEEKLY"
+)
+
+// Date represents the calendar date, without the time zone
+type Date struct {
+	Year  int
+	Month time.Month
+	Day   int
+}
+
+// DateOf returns the date of t in its own location
+func DateOf(t time.Time) Date {
+	y, m, d := t.Date()
+	return Date{Year: y, Month: m, Day: d}
+}
+
+// String returns the date in YYYYMMDD format
+func (d Date) String() string {
+	return fmt.Sprintf("%04d%02d%02d", d.Year, d.Month, d.Day)
+}
+
+// AddDays returns the date the given number of days after d
+func (d Date) AddDays(n int) Date {
+	return DateOf(time.Date(d.Year, d.Month, d.Day+n, 0, 0, 0, 0, time.UTC))
+}
+
+// daysSince returns the number of days from the date from to d
+func (d Date) daysSince(from Date) int {
+	a := time.Date(d.Year, d.Month, d.Day, 0, 0, 0, 0, time.UTC)
+	b := time.Date(from.Year, from.Month, from.Day, 0, 0, 0, 0, time.UTC)
+	return int(a.Sub(b).Hours() / 24)
+}
+
+// Recurrence represents the recurring display windows of the banner,
+// e.g. 11:00 to 14:00 on weekdays. The windows are wall clock times in
+// the banner time zone, so they follow DST, and only the windows within
+// the banner display period, [ScheduledDisplayingAt, ExpiresAt), count
+type Recurrence struct {
+	Frequency Frequency
+
+	// Interval is the number of days or weeks between the
+	// occurrences, counted from the start of display period.
+	// Zero is the same as one, i.e. every day or week
+	Interval int
+
+	// Weekdays lists the days of the weekly recurrence, all
+	// of which are displayed in the week of the occurrence
+	Weekdays []time.Weekday
+
+	// StartHour and StartMinute are the wall clock time
+	// at which the display window starts
+	StartHour   int
+	StartMinute int
+
+	// Duration is the length of the display window in wall
+	// clock time, it may reach into the next day
+	Duration time.Duration
+
+	// Except lists the dates whose windows are not displayed,
+	// dates are those on which the windows start
+	Except []Date
+}
+
+// Clone returns the deep copy of the recurrence
+func (r *Recurrence) Clone() *Recurrence {
+	if r == nil {
+		return nil
+	}
+
+	c := *r
+	c.Weekdays = append([]time.Weekday(nil), r.Weekdays...)
+	c.Except = append([]Date(nil), r.Except...)
+
+	return &c
+}
+
+// IsActive checks whether now falls into one of the display windows,
+// which are resolved in the given location. Start is the beginning
+// of the display period, from which the intervals are counted
+func (r *Recurrence) IsActive(now, start time.Time, loc *time.Location) bool {
+	now = now.In(loc)
+	today := DateOf(now)
+	first := DateOf(start.In(loc))
+
+	// windows which started on previous days may still be open
+	for i := 0; i < r.span(); i++ {
+		day := today.AddDays(-i)
+		if !r.occursOn(day, first) {
+			continue
+		}
+
+		w := r.window(day, loc)
+		if !now.Before(w.From) && now.Before(w.To) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Windows returns the display windows which intersect [from, to),
+// clipped to it, in chronological order. Start is the beginning of
+// display period, from which the intervals are counted
+func (r *Recurrence) Windows(from, to, start time.Time, loc *time.Location) []Interval {
+	first := DateOf(start.In(loc))
+	last := DateOf(to.In(loc))
+
+	var windows []Interval
+	for day := DateOf(from.In(loc)).AddDays(1 - r.span()); day.daysSince(last) <= 0; day = day.AddDays(1) {
+		if !r.occursOn(day, first) {
+			continue
+		}
+
+		w := r.window(day, loc)
+		if w.From.Before(from) {
+			w.From = from
+		}
+		if w.To.After(to) {
+			w.To = to
+		}
+		if w.From.Before(w.To) {
+			windows = append(windows, w)
+		}
+	}
+
+	return windows
+}
+
+// span returns the number of days the window
+// reaches into, counting the one it starts on
+func (r *Recurrence) span() int {
+	start := time.Duration(r.StartHour)*time.Hour + time.Duration(r.StartMinute)*time.Minute
+	return int((start+r.Duration)/(24*time.Hour)) + 1
+}
+
+// window returns the display window which starts on the given day
+func (r *Recurrence) window(day Date, loc *time.Location) Interval {
+	from := WallClock{Year: day.Year, Month: day.Month, Day: day.Day, Hour: r.StartHour, Minute: r.StartMinute}
+	to := WallClockOf(from.naive().Add(r.Duration))
+
+	return Interval{From: from.In(loc), To: to.In(loc)}
+}
+
+// occursOn checks whether the window starts on the given day
+func (r *Recurrence) occursOn(day, first Date) bool {
+	for _, d := range r.Except {
+		if d == day {
+			return false
+		}
+	}
+
+	interval := r.Interval
+	if interval < 1 {
+		interval = 1
+	}
+
+	days := day.daysSince(first)
+	if days < 0 {
+		return false
+	}
+
+	switch r.Frequency {
+	case Daily:
+		return days%interval == 0
+	case Weekly:
+		// weeks are counted from monday of the first week
+		firstWeekday := (int(time.Date(first.Year, first.Month, first.Day, 0, 0, 0, 0, time.UTC).Weekday()) + 6) % 7
+		if ((days+firstWeekday)/7)%interval != 0 {
+			return false
+		}
+
+		weekday := time.Date(day.Year, day.Month, day.Day, 0, 0, 0, 0, time.UTC).Weekday()
+		for _, w := range r.Weekdays {
+			if w == weekday {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+var weekdayCodes = map[time.Weekday]string{
+	time.Monday:    "MO",
+	time.Tuesday:   "TU",
+	time.Wednesday: "WE",
+	time.Thursday:  "TH",
+	time.Friday:    "FR",
+	time.Saturday:  "SA",
+	time.Sunday:    "SU",
+}
+
+// String returns the recurrence in RRULE-like format, e.g.
+// FREQ=WEEKLY;BYDAY=MO,TU,WE,TH,FR;BYHOUR=11;BYMINUTE=0;DURATION=PT3H
+func (r *Recurrence) String() string {
+	parts := []string{"FREQ=" + string(r.Frequency)}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if len(r.Weekdays) > 0 {
+		days := make([]string, 0, len(r.Weekdays))
+		for _, w := range r.Weekdays {
+			days = append(days, weekdayCodes[w])
+		}
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+	parts = append(parts,
+		"BYHOUR="+strconv.Itoa(r.StartHour),
+		"BYMINUTE="+strconv.Itoa(r.StartMinute),
+		"DURATION="+formatDuration(r.Duration),
+	)
+	if len(r.Except) > 0 {
+		dates := make([]string, 0, len(r.Except))
+		for _, d := range r.Except {
+			dates = append(dates, d.String())
+		}
+		parts = append(parts, "EXDATE="+strings.Join(dates, ","))
+	}
+
+	return strings.Join(parts, ";")
+}
+
+// ParseRecurrence parses the recurrence in RRULE-like format, which
+// consists of FREQ (DAILY or WEEKLY), INTERVAL, BYDAY, BYHOUR,
+// BYMINUTE, DURATION (e.g. PT3H30M) and EXDATE (YYYYMMDD dates)
+// parts, e.g. FREQ=DAILY;BYHOUR=11;DURATION=PT3H;EXDATE=20190610
+func ParseRecurrence(s string) (*Recurrence, error) {
+	r := &Recurrence{}
+	for _, part := range strings.Split(s, ";") {
+		if part == "" {
+			continue
+		}
+
+		key, value, ok := strings.Cut(part, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid recurrence part %q", part)
+		}
+
+		var err error
+		switch strings.ToUpper(key) {
+		case "FREQ":
+			r.Frequency = Frequency(strings.ToUpper(value))
+		case "INTERVAL":
+			r.Interval, err = strconv.Atoi(value)
+		case "BYDAY":
+			r.Weekdays, err = parseWeekdays(value)
+		case "BYHOUR":
+			r.StartHour, err = strconv.Atoi(value)
+		case "BYMINUTE":
+			r.StartMinute, err = strconv.Atoi(value)
+		case "DURATION":
+			r.Duration, err = parseDuration(value)
+		case "EXDATE":
+			r.Except, err = parseDates(value)
+		default:
+			return nil, fmt.Errorf("unsupported recurrence part %q", key)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid recurrence %s %q", key, value)
+		}
+	}
+	if r.Frequency == "" {
+		return nil, fmt.Errorf("recurrence %q must have FREQ", s)
+	}
+
+	return r, nil
+}
+
+func parseWeekdays(s string) ([]time.Weekday, error) {
+	var days []time.Weekday
+	for _, code := range strings.Split(s, ",") {
+		found := false
+		for w, c := range weekdayCodes {
+			if strings.EqualFold(c, code) {
+				days = append(days, w)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown weekday %q", code)
+		}
+	}
+
+	return days, nil
+}
+
+func parseDates(s string) ([]Date, error) {
+	var dates []Date
+	for _, v := range strings.Split(s, ",") {
+		t, err := time.Parse("20060102", v)
+		if err != nil {
+			return nil, err
+		}
+		dates = append(dates, DateOf(t))
+	}
+
+	return dates, nil
+}
+
+// formatDuration formats the duration in ISO 8601 format, e.g. PT3H30M
+func formatDuration(d time.Duration) string {
+	if d == 0 {
+		return "PT0S"
+	}
+
+	s := "PT"
+	if h := d / time.Hour; h > 0 {
+		s += strconv.Itoa(int(h)) + "H"
+		d -= h * time.Hour
+	}
+	if m := d / time.Minute; m > 0 {
+		s += strconv.Itoa(int(m)) + "M"
+		d -= m * time.Minute
+	}
+	if sec := d / time.Second; sec > 0 {
+		s += strconv.Itoa(int(sec)) + "S"
+	}
+
+	return s
+}
+
+// parseDuration parses the time part of ISO 8601 duration, e.g. PT3H30M
+func parseDuration(s string) (time.Duration, error) {
+	rest, ok := strings.CutPrefix(strings.ToUpper(s), "PT")
+	if !ok || rest == "" {
+		return 0, fmt.Errorf("invalid duration %q", s)
+	}
+
+	var d time.Duration
+	for rest != "" {
+		i := strings.IndexAny(rest, "HMS")
+		if i <= 0 {
+			return 0, fmt.Errorf("invalid duration %q", s)
+		}
+
+		n, err := strconv.Atoi(rest[:i])
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q", s)
+		}
+
+		switch rest[i] {
+		case 'H':
+			d += time.Duration(n) * time.Hour
+		case 'M':
+			d += time.Duration(n) * time.Minute
+		case 'S':
+			d += time.Duration(n) * time.Second
+		}
+		rest = rest[i+1:]
+	}
+
+	return d, nil
+}
@@ -0,0 +1,220 @@
+package domain_test
+
+import (
+	"testing"
+	"time"
+
+	domain "github.com/DzananGanic/banner"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBannerIsInDisplayPeriodRecurrence(t *testing.T) {
+	lunch := domain.Banner{
+		ScheduledDisplayingAt: time.Date(2019, 3, 24, 23, 0, 0, 0, time.UTC),
+		ExpiresAt:             time.Date(2019, 4, 30, 22, 0, 0, 0, time.UTC),
+		TimeZone:              "Europe/Sarajevo",
+		Recurrence: &domain.Recurrence{
+			Frequency: domain.Weekly,
+			Weekdays:  []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday},
+			StartHour: 11,
+			Duration:  3 * time.Hour,
+			Except:    []domain.Date{{Year: 2019, Month: time.April, Day: 19}},
+		},
+	}
+
+	everyOtherDay := domain.Banner{
+		ScheduledDisplayingAt: time.Date(2019, 3, 25, 0, 0, 0, 0, time.UTC),
+		ExpiresAt:             time.Date(2019, 4, 30, 0, 0, 0, 0, time.UTC),
+		Recurrence: &domain.Recurrence{
+			Frequency: domain.Daily,
+			Interval:  2,
+			StartHour: 9,
+			Duration:  time.Hour,
+		},
+	}
+
+	everyOtherMonday := domain.Banner{
+		ScheduledDisplayingAt: time.Date(2019, 3, 27, 0, 0, 0, 0, time.UTC),
+		ExpiresAt:             time.Date(2019, 4, 30, 0, 0, 0, 0, time.UTC),
+		Recurrence: &domain.Recurrence{
+			Frequency: domain.Weekly,
+			Interval:  2,
+			Weekdays:  []time.Weekday{time.Monday},
+			Duration:  24 * time.Hour,
+		},
+	}
+
+	lateNight := domain.Banner{
+		ScheduledDisplayingAt: time.Date(2019, 3, 1, 0, 0, 0, 0, time.UTC),
+		ExpiresAt:             time.Date(2019, 4, 30, 0, 0, 0, 0, time.UTC),
+		TimeZone:              "Europe/Sarajevo",
+		Recurrence: &domain.Recurrence{
+			Frequency: domain.Daily,
+			StartHour: 22,
+			Duration:  4 * time.Hour,
+		},
+	}
+
+	cases := []struct {
+		name   string
+		banner domain.Banner
+		now    time.Time
+		want   bool
+	}{
+		{
+			name:   "test weekday window in standard time",
+			banner: lunch,
+			now:    time.Date(2019, 3, 29, 10, 30, 0, 0, time.UTC),
+			want:   true,
+		},
+		{
+			name:   "test weekday window follows summer time",
+			banner: lunch,
+			now:    time.Date(2019, 4, 1, 9, 30, 0, 0, time.UTC),
+			want:   true,
+		},
+		{
+			name:   "test after weekday window in summer time",
+			banner: lunch,
+			now:    time.Date(2019, 4, 1, 12, 30, 0, 0, time.UTC),
+			want:   false,
+		},
+		{
+			name:   "test weekend",
+			banner: lunch,
+			now:    time.Date(2019, 3, 30, 10, 30, 0, 0, time.UTC),
+			want:   false,
+		},
+		{
+			name:   "test excluded date",
+			banner: lunch,
+			now:    time.Date(2019, 4, 19, 9, 30, 0, 0, time.UTC),
+			want:   false,
+		},
+		{
+			name:   "test window before display period",
+			banner: lunch,
+			now:    time.Date(2019, 3, 22, 10, 30, 0, 0, time.UTC),
+			want:   false,
+		},
+		{
+			name:   "test window after expiration",
+			banner: lunch,
+			now:    time.Date(2019, 5, 2, 9, 30, 0, 0, time.UTC),
+			want:   false,
+		},
+		{
+			name:   "test daily interval day off",
+			banner: everyOtherDay,
+			now:    time.Date(2019, 3, 26, 9, 30, 0, 0, time.UTC),
+			want:   false,
+		},
+		{
+			name:   "test daily interval day on",
+			banner: everyOtherDay,
+			now:    time.Date(2019, 3, 27, 9, 30, 0, 0, time.UTC),
+			want:   true,
+		},
+		{
+			name:   "test weekly interval week off",
+			banner: everyOtherMonday,
+			now:    time.Date(2019, 4, 1, 12, 0, 0, 0, time.UTC),
+			want:   false,
+		},
+		{
+			name:   "test weekly interval week on",
+			banner: everyOtherMonday,
+			now:    time.Date(2019, 4, 8, 12, 0, 0, 0, time.UTC),
+			want:   true,
+		},
+		{
+			name:   "test window reaching into next day",
+			banner: lateNight,
+			now:    time.Date(2019, 3, 12, 0, 30, 0, 0, time.UTC),
+			want:   true,
+		},
+		{
+			name:   "test window ends early when clocks go forward",
+			banner: lateNight,
+			now:    time.Date(2019, 3, 31, 1, 30, 0, 0, time.UTC),
+			want:   false,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			assert.Equal(t, c.want, c.banner.IsInDisplayPeriod(c.now))
+		})
+	}
+}
+
+func TestParseRecurrence(t *testing.T) {
+	cases := []struct {
+		name    string
+		rule    string
+		want    *domain.Recurrence
+		wantErr bool
+	}{
+		{
+			name: "test weekly rule",
+			rule: "FREQ=WEEKLY;BYDAY=MO,FR;BYHOUR=11;BYMINUTE=30;DURATION=PT2H30M;EXDATE=20190610,20190614",
+			want: &domain.Recurrence{
+				Frequency:   domain.Weekly,
+				Weekdays:    []time.Weekday{time.Monday, time.Friday},
+				StartHour:   11,
+				StartMinute: 30,
+				Duration:    150 * time.Minute,
+				Except: []domain.Date{
+					{Year: 2019, Month: time.June, Day: 10},
+					{Year: 2019, Month: time.June, Day: 14},
+				},
+			},
+		},
+		{
+			name: "test daily rule with interval",
+			rule: "freq=daily;interval=3;duration=PT1H",
+			want: &domain.Recurrence{
+				Frequency: domain.Daily,
+				Interval:  3,
+				Duration:  time.Hour,
+			},
+		},
+		{
+			name:    "test missing frequency",
+			rule:    "BYHOUR=11;DURATION=PT1H",
+			wantErr: true,
+		},
+		{
+			name:    "test unknown weekday",
+			rule:    "FREQ=WEEKLY;BYDAY=XX;DURATION=PT1H",
+			wantErr: true,
+		},
+		{
+			name:    "test invalid duration",
+			rule:    "FREQ=DAILY;DURATION=3h",
+			wantErr: true,
+		},
+		{
+			name:    "test unsupported part",
+			rule:    "FREQ=DAILY;COUNT=3",
+			wantErr: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got, err := domain.ParseRecurrence(c.rule)
+			if c.wantErr {
+				assert.NotNil(t, err)
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.Equal(t, c.want, got)
+
+			again, err := domain.ParseRecurrence(got.String())
+			assert.Nil(t, err)
+			assert.Equal(t, got, again)
+		})
+	}
+}
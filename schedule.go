@@ -0,0 +1,205 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// Location returns the time zone of the banner,
+// which is UTC for the banner without one
+func (b *Banner) Location() (*time.Location, error) {
+	if b.TimeZone == "" {
+		return time.UTC, nil
+	}
+
+	loc, err := time.LoadLocation(b.TimeZone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid time zone %q: %w", b.TimeZone, err)
+	}
+
+	return loc, nil
+}
+
+// location returns the time zone of the banner, or UTC if it is
+// invalid. The time zone is validated when the banner is saved,
+// so invalid one may only come from the outdated tz database
+func (b *Banner) location() *time.Location {
+	loc, err := b.Location()
+	if err != nil {
+		return time.UTC
+	}
+
+	return loc
+}
+
+// Interval represents the time range [From, To)
+type Interval struct {
+	From time.Time
+	To   time.Time
+}
+
+// DisplayWindows returns the intervals within [from, to) during
+// which the banner is displayed, in chronological order
+func (b *Banner) DisplayWindows(from, to time.Time) []Interval {
+	if b.ScheduledDisplayingAt.After(from) {
+		from = b.ScheduledDisplayingAt
+	}
+	if b.ExpiresAt.Before(to) {
+		to = b.ExpiresAt
+	}
+	if !from.Before(to) {
+		return nil
+	}
+
+	if b.Recurrence == nil {
+		return []Interval{{From: from, To: to}}
+	}
+
+	return b.Recurrence.Windows(from, to, b.ScheduledDisplayingAt, b.location())
+}
+
+// Overlap returns the first interval, from the given time on, during
+// which both banners are displayed, and false if there is none
+func (b *Banner) Overlap(other *Banner, from time.Time) (Interval, bool) {
+	to := b.ExpiresAt
+	if other.ExpiresAt.Before(to) {
+		to = other.ExpiresAt
+	}
+
+	bw := b.DisplayWindows(from, to)
+	ow := other.DisplayWindows(from, to)
+	for i, j := 0, 0; i < len(bw) && j < len(ow); {
+		overlap := bw[i]
+		if ow[j].From.After(overlap.From) {
+			overlap.From = ow[j].From
+		}
+		if ow[j].To.Before(overlap.To) {
+			overlap.To = ow[j].To
+		}
+		if overlap.From.Before(overlap.To) {
+			return overlap, true
+		}
+
+		// the window which ends first can not overlap the rest
+		if bw[i].To.Before(ow[j].To) {
+			i++
+		} else {
+			j++
+		}
+	}
+
+	return Interval{}, false
+}
+
+// CheckNormalized checks that the banner instants are normalized to
+// UTC, as repositories store them, so that the behaviour does not
+// depend on the time zone of the server which created the banner
+func (b *Banner) CheckNormalized() error {
+	for _, t := range []struct {
+		name string
+		t    time.Time
+	}{
+		{"created at", b.CreatedAt},
+		{"scheduled displaying at", b.ScheduledDisplayingAt},
+		{"expires at", b.ExpiresAt},
+	} {
+		if t.t.Location() != time.UTC {
+			return fmt.Errorf("%s must be in UTC, got %s", t.name, t.t.Location())
+		}
+	}
+
+	return nil
+}
+
+// wallClockLayouts lists the accepted formats of the wall clock time
+var wallClockLayouts = []string{
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+}
+
+// WallClock represents the date and time as shown on the wall
+// clock, without the time zone, e.g. Monday 2019-06-03 09:00
+type WallClock struct {
+	Year   int
+	Month  time.Month
+	Day    int
+	Hour   int
+	Minute int
+	Second int
+}
+
+// ParseWallClock parses the wall clock time,
+// e.g. 2019-06-03T09:00 or 2019-06-03 09:00:00
+func ParseWallClock(s string) (WallClock, error) {
+	for _, layout := range wallClockLayouts {
+		t, err := time.Parse(layout, s)
+		if err == nil {
+			return WallClockOf(t), nil
+		}
+	}
+
+	return WallClock{}, fmt.Errorf("invalid wall clock time %q, expected YYYY-MM-DDThh:mm[:ss]", s)
+}
+
+// WallClockOf returns the wall clock time of t in its own location
+func WallClockOf(t time.Time) WallClock {
+	return WallClock{
+		Year:   t.Year(),
+		Month:  t.Month(),
+		Day:    t.Day(),
+		Hour:   t.Hour(),
+		Minute: t.Minute(),
+		Second: t.Second(),
+	}
+}
+
+// String returns the wall clock time in YYYY-MM-DDThh:mm:ss format
+func (w WallClock) String() string {
+	return w.naive().Format(wallClockLayouts[0])
+}
+
+// IsZero checks whether the wall clock time is not set
+func (w WallClock) IsZero() bool {
+	return w == WallClock{}
+}
+
+// In resolves the wall clock time in the given location into the
+// instant. Resolution does not depend on the platform and is stable
+// across DST transitions: the time skipped when the clocks go forward
+// is moved forward by the length of the transition, e.g. 02:30
+// becomes 03:30, and the time repeated when the clocks go back
+// resolves to its first occurrence
+func (w WallClock) In(loc *time.Location) time.Time {
+	naive := w.naive()
+
+	// transitions never happen twice a day, so the offsets
+	// in effect a day before and a day after are the only
+	// offsets the wall clock time may have
+	_, before := naive.Add(-24 * time.Hour).In(loc).Zone()
+	_, after := naive.Add(24 * time.Hour).In(loc).Zone()
+
+	var resolved time.Time
+	for _, offset := range []int{before, after} {
+		t := naive.Add(-time.Duration(offset) * time.Second).In(loc)
+		if WallClockOf(t) != w {
+			continue
+		}
+		if resolved.IsZero() || t.Before(resolved) {
+			resolved = t
+		}
+	}
+	if !resolved.IsZero() {
+		return resolved
+	}
+
+	// the wall clock time has been skipped, so it is resolved
+	// with the offset in effect before the transition
+	return naive.Add(-time.Duration(before) * time.Second).In(loc)
+}
+
+// naive returns the wall clock time as if it were in UTC
+func (w WallClock) naive() time.Time {
+	return time.Date(w.Year, w.Month, w.Day, w.Hour, w.Minute, w.Second, 0, time.UTC)
+}
@@ -0,0 +1,177 @@
+package domain_test
+
+import (
+	"testing"
+	"time"
+
+	domain "github.com/DzananGanic/banner"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWallClockIn(t *testing.T) {
+	sarajevo, err := time.LoadLocation("Europe/Sarajevo")
+	if err != nil {
+		t.Fatalf("failed to load sarajevo location: %v", err)
+	}
+
+	cases := []struct {
+		name      string
+		wallClock string
+		want      time.Time
+	}{
+		{
+			name:      "test summer time",
+			wallClock: "2019-06-03T09:00",
+			want:      time.Date(2019, 6, 3, 7, 0, 0, 0, time.UTC),
+		},
+		{
+			name:      "test standard time",
+			wallClock: "2019-12-02T09:00",
+			want:      time.Date(2019, 12, 2, 8, 0, 0, 0, time.UTC),
+		},
+		{
+			name:      "test time skipped when clocks go forward is moved forward",
+			wallClock: "2019-03-31T02:30",
+			want:      time.Date(2019, 3, 31, 1, 30, 0, 0, time.UTC),
+		},
+		{
+			name:      "test time repeated when clocks go back resolves to first occurrence",
+			wallClock: "2019-10-27T02:30",
+			want:      time.Date(2019, 10, 27, 0, 30, 0, 0, time.UTC),
+		},
+		{
+			name:      "test day of transition before it happens",
+			wallClock: "2019-03-31 01:59:59",
+			want:      time.Date(2019, 3, 31, 0, 59, 59, 0, time.UTC),
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			w, err := domain.ParseWallClock(c.wallClock)
+			assert.Nil(t, err)
+
+			got := w.In(sarajevo)
+			assert.True(t, c.want.Equal(got), "want %s, got %s", c.want, got.UTC())
+		})
+	}
+}
+
+func TestParseWallClock(t *testing.T) {
+	w, err := domain.ParseWallClock("2019-06-03T09:00")
+	assert.Nil(t, err)
+	assert.Equal(t, domain.WallClock{Year: 2019, Month: time.June, Day: 3, Hour: 9}, w)
+	assert.Equal(t, "2019-06-03T09:00:00", w.String())
+
+	_, err = domain.ParseWallClock("2019-06-03T09:00:00+02:00")
+	assert.NotNil(t, err)
+}
+
+func TestBannerCheckNormalized(t *testing.T) {
+	b := domain.Banner{
+		CreatedAt:             time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC),
+		ScheduledDisplayingAt: time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC),
+		ExpiresAt:             time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	assert.Nil(t, b.CheckNormalized())
+
+	b.ExpiresAt = time.Date(2020, 1, 1, 0, 0, 0, 0, time.FixedZone("CET", 3600))
+	assert.NotNil(t, b.CheckNormalized())
+}
+
+func TestBannerLocation(t *testing.T) {
+	loc, err := (&domain.Banner{}).Location()
+	assert.Nil(t, err)
+	assert.Equal(t, time.UTC, loc)
+
+	loc, err = (&domain.Banner{TimeZone: "Europe/Sarajevo"}).Location()
+	assert.Nil(t, err)
+	assert.Equal(t, "Europe/Sarajevo", loc.String())
+
+	_, err = (&domain.Banner{TimeZone: "Mars/Olympus_Mons"}).Location()
+	assert.NotNil(t, err)
+}
+
+func TestBannerOverlap(t *testing.T) {
+	period := func(from, to int) domain.Banner {
+		return domain.Banner{
+			ScheduledDisplayingAt: time.Date(2019, 1, from, 0, 0, 0, 0, time.UTC),
+			ExpiresAt:             time.Date(2019, 1, to, 0, 0, 0, 0, time.UTC),
+		}
+	}
+	daily := func(b domain.Banner, hour int, d time.Duration) domain.Banner {
+		b.Recurrence = &domain.Recurrence{Frequency: domain.Daily, StartHour: hour, Duration: d}
+		return b
+	}
+	now := time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		name         string
+		a            domain.Banner
+		b            domain.Banner
+		wantInterval domain.Interval
+		wantOK       bool
+	}{
+		{
+			name: "test overlapping periods",
+			a:    period(1, 10),
+			b:    period(5, 20),
+			wantInterval: domain.Interval{
+				From: time.Date(2019, 1, 5, 0, 0, 0, 0, time.UTC),
+				To:   time.Date(2019, 1, 10, 0, 0, 0, 0, time.UTC),
+			},
+			wantOK: true,
+		},
+		{
+			name:   "test adjacent periods",
+			a:      period(1, 10),
+			b:      period(10, 20),
+			wantOK: false,
+		},
+		{
+			name:   "test overlap in the past",
+			a:      domain.Banner{ScheduledDisplayingAt: now.AddDate(0, -1, 0), ExpiresAt: now},
+			b:      domain.Banner{ScheduledDisplayingAt: now.AddDate(0, -1, 0), ExpiresAt: now},
+			wantOK: false,
+		},
+		{
+			name:   "test disjoint recurring windows",
+			a:      daily(period(1, 10), 11, 3*time.Hour),
+			b:      daily(period(1, 10), 18, 2*time.Hour),
+			wantOK: false,
+		},
+		{
+			name: "test overlapping recurring windows",
+			a:    daily(period(3, 10), 11, 3*time.Hour),
+			b:    daily(period(1, 10), 13, 2*time.Hour),
+			wantInterval: domain.Interval{
+				From: time.Date(2019, 1, 3, 13, 0, 0, 0, time.UTC),
+				To:   time.Date(2019, 1, 3, 14, 0, 0, 0, time.UTC),
+			},
+			wantOK: true,
+		},
+		{
+			name: "test recurring window within period",
+			a:    daily(period(1, 10), 22, 4*time.Hour),
+			b:    period(4, 20),
+			wantInterval: domain.Interval{
+				From: time.Date(2019, 1, 4, 0, 0, 0, 0, time.UTC),
+				To:   time.Date(2019, 1, 4, 2, 0, 0, 0, time.UTC),
+			},
+			wantOK: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			interval, ok := c.a.Overlap(&c.b, now)
+			assert.Equal(t, c.wantOK, ok)
+			assert.Equal(t, c.wantInterval, interval)
+
+			// overlap is symmetric
+			interval, ok = c.b.Overlap(&c.a, now)
+			assert.Equal(t, c.wantOK, ok)
+			assert.Equal(t, c.wantInterval, interval)
+		})
+	}
+}